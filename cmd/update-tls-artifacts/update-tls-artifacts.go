@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/openshift/origin/pkg/cmd/update-tls-artifacts/generate-from-cluster"
 	"github.com/openshift/origin/pkg/cmd/update-tls-artifacts/generate-owners"
 
 	"github.com/openshift/library-go/pkg/serviceability"
@@ -35,6 +36,7 @@ func main() {
 
 	root.AddCommand(
 		generate_owners.NewGenerateOwnershipCommand(streams),
+		generate_from_cluster.NewGenerateFromClusterCommand(streams),
 	)
 
 	f := flag.CommandLine.Lookup("v")
@@ -0,0 +1,244 @@
+package certcollection
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openshift/api/annotations"
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/library-go/pkg/certs/cert-inspection/certgraphanalysis"
+	"github.com/openshift/library-go/pkg/certs/cert-inspection/certgraphapi"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+
+	"github.com/openshift/origin/pkg/cmd/update-tls-artifacts/generate-owners/tlsmetadatadefaults"
+	"github.com/openshift/origin/pkg/cmd/update-tls-artifacts/generate-owners/tlsmetadatainterfaces"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/test/extended/util"
+	"github.com/openshift/origin/test/extended/util/image"
+)
+
+const certInspectResultFile = "/tmp/shared/pkiList.json"
+
+var (
+	//go:embed manifests/namespace.yaml
+	namespaceYaml []byte
+	//go:embed manifests/serviceaccount.yaml
+	serviceAccountYaml []byte
+	//go:embed manifests/rolebinding-privileged.yaml
+	roleBindingPrivilegedYaml []byte
+	//go:embed manifests/clusterrolebinding-nodelist.yaml
+	roleBindingNodeReaderYaml []byte
+	//go:embed manifests/pod.yaml
+	podYaml []byte
+)
+
+// RawTLSArtifactFilename is the name raw data collected from a cluster is stored under in tls/raw-data.
+func RawTLSArtifactFilename(jobType *platformidentification.JobType, featureSet configv1.FeatureSet) string {
+	featureSetString := string(featureSet)
+	if len(featureSetString) == 0 {
+		featureSetString = "Default"
+	}
+	return fmt.Sprintf(
+		"raw-tls-artifacts-%s-%s-%s-%s-%s.json",
+		jobType.Topology,
+		jobType.Architecture,
+		jobType.Platform,
+		jobType.Network,
+		strings.ToLower(featureSetString),
+	)
+}
+
+// ControlPlaneNodes returns the nodes whose IPs are rewritten in collected certificates.
+func ControlPlaneNodes(ctx context.Context, kubeClient kubernetes.Interface) ([]*corev1.Node, error) {
+	controlPlaneLabel := labels.SelectorFromSet(map[string]string{"node-role.kubernetes.io/control-plane": ""})
+	nodeList, err := kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: controlPlaneLabel.String()})
+	if err != nil {
+		return nil, err
+	}
+	masters := []*corev1.Node{}
+	for i := range nodeList.Items {
+		masters = append(masters, &nodeList.Items[i])
+	}
+	return masters, nil
+}
+
+// GatherCertsFromPlatformNamespaces collects the secrets and configmaps holding TLS artifacts in platform namespaces,
+// along with every annotation the default requirements inspect.
+func GatherCertsFromPlatformNamespaces(ctx context.Context, kubeClient kubernetes.Interface, masters []*corev1.Node) (*certgraphapi.PKIList, error) {
+	annotationsToCollect := []string{annotations.OpenShiftComponent}
+	for _, currRequirement := range tlsmetadatadefaults.GetDefaultTLSRequirements() {
+		annotationRequirement, ok := currRequirement.(tlsmetadatainterfaces.AnnotationRequirement)
+		if ok {
+			annotationsToCollect = append(annotationsToCollect, annotationRequirement.GetAnnotationName())
+		}
+	}
+
+	return certgraphanalysis.GatherCertsFromPlatformNamespaces(ctx, kubeClient,
+		certgraphanalysis.SkipRevisioned,
+		certgraphanalysis.SkipHashed,
+		certgraphanalysis.ElideProxyCADetails,
+		certgraphanalysis.RewriteNodeIPs(masters),
+		certgraphanalysis.CollectAnnotations(annotationsToCollect...),
+	)
+}
+
+// FetchOnDiskCertificates runs `openshift-tests collect-disk-certificates` from testPullSpec in a privileged pod on
+// every node and merges the results.
+func FetchOnDiskCertificates(ctx context.Context, kubeClient kubernetes.Interface, podRESTConfig *rest.Config, nodeList []*corev1.Node, testPullSpec string) (*certgraphapi.PKIList, error) {
+	namespace, err := createNamespace(ctx, kubeClient)
+	if err != nil {
+		return nil, err
+	}
+	defer kubeClient.CoreV1().Namespaces().Delete(ctx, namespace, metav1.DeleteOptions{})
+
+	err = createServiceAccount(ctx, kubeClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+	nodeReaderCRB, err := createRBACBindings(ctx, kubeClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+	defer kubeClient.RbacV1().ClusterRoleBindings().Delete(ctx, nodeReaderCRB, metav1.DeleteOptions{})
+
+	pauseImage := image.LocationFor("registry.k8s.io/e2e-test-images/agnhost:2.47")
+	podNameOnNode, err := createPods(ctx, kubeClient, namespace, nodeList, testPullSpec, pauseImage)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &certgraphapi.PKIList{}
+	errs := []error{}
+	for _, node := range nodeList {
+		nodePKIList, err := fetchNodePKIList(ctx, kubeClient, podRESTConfig, podNameOnNode, node)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		ret = certgraphanalysis.MergePKILists(ctx, ret, nodePKIList)
+	}
+	if len(errs) != 0 {
+		return ret, utilerrors.NewAggregate(errs)
+	}
+
+	return ret, nil
+}
+
+func createNamespace(ctx context.Context, kubeClient kubernetes.Interface) (string, error) {
+	namespaceObj := resourceread.ReadNamespaceV1OrDie(namespaceYaml)
+
+	client := kubeClient.CoreV1().Namespaces()
+	actualNamespace, err := client.Create(ctx, namespaceObj, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return "", fmt.Errorf("error creating namespace: %v", err)
+	}
+	return actualNamespace.Name, nil
+}
+
+func createServiceAccount(ctx context.Context, kubeClient kubernetes.Interface, namespace string) error {
+	serviceAccountObj := resourceread.ReadServiceAccountV1OrDie(serviceAccountYaml)
+	serviceAccountObj.Namespace = namespace
+	client := kubeClient.CoreV1().ServiceAccounts(namespace)
+	_, err := client.Create(ctx, serviceAccountObj, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("error creating service account: %v", err)
+	}
+	return nil
+}
+
+func createRBACBindings(ctx context.Context, kubeClient kubernetes.Interface, namespace string) (string, error) {
+	privilegedRoleBindingObj := resourceread.ReadRoleBindingV1OrDie(roleBindingPrivilegedYaml)
+	privilegedRoleBindingObj.Namespace = namespace
+
+	client := kubeClient.RbacV1().RoleBindings(namespace)
+	_, err := client.Create(ctx, privilegedRoleBindingObj, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return "", fmt.Errorf("error creating hostaccess SCC CRB: %v", err)
+	}
+
+	nodeReaderRoleBindingObj := resourceread.ReadClusterRoleBindingV1OrDie(roleBindingNodeReaderYaml)
+	nodeReaderRoleBindingObj.Subjects[0].Namespace = namespace
+	crbClient := kubeClient.RbacV1().ClusterRoleBindings()
+	nodeReaderObj, err := crbClient.Create(ctx, nodeReaderRoleBindingObj, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return "", fmt.Errorf("error creating node reader CRB: %v", err)
+	}
+	return nodeReaderObj.Name, nil
+}
+
+type podToNodeMap map[string]*corev1.Pod
+
+func createPods(ctx context.Context, kubeClient kubernetes.Interface, namespace string, nodeList []*corev1.Node, testImagePullSpec, pauseImagePullSpec string) (podToNodeMap, error) {
+	podOnNode := podToNodeMap{}
+
+	client := kubeClient.CoreV1().Pods(namespace)
+	podTemplate := resourceread.ReadPodV1OrDie(podYaml)
+	for _, node := range nodeList {
+		podObj := podTemplate.DeepCopy()
+		podObj.Namespace = namespace
+		podObj.Spec.NodeName = node.Name
+		podObj.Spec.InitContainers[0].Image = testImagePullSpec
+		podObj.Spec.Containers[0].Image = pauseImagePullSpec
+
+		actualPod, err := client.Create(ctx, podObj, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			return podOnNode, fmt.Errorf("error creating pod on node %s: %v", node.Name, err)
+		}
+
+		timeLimitedCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+
+		if _, watchErr := watchtools.UntilWithSync(timeLimitedCtx,
+			cache.NewListWatchFromClient(
+				kubeClient.CoreV1().RESTClient(), "pods", namespace, fields.OneTermEqualSelector("metadata.name", actualPod.Name)),
+			&corev1.Pod{},
+			nil,
+			func(event watch.Event) (bool, error) {
+				pod := event.Object.(*corev1.Pod)
+				if pod.Status.Phase == corev1.PodRunning {
+					podOnNode[node.Name] = pod
+					return true, nil
+				}
+				return false, nil
+			},
+		); watchErr != nil {
+			return podOnNode, fmt.Errorf("pod %s in namespace %s didn't start: %v", actualPod.Name, namespace, watchErr)
+		}
+	}
+	return podOnNode, nil
+}
+
+func fetchNodePKIList(ctx context.Context, kubeClient kubernetes.Interface, podRESTConfig *rest.Config, podOnNode podToNodeMap, node *corev1.Node) (*certgraphapi.PKIList, error) {
+	pkiList := &certgraphapi.PKIList{}
+
+	pod, ok := podOnNode[node.Name]
+	if !ok {
+		return pkiList, fmt.Errorf("failed to find node %s in pod map %v", node.Name, podOnNode)
+	}
+
+	output, err := util.ExecInPodWithResult(kubeClient.CoreV1(), podRESTConfig, pod.Namespace, pod.Name, "pause", []string{"/bin/cat", certInspectResultFile})
+	if err != nil {
+		return pkiList, fmt.Errorf("failed to fetch file %s from pod %s/%s node %s: %v", certInspectResultFile, pod.Namespace, pod.Name, node.Name, err)
+	}
+
+	err = json.Unmarshal([]byte(output), pkiList)
+	if err != nil {
+		return pkiList, fmt.Errorf("failed to unmarshal file %s on node %s: %v", certInspectResultFile, node.Name, err)
+	}
+
+	return pkiList, nil
+}
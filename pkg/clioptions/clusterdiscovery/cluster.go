@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -70,6 +71,13 @@ type ClusterConfiguration struct {
 
 	// IsNoOptionalCapabilities indicates the cluster has no optional capabilities enabled
 	HasNoOptionalCapabilities bool
+
+	// EnabledCapabilities are the ClusterVersion capabilities of the cluster.  Tests labeled [Capability:NAME] are
+	// skipped when NAME is not enabled.  Nil when unknown, which skips nothing.
+	EnabledCapabilities []string `json:",omitempty"`
+
+	// Architecture is the kubernetes.io/arch of the control plane nodes, used for [Skipped:Arch/ARCH] labels.
+	Architecture string `json:",omitempty"`
 }
 
 func (c *ClusterConfiguration) ToJSONString() string {
@@ -173,6 +181,12 @@ func LoadConfig(state *ClusterState) (*ClusterConfiguration, error) {
 	}
 
 	config.HasNoOptionalCapabilities = len(state.OptionalCapabilities) == 0
+	if state.OptionalCapabilities != nil {
+		config.EnabledCapabilities = []string{}
+		for _, capability := range state.OptionalCapabilities {
+			config.EnabledCapabilities = append(config.EnabledCapabilities, string(capability))
+		}
+	}
 	// after introducing MachineAPI capability it's needed to be always enabled
 	// to make it compatable to CI.
 	// We need this code in order to keep tests working with no capabilities
@@ -190,6 +204,11 @@ func LoadConfig(state *ClusterState) (*ClusterConfiguration, error) {
 	if zones.Len() > 0 {
 		config.Zone = zones.List()[0]
 	}
+	for _, nodes := range []*corev1.NodeList{state.Masters, state.NonMasters} {
+		if nodes != nil && len(nodes.Items) > 0 && len(config.Architecture) == 0 {
+			config.Architecture = nodes.Items[0].Labels["kubernetes.io/arch"]
+		}
+	}
 	if len(state.NonMasters.Items) == 0 {
 		config.NumNodes = len(state.NonMasters.Items)
 	} else {
@@ -275,57 +294,81 @@ func LoadConfig(state *ClusterState) (*ClusterConfiguration, error) {
 // MatchFn returns a function that tests if a named function should be run based on
 // the cluster configuration
 func (c *ClusterConfiguration) MatchFn() func(string) bool {
-	var skips []string
-	skips = append(skips, fmt.Sprintf("[Skipped:%s]", c.ProviderName))
+	return func(name string) bool {
+		return len(c.SkipReason(name)) == 0
+	}
+}
+
+var capabilityRegex = regexp.MustCompile(`\[Capability:([^]]*)\]`)
+
+// SkipReason returns why the named test does not apply to a cluster with this configuration, or an empty string if it
+// does.
+func (c *ClusterConfiguration) SkipReason(name string) string {
+	type skip struct {
+		label  string
+		reason string
+	}
+	skips := []skip{
+		{label: fmt.Sprintf("[Skipped:%s]", c.ProviderName), reason: fmt.Sprintf("platform is %s", c.ProviderName)},
+	}
 
 	if c.IsIBMROKS {
-		skips = append(skips, "[Skipped:ibmroks]")
+		skips = append(skips, skip{label: "[Skipped:ibmroks]", reason: "cluster is IBM ROKS"})
 	}
 	if c.NetworkPlugin != "" {
-		skips = append(skips, fmt.Sprintf("[Skipped:Network/%s]", c.NetworkPlugin))
+		skips = append(skips, skip{label: fmt.Sprintf("[Skipped:Network/%s]", c.NetworkPlugin), reason: fmt.Sprintf("network type is %s", c.NetworkPlugin)})
 		if c.NetworkPluginMode != "" {
-			skips = append(skips, fmt.Sprintf("[Skipped:Network/%s/%s]", c.NetworkPlugin, c.NetworkPluginMode))
+			skips = append(skips, skip{label: fmt.Sprintf("[Skipped:Network/%s/%s]", c.NetworkPlugin, c.NetworkPluginMode), reason: fmt.Sprintf("network type is %s in %s mode", c.NetworkPlugin, c.NetworkPluginMode)})
 		}
 	}
+	if len(c.Architecture) > 0 {
+		skips = append(skips, skip{label: fmt.Sprintf("[Skipped:Arch/%s]", c.Architecture), reason: fmt.Sprintf("architecture is %s", c.Architecture)})
+	}
 
 	if c.Disconnected {
-		skips = append(skips, "[Skipped:Disconnected]")
+		skips = append(skips, skip{label: "[Skipped:Disconnected]", reason: "cluster is disconnected"})
 	}
 
 	if c.IsProxied {
-		skips = append(skips, "[Skipped:Proxy]")
+		skips = append(skips, skip{label: "[Skipped:Proxy]", reason: "cluster is accessed through a proxy"})
 	}
 
 	if c.SingleReplicaTopology {
-		skips = append(skips, "[Skipped:SingleReplicaTopology]")
+		skips = append(skips, skip{label: "[Skipped:SingleReplicaTopology]", reason: "control plane topology is SingleReplica"})
 	}
 
 	if !c.HasIPv4 {
-		skips = append(skips, "[Feature:Networking-IPv4]")
+		skips = append(skips, skip{label: "[Feature:Networking-IPv4]", reason: "cluster has no IPv4 service network"})
 	}
 	if !c.HasIPv6 {
-		skips = append(skips, "[Feature:Networking-IPv6]")
+		skips = append(skips, skip{label: "[Feature:Networking-IPv6]", reason: "cluster has no IPv6 service network"})
 	}
 	if !c.HasIPv4 || !c.HasIPv6 {
 		// lack of "]" is intentional; this matches multiple tags
-		skips = append(skips, "[Feature:IPv6DualStack")
+		skips = append(skips, skip{label: "[Feature:IPv6DualStack", reason: "cluster is not dual stack"})
 	}
 
 	if !c.HasSCTP {
-		skips = append(skips, "[Feature:SCTPConnectivity]")
+		skips = append(skips, skip{label: "[Feature:SCTPConnectivity]", reason: "cluster does not support SCTP"})
 	}
 
 	if c.HasNoOptionalCapabilities {
-		skips = append(skips, "[Skipped:NoOptionalCapabilities]")
+		skips = append(skips, skip{label: "[Skipped:NoOptionalCapabilities]", reason: "cluster has no optional capabilities enabled"})
+	}
+
+	for _, skip := range skips {
+		if strings.Contains(name, skip.label) {
+			return fmt.Sprintf("%s and the test is labeled %s", skip.reason, skip.label)
+		}
 	}
 
-	matchFn := func(name string) bool {
-		for _, skip := range skips {
-			if strings.Contains(name, skip) {
-				return false
+	if c.EnabledCapabilities != nil {
+		enabled := sets.NewString(c.EnabledCapabilities...)
+		for _, match := range capabilityRegex.FindAllStringSubmatch(name, -1) {
+			if !enabled.Has(match[1]) {
+				return fmt.Sprintf("capability %s is not enabled in ClusterVersion", match[1])
 			}
 		}
-		return true
 	}
-	return matchFn
+	return ""
 }
@@ -0,0 +1,31 @@
+package clusterdiscovery
+
+import "testing"
+
+func TestClusterConfigurationSkipReason(t *testing.T) {
+	config := &ClusterConfiguration{
+		ProviderName:        "aws",
+		NetworkPlugin:       "OVNKubernetes",
+		HasIPv4:             true,
+		Architecture:        "arm64",
+		EnabledCapabilities: []string{"Build"},
+	}
+	for name, want := range map[string]string{
+		"[sig-foo] applies":                                    "",
+		"[sig-foo] not on aws [Skipped:aws]":                   "platform is aws and the test is labeled [Skipped:aws]",
+		"[sig-foo] not on ovn [Skipped:Network/OVNKubernetes]": "network type is OVNKubernetes and the test is labeled [Skipped:Network/OVNKubernetes]",
+		"[sig-foo] not on arm [Skipped:Arch/arm64]":            "architecture is arm64 and the test is labeled [Skipped:Arch/arm64]",
+		"[sig-foo] ipv6 [Feature:Networking-IPv6]":             "cluster has no IPv6 service network and the test is labeled [Feature:Networking-IPv6]",
+		"[sig-foo] builds [Capability:Build]":                  "",
+		"[sig-foo] console [Capability:Console]":               "capability Console is not enabled in ClusterVersion",
+	} {
+		if got := config.SkipReason(name); got != want {
+			t.Errorf("SkipReason(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	config.EnabledCapabilities = nil
+	if got := config.SkipReason("[sig-foo] console [Capability:Console]"); got != "" {
+		t.Errorf("unknown capabilities should not skip, got %q", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strings"
 
 	clientconfigv1 "github.com/openshift/client-go/config/clientset/versioned"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -55,7 +56,8 @@ func newFeatureGateFilter(ctx context.Context, configClient clientconfigv1.Inter
 	return ret, nil
 }
 
-func (f *featureGateFilter) includeTest(name string) bool {
+// skipReason returns the featuregates of [OCPFeatureGate:NAME] labels that are not enabled in the cluster.
+func (f *featureGateFilter) skipReason(name string) string {
 	featureGates := []string{}
 	matches := featureGateRegex.FindAllStringSubmatch(name, -1)
 	for _, match := range matches {
@@ -66,15 +68,30 @@ func (f *featureGateFilter) includeTest(name string) bool {
 		featureGates = append(featureGates, featureGate)
 	}
 
-	if f.disabled.HasAny(featureGates...) {
-		return false
+	var disabled, unknown []string
+	for _, featureGate := range featureGates {
+		switch {
+		case f.disabled.Has(featureGate):
+			disabled = append(disabled, featureGate)
+		case !f.enabled.Has(featureGate):
+			unknown = append(unknown, featureGate)
+		}
 	}
-
-	return f.enabled.HasAll(featureGates...)
+	switch {
+	case len(disabled) > 0:
+		return fmt.Sprintf("featuregates %s are disabled", strings.Join(disabled, ", "))
+	case len(unknown) > 0:
+		return fmt.Sprintf("featuregates %s are not known to the cluster", strings.Join(unknown, ", "))
+	}
+	return ""
 }
 
-func includeNonFeatureGateTest(name string) bool {
-	return featureGateRegex.FindAllStringSubmatch(name, -1) == nil
+// noFeatureGatesSkipReason skips featuregated tests on clusters that do not report featuregates.
+func noFeatureGatesSkipReason(name string) string {
+	if featureGateRegex.FindAllStringSubmatch(name, -1) == nil {
+		return ""
+	}
+	return "the cluster does not report featuregates"
 }
 
 var (
@@ -3,6 +3,7 @@ package suiteselection
 import (
 	"fmt"
 	"regexp"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/discovery"
@@ -36,16 +37,21 @@ var (
 	apiGroupRegex = regexp.MustCompile(`\[apigroup:([^]]*)\]`)
 )
 
-func (agf *apiGroupFilter) includeTest(name string) bool {
-	apiGroups := []string{}
+// skipReason returns the apigroups of [apigroup:GROUP] labels that the cluster does not serve.
+func (agf *apiGroupFilter) skipReason(name string) string {
+	var missing []string
 	matches := apiGroupRegex.FindAllStringSubmatch(name, -1)
 	for _, match := range matches {
 		if len(match) < 2 {
 			panic(fmt.Errorf("regexp match %v is invalid: len(match) < 2 for %v", match, name))
 		}
 		apigroup := match[1]
-		apiGroups = append(apiGroups, apigroup)
+		if !agf.apiGroups.Has(apigroup) {
+			missing = append(missing, apigroup)
+		}
 	}
-
-	return agf.apiGroups.HasAll(apiGroups...)
+	if len(missing) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("apigroups %s are not served by the cluster", strings.Join(missing, ", "))
 }
@@ -37,6 +37,8 @@ type TestSuiteSelectionFlags struct {
 
 	// Regex allows a selection of a subset of tests
 	Regex string
+	// LabelFilter is a boolean expression over test labels that selects a subset of tests
+	LabelFilter string
 	// MatchFn if set is also used to filter the suite contents
 	MatchFn testginkgo.TestMatchFunc
 
@@ -52,6 +54,7 @@ func NewTestSuiteSelectionFlags(streams genericclioptions.IOStreams) *TestSuiteS
 func (f *TestSuiteSelectionFlags) BindFlags(flags *pflag.FlagSet) {
 	flags.StringVarP(&f.TestFile, "file", "f", f.TestFile, "Create a suite from the newline-delimited test names in this file.")
 	flags.StringVar(&f.Regex, "run", f.Regex, "Regular expression of tests to run.")
+	flags.StringVar(&f.LabelFilter, "label-filter", f.LabelFilter, "Boolean expression over test labels of tests to run, for instance 'sig-network && !Slow && (Feature:EgressIP || apigroup:route.openshift.io)'. Labels are the bracketed tags of test names. With --dry-run, explains why each test of the suite is included or excluded.")
 }
 
 func (f *TestSuiteSelectionFlags) Validate() error {
@@ -69,7 +72,7 @@ func (f *TestSuiteSelectionFlags) SelectSuite(
 	discoveryClientGetter DiscoveryClientGetter,
 	configClientGetter ConfigClientGetter,
 	dryRun bool,
-	additionalMatchFn testginkgo.TestMatchFunc,
+	clusterApplicability testginkgo.TestApplicabilityFunc,
 ) (*testginkgo.TestSuite, error) {
 	var suite *testginkgo.TestSuite
 
@@ -110,8 +113,16 @@ func (f *TestSuiteSelectionFlags) SelectSuite(
 		suite.AddRequiredMatchFunc(re.MatchString)
 	}
 
+	if len(f.LabelFilter) > 0 {
+		labelFilter, err := testginkgo.ParseLabelFilter(f.LabelFilter)
+		if err != nil {
+			return nil, err
+		}
+		suite.LabelFilter = labelFilter
+	}
+
 	suite.AddRequiredMatchFunc(f.MatchFn)
-	suite.AddRequiredMatchFunc(additionalMatchFn)
+	suite.AddApplicabilityCheck("cluster", clusterApplicability)
 
 	// Skip tests with [apigroup:GROUP] labels for apigroups which are not
 	// served by a cluster. E.g. MicroShift is not serving most of the openshift.io
@@ -135,7 +146,7 @@ func (f *TestSuiteSelectionFlags) SelectSuite(
 			if err != nil {
 				return nil, fmt.Errorf("unable to build api group filter: %w", err)
 			}
-			suite.AddRequiredMatchFunc(apiGroupFilter.includeTest)
+			suite.AddApplicabilityCheck("apigroup", apiGroupFilter.skipReason)
 		}
 	}
 
@@ -151,11 +162,11 @@ func (f *TestSuiteSelectionFlags) SelectSuite(
 		case apierrors.IsNotFound(err):
 			// In case we are unable to determine if there is support for feature gates, exclude all featuregated tests
 			// as the test target doesnt comply with preconditions.
-			suite.AddRequiredMatchFunc(includeNonFeatureGateTest)
+			suite.AddApplicabilityCheck("featuregate", noFeatureGatesSkipReason)
 		case err != nil:
 			return nil, fmt.Errorf("unable to build FeatureGate filter: %w", err)
 		default:
-			suite.AddRequiredMatchFunc(featureGateFilter.includeTest)
+			suite.AddApplicabilityCheck("featuregate", featureGateFilter.skipReason)
 		}
 	}
 
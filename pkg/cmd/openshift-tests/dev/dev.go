@@ -27,6 +27,7 @@ func NewDevCommand() *cobra.Command {
 	cmd.AddCommand(
 		newRunAlertInvariantsCommand(),
 		newRunDisruptionInvariantsCommand(),
+		newAnalyzeTestInterferenceCommand(),
 	)
 	return cmd
 }
@@ -0,0 +1,82 @@
+package dev
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/openshift/origin/pkg/testinterference"
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+type testInterferenceOpts struct {
+	options testinterference.Options
+	output  string
+	suggest bool
+}
+
+func newAnalyzeTestInterferenceCommand() *cobra.Command {
+	o := testInterferenceOpts{
+		options: testinterference.NewOptions(),
+		output:  "text",
+	}
+
+	cmd := &cobra.Command{
+		Use:   "analyze-test-interference DIR...",
+		Short: "Find tests that fail more often while specific other tests run concurrently",
+		Long: templates.LongDesc(`
+Find tests that fail more often while specific other tests run concurrently.
+
+Reads the e2e-events_*.json intervals files found below each directory, for instance the
+artifacts of many CI job runs, and pairs the E2ETestStarted and E2ETestFinished intervals
+of each test. For every test that failed, the tests running concurrently with its failures
+are compared to the tests running concurrently with its passes. Pairs that coincide more
+often than chance predicts are listed, most suspicious first.
+`),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("at least one directory of job run artifacts is required")
+			}
+			if o.output != "text" && o.output != "json" {
+				return fmt.Errorf("--output must be text or json")
+			}
+			if err := o.options.Validate(); err != nil {
+				return err
+			}
+
+			runs, err := testinterference.LoadJobRuns(args)
+			if err != nil {
+				return err
+			}
+			if len(runs) == 0 {
+				return fmt.Errorf("no e2e-events_*.json files with test intervals found in %v", args)
+			}
+
+			report := testinterference.Analyze(runs, o.options)
+			if !o.suggest {
+				report.Suggestions = nil
+			}
+			if o.output == "json" {
+				data, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(os.Stdout, string(data))
+				return nil
+			}
+			return report.Print(os.Stdout)
+		},
+	}
+	cmd.Flags().IntVar(&o.options.MinCoFailures, "min-co-failures", o.options.MinCoFailures,
+		"Minimum number of failures of a test that overlapped the suspect.")
+	cmd.Flags().Float64Var(&o.options.MaxPValue, "max-p-value", o.options.MaxPValue,
+		"Maximum probability of the failures overlapping the suspect by chance for the pair to be listed.")
+	cmd.Flags().IntVar(&o.options.SerialThreshold, "serial-threshold", o.options.SerialThreshold,
+		"Number of tests a suspect must interfere with to suggest marking the suspect [Serial] rather than isolating each test.")
+	cmd.Flags().BoolVar(&o.suggest, "suggest", o.suggest,
+		"Suggest [Serial] annotations for suspects that interfere with many tests and for tests that need isolation.")
+	cmd.Flags().StringVarP(&o.output, "output", "o", o.output, "Output format: text or json.")
+	return cmd
+}
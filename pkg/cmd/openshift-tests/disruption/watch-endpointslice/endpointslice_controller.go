@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"sort"
 	"sync"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	discoveryinformers "k8s.io/client-go/informers/discovery/v1"
@@ -36,8 +38,12 @@ type EndpointSliceController struct {
 	scheme             string
 	path               string
 	expectedStatusCode int
-	recorder           monitorapi.RecorderWriter
-	outFile            io.Writer
+	// nodeMatrix skips the endpoints on this node and records the source and target node and zone of every sampler.
+	nodeMatrix bool
+	// maxTargetNodes limits the nodes sampled in nodeMatrix mode, zero samples every other node.
+	maxTargetNodes int
+	recorder       monitorapi.RecorderWriter
+	outFile        io.Writer
 
 	endpointSliceLister discoverylisters.EndpointSliceLister
 	configmapLister     corelisters.ConfigMapLister
@@ -54,6 +60,7 @@ type watcher struct {
 	address                 string
 	port                    string
 	nodeName                string
+	zone                    string
 	newConnectionSampler    *backenddisruption.BackendSampler
 	reusedConnectionSampler *backenddisruption.BackendSampler
 }
@@ -67,6 +74,8 @@ func NewEndpointWatcher(
 	scheme string,
 	path string,
 	expectedStatusCode int,
+	nodeMatrix bool,
+	maxTargetNodes int,
 	recorder monitorapi.RecorderWriter,
 	outFile io.Writer,
 
@@ -82,6 +91,8 @@ func NewEndpointWatcher(
 		scheme:             scheme,
 		path:               path,
 		expectedStatusCode: expectedStatusCode,
+		nodeMatrix:         nodeMatrix,
+		maxTargetNodes:     maxTargetNodes,
 		recorder:           recorder,
 		outFile:            outFile,
 
@@ -168,9 +179,16 @@ func (c *EndpointSliceController) syncEndpointSlice(ctx context.Context, key str
 				if endpoint.NodeName != nil {
 					watchersForCurrEndpoints[address].nodeName = *endpoint.NodeName
 				}
+				if endpoint.Zone != nil {
+					watchersForCurrEndpoints[address].zone = *endpoint.Zone
+				}
 			}
 		}
 	}
+	myZone := ""
+	if c.nodeMatrix {
+		myZone = c.selectNodeMatrixTargets(watchersForCurrEndpoints)
+	}
 	if len(watchersForCurrEndpoints) == 0 {
 		c.removeAllWatchers()
 		return nil
@@ -206,8 +224,16 @@ func (c *EndpointSliceController) syncEndpointSlice(ctx context.Context, key str
 		historicalBackendDisruptionDataForNewConnectionsName := fmt.Sprintf("%s-%v-connections", c.backendPrefix, monitorapi.NewConnectionType)
 		historicalBackendDisruptionDataForReusedConnectionsName := fmt.Sprintf("%s-%v-connections", c.backendPrefix, monitorapi.ReusedConnectionType)
 		intervalLocator := fmt.Sprintf("%s-from-node-%v-to-node-%v-endpoint-%v", c.backendPrefix, c.myNodeName, newWatcher.nodeName, newWatcher.address)
+		newConnectionLocator := monitorapi.NewLocator().LocateDisruptionCheck(historicalBackendDisruptionDataForNewConnectionsName, intervalLocator, monitorapi.NewConnectionType)
+		reusedConnectionLocator := monitorapi.NewLocator().LocateDisruptionCheck(historicalBackendDisruptionDataForReusedConnectionsName, intervalLocator, monitorapi.ReusedConnectionType)
+		if c.nodeMatrix {
+			newConnectionLocator = monitorapi.NewLocator().LocateNodePairDisruptionCheck(historicalBackendDisruptionDataForNewConnectionsName, intervalLocator,
+				c.myNodeName, myZone, newWatcher.nodeName, newWatcher.zone, monitorapi.NewConnectionType)
+			reusedConnectionLocator = monitorapi.NewLocator().LocateNodePairDisruptionCheck(historicalBackendDisruptionDataForReusedConnectionsName, intervalLocator,
+				c.myNodeName, myZone, newWatcher.nodeName, newWatcher.zone, monitorapi.ReusedConnectionType)
+		}
 		newWatcher.newConnectionSampler = backenddisruption.NewSimpleBackendWithLocator(
-			monitorapi.NewLocator().LocateDisruptionCheck(historicalBackendDisruptionDataForNewConnectionsName, intervalLocator, monitorapi.NewConnectionType),
+			newConnectionLocator,
 			url,
 			"",
 			monitorapi.NewConnectionType,
@@ -218,7 +244,7 @@ func (c *EndpointSliceController) syncEndpointSlice(ctx context.Context, key str
 		newWatcher.newConnectionSampler.StartEndpointMonitoring(ctx, c.recorder, nil)
 
 		newWatcher.reusedConnectionSampler = backenddisruption.NewSimpleBackendWithLocator(
-			monitorapi.NewLocator().LocateDisruptionCheck(historicalBackendDisruptionDataForReusedConnectionsName, intervalLocator, monitorapi.ReusedConnectionType),
+			reusedConnectionLocator,
 			url,
 			"",
 			monitorapi.ReusedConnectionType,
@@ -236,6 +262,46 @@ func (c *EndpointSliceController) syncEndpointSlice(ctx context.Context, key str
 	return err
 }
 
+// selectNodeMatrixTargets removes the endpoints on this node, and those on nodes outside of the sampled subset when
+// there are more than maxTargetNodes other nodes.  It returns the zone of this node, if one of its endpoints has it.
+func (c *EndpointSliceController) selectNodeMatrixTargets(watchers map[string]*watcher) string {
+	myZone := ""
+	nodes := sets.New[string]()
+	for address, currWatcher := range watchers {
+		if currWatcher.nodeName == c.myNodeName {
+			myZone = currWatcher.zone
+			delete(watchers, address)
+			continue
+		}
+		nodes.Insert(currWatcher.nodeName)
+	}
+
+	targetNodes := sets.New(nodeMatrixTargets(c.myNodeName, sets.List(nodes), c.maxTargetNodes)...)
+	for address, currWatcher := range watchers {
+		if !targetNodes.Has(currWatcher.nodeName) {
+			delete(watchers, address)
+		}
+	}
+	return myZone
+}
+
+// nodeMatrixTargets returns the nodes myNode samples.  When there are more than maxTargets, the nodes are placed on a
+// ring in name order and each node samples half of maxTargets, rounded down, on either side of it.  Every node is then
+// sampled by as many nodes as it samples, and every sampled pair is sampled in both directions.
+func nodeMatrixTargets(myNode string, otherNodes []string, maxTargets int) []string {
+	if maxTargets <= 0 || len(otherNodes) <= maxTargets {
+		return otherNodes
+	}
+
+	// otherNodes is sorted, the nodes after myNode in the ring come first
+	position := sort.SearchStrings(otherNodes, myNode)
+	ring := append(append([]string{}, otherNodes[position:]...), otherNodes[:position]...)
+	half := max(maxTargets/2, 1)
+	targets := append(append([]string{}, ring[:half]...), ring[len(ring)-half:]...)
+	sort.Strings(targets)
+	return targets
+}
+
 func (c *EndpointSliceController) removeAllWatchers() {
 	c.watcherLock.Lock()
 	defer c.watcherLock.Unlock()
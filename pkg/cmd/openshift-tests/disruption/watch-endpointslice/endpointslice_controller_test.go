@@ -0,0 +1,46 @@
+package watch_endpointslice
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func Test_nodeMatrixTargets(t *testing.T) {
+	if got := nodeMatrixTargets("b", []string{"a", "c"}, 10); !reflect.DeepEqual(got, []string{"a", "c"}) {
+		t.Errorf("expected every other node on small clusters, got %v", got)
+	}
+	if got := nodeMatrixTargets("a", []string{"b", "c", "d", "e", "f"}, 2); !reflect.DeepEqual(got, []string{"b", "f"}) {
+		t.Errorf("expected the neighbors on the ring, got %v", got)
+	}
+	if got := nodeMatrixTargets("a", []string{"b", "c", "d", "e", "f"}, 3); !reflect.DeepEqual(got, []string{"b", "f"}) {
+		t.Errorf("expected an odd limit to be rounded down, got %v", got)
+	}
+
+	var nodes []string
+	for i := 0; i < 25; i++ {
+		nodes = append(nodes, fmt.Sprintf("node-%02d", i))
+	}
+	sampledBy := map[string]int{}
+	for _, node := range nodes {
+		others := sets.List(sets.New(nodes...).Delete(node))
+		targets := nodeMatrixTargets(node, others, 10)
+		if len(targets) != 10 || sets.New(targets...).Has(node) {
+			t.Fatalf("%s: unexpected targets %v", node, targets)
+		}
+		for _, target := range targets {
+			sampledBy[target]++
+			// every sampled pair is sampled in both directions
+			if !sets.New(nodeMatrixTargets(target, sets.List(sets.New(nodes...).Delete(target)), 10)...).Has(node) {
+				t.Errorf("%s samples %s, but not the reverse", node, target)
+			}
+		}
+	}
+	for _, node := range nodes {
+		if sampledBy[node] != 10 {
+			t.Errorf("%s is sampled by %d nodes", node, sampledBy[node])
+		}
+	}
+}
@@ -25,6 +25,8 @@ type WatchEndpointSliceFlags struct {
 	Scheme             string
 	Path               string
 	ExpectedStatusCode int
+	NodeMatrix         bool
+	MaxTargetNodes     int
 	MyNodeName         string
 	StopConfigMapName  string
 
@@ -92,6 +94,8 @@ func (f *WatchEndpointSliceFlags) BindOptions(flags *pflag.FlagSet) {
 	flags.StringVar(&f.Scheme, "request-scheme", f.Scheme, "http or https")
 	flags.StringVar(&f.Path, "request-path", f.Path, "path to request, like /healthz")
 	flags.IntVar(&f.ExpectedStatusCode, "expected-status-code", f.ExpectedStatusCode, "status code to expect from the sampler")
+	flags.BoolVar(&f.NodeMatrix, "node-matrix", f.NodeMatrix, "skip the endpoints on this node and record the source and target node and zone of each sample")
+	flags.IntVar(&f.MaxTargetNodes, "max-target-nodes", f.MaxTargetNodes, "with --node-matrix, the maximum number of other nodes to sample.  0 samples every other node.")
 	f.ConfigFlags.AddFlags(flags)
 	f.OutputFlags.BindFlags(flags)
 }
@@ -110,6 +114,12 @@ func (f *WatchEndpointSliceFlags) Validate() error {
 	if len(f.BackendPrefix) == 0 {
 		return fmt.Errorf("disruption-backend-prefix must be specified")
 	}
+	if f.NodeMatrix && len(f.MyNodeName) == 0 {
+		return fmt.Errorf("my-node-name must be specified with node-matrix")
+	}
+	if f.MaxTargetNodes < 0 {
+		return fmt.Errorf("max-target-nodes must not be negative")
+	}
 
 	return nil
 }
@@ -149,6 +159,8 @@ func (f *WatchEndpointSliceFlags) ToOptions() (*WatchEndpointSliceOptions, error
 		MyNodeName:         f.MyNodeName,
 		BackendPrefix:      f.BackendPrefix,
 		ExpectedStatusCode: f.ExpectedStatusCode,
+		NodeMatrix:         f.NodeMatrix,
+		MaxTargetNodes:     f.MaxTargetNodes,
 		CloseFn:            closeFn,
 		OriginalOutFile:    originalOutStream,
 		IOStreams:          f.IOStreams,
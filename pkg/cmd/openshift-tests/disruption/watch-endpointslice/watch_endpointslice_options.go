@@ -30,6 +30,8 @@ type WatchEndpointSliceOptions struct {
 	Scheme             string
 	Path               string
 	ExpectedStatusCode int
+	NodeMatrix         bool
+	MaxTargetNodes     int
 	StopConfigMapName  string
 
 	OriginalOutFile io.Writer
@@ -65,6 +67,8 @@ func (o *WatchEndpointSliceOptions) Run(ctx context.Context) error {
 		o.Scheme,
 		o.Path,
 		o.ExpectedStatusCode,
+		o.NodeMatrix,
+		o.MaxTargetNodes,
 		recorder,
 		o.OriginalOutFile,
 		namespaceScopedEndpointSliceInformers.EndpointSlices(),
@@ -0,0 +1,51 @@
+package impacted_tests
+
+import (
+	"os"
+
+	"github.com/openshift/origin/pkg/clioptions/clusterdiscovery"
+	testginkgo "github.com/openshift/origin/pkg/test/ginkgo"
+	exutil "github.com/openshift/origin/test/extended/util"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+func NewImpactedTestsCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	o := testginkgo.NewImpactedTestsOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "impacted-tests CHANGED_FILE_OR_PACKAGE...",
+		Short: "List the tests affected by a set of changes",
+		Long: templates.LongDesc(`
+		List the tests affected by a set of changed files or packages
+
+		A test is affected when one of its ginkgo code locations is in a package that is changed or that
+		imports a changed package, directly or not. Changes matching a runAll rule, like the shared helpers
+		in test/extended/util, select every test. Pass "-" to read the changes from standard input, one
+		per line.
+
+		The test names are printed in the format accepted by the --file argument of the run command, for
+		instance:
+
+		    git diff --name-only origin/main | openshift-tests impacted-tests - > tests.txt
+		    openshift-tests run openshift/conformance/parallel --file tests.txt
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// no cluster is needed to list tests, initialize the framework the same way run --dry-run does
+			providerConfig, err := clusterdiscovery.DecodeProvider("", true, false, nil)
+			if err != nil {
+				return err
+			}
+			if err := clusterdiscovery.InitializeTestFramework(exutil.TestContext, providerConfig, true); err != nil {
+				return err
+			}
+			return o.Run(args, os.Stdin)
+		},
+	}
+	o.BindFlags(cmd.Flags())
+	return cmd
+}
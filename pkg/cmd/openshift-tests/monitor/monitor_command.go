@@ -3,6 +3,7 @@ package monitor
 import (
 	"github.com/openshift/origin/pkg/cmd/openshift-tests/monitor/run"
 	summarize_audit_logs "github.com/openshift/origin/pkg/cmd/openshift-tests/monitor/summarize-audit-logs"
+	who_changed "github.com/openshift/origin/pkg/cmd/openshift-tests/monitor/who-changed"
 	"github.com/openshift/origin/pkg/monitor/apiserveravailability"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -17,6 +18,7 @@ func NewMonitorCommand(streams genericclioptions.IOStreams) *cobra.Command {
 		run.NewRunCommand(streams),
 		summarize_audit_logs.AuditLogSummaryCommand(),
 		apiserveravailability.LogSummaryCommand(),
+		who_changed.NewWhoChangedCommand(streams),
 	)
 	return cmd
 }
@@ -13,6 +13,8 @@ import (
 
 	"github.com/openshift/origin/pkg/clioptions/imagesetup"
 	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/logrules"
+	"github.com/openshift/origin/pkg/monitortests/testframework/disruptioncustombackends"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"github.com/openshift/origin/test/extended/util/image"
@@ -33,15 +35,26 @@ type RunMonitorFlags struct {
 	ExactMonitorTests   []string
 	DisableMonitorTests []string
 	FromRepository      string
+	// DisruptionBackendsFile defines additional backends to sample for disruption.
+	DisruptionBackendsFile string
+	// PodNetworkNodeMatrix samples the pod and host network between pairs of nodes.
+	PodNetworkNodeMatrix bool
+	// LogRulesFile defines additional rules turning log lines into intervals.
+	LogRulesFile string
+	// StreamAuditLogs tails the audit logs during the run instead of downloading them at the end.
+	StreamAuditLogs bool
+	// CertificateExpiryWarningDays is how many days before expiry platform certificates are reported.
+	CertificateExpiryWarningDays int
 
 	genericclioptions.IOStreams
 }
 
 func NewRunMonitorOptions(streams genericclioptions.IOStreams, fromRepository string) *RunMonitorFlags {
 	return &RunMonitorFlags{
-		DisplayFromNow: true,
-		IOStreams:      streams,
-		FromRepository: fromRepository,
+		DisplayFromNow:               true,
+		IOStreams:                    streams,
+		FromRepository:               fromRepository,
+		CertificateExpiryWarningDays: 7,
 	}
 }
 
@@ -90,9 +103,25 @@ func (f *RunMonitorFlags) BindFlags(flags *pflag.FlagSet) {
 		fmt.Sprintf("list of exactly which monitors to enable. All others will be disabled.  Current monitors are: [%s]", strings.Join(monitorNames, ", ")))
 	flags.StringSliceVar(&f.DisableMonitorTests, "disable-monitor", f.DisableMonitorTests, "list of monitors to disable.  Defaults for others will be honored.")
 	flags.StringVar(&f.FromRepository, "from-repository", f.FromRepository, "A container image repository to retrieve test images from.")
+	flags.StringVar(&f.DisruptionBackendsFile, "disruption-backends", f.DisruptionBackendsFile, "A YAML file of routes, services and URLs to sample for disruption alongside the built-in backends, see pkg/monitortests/testframework/disruptioncustombackends.")
+	flags.BoolVar(&f.PodNetworkNodeMatrix, "pod-network-node-matrix", f.PodNetworkNodeMatrix, "Run the pod network disruption pollers on every node, sampling up to ten other nodes each, and write a node-by-node heatmap of disruption.")
+	flags.BoolVar(&f.StreamAuditLogs, "stream-audit-logs", f.StreamAuditLogs, "Tail the audit logs of the control plane nodes during the run instead of downloading them at the end, reporting the time ranges that could not be read.")
+	flags.IntVar(&f.CertificateExpiryWarningDays, "certificate-expiry-warning-days", f.CertificateExpiryWarningDays, "Report platform certificates that are within this many days of expiring during the run.")
+	flags.StringVar(&f.LogRulesFile, "log-rules", f.LogRulesFile, "A YAML file of rules turning the lines of node journals and pod logs into intervals, see pkg/monitortestlibrary/logrules.")
 }
 
 func (f *RunMonitorFlags) ToOptions() (*RunMonitorOptions, error) {
+	if len(f.DisruptionBackendsFile) > 0 {
+		if _, err := disruptioncustombackends.BackendsFromFile(f.DisruptionBackendsFile); err != nil {
+			return nil, fmt.Errorf("unable to load --disruption-backends: %w", err)
+		}
+	}
+	if len(f.LogRulesFile) > 0 {
+		if _, err := logrules.RulesFromFile(f.LogRulesFile); err != nil {
+			return nil, fmt.Errorf("unable to load --log-rules: %w", err)
+		}
+	}
+
 	var displayFilterFn monitorapi.EventIntervalMatchesFunc
 	if f.DisplayFromNow {
 		now := time.Now()
@@ -120,9 +149,14 @@ func (f *RunMonitorFlags) ToOptions() (*RunMonitorOptions, error) {
 
 func (f *RunMonitorFlags) getMonitorTestRegistry() (monitortestframework.MonitorTestRegistry, error) {
 	monitorTestInfo := monitortestframework.MonitorTestInitializationInfo{
-		ClusterStabilityDuringTest: monitortestframework.Stable,
-		ExactMonitorTests:          f.ExactMonitorTests,
-		DisableMonitorTests:        f.DisableMonitorTests,
+		ClusterStabilityDuringTest:   monitortestframework.Stable,
+		ExactMonitorTests:            f.ExactMonitorTests,
+		DisableMonitorTests:          f.DisableMonitorTests,
+		DisruptionBackendsFile:       f.DisruptionBackendsFile,
+		PodNetworkNodeMatrix:         f.PodNetworkNodeMatrix,
+		LogRulesFile:                 f.LogRulesFile,
+		StreamAuditLogs:              f.StreamAuditLogs,
+		CertificateExpiryWarningDays: f.CertificateExpiryWarningDays,
 	}
 	return defaultmonitortests.NewMonitorTestsFor(monitorTestInfo)
 }
@@ -0,0 +1,132 @@
+package who_changed
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortests/kubeapiserver/auditloganalyzer"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+type whoChangedOptions struct {
+	ArtifactDir string
+	Namespace   string
+
+	Resource string
+	Name     string
+
+	IOStreams genericclioptions.IOStreams
+}
+
+func NewWhoChangedCommand(ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := &whoChangedOptions{
+		ArtifactDir: ".",
+		IOStreams:   ioStreams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "who-changed RESOURCE/NAME",
+		Short: "List who created, updated, patched or deleted an object during a run",
+		Long: templates.LongDesc(`
+List who created, updated, patched or deleted an object during a run.
+
+Reads the audit-log-object-mutations_*.json files found below --artifact-dir and prints
+the mutating requests the audit log analysis recorded for the object, oldest first, with
+the user, user agent and audit ID of each. Only objects in platform namespaces, cluster
+configuration, and objects e2e test service accounts changed outside their namespace
+are recorded.
+
+The resource matches with or without its group, for instance both configmaps/foo and
+infrastructures.config.openshift.io/cluster.
+`),
+		Example: templates.Examples(`
+		openshift-tests monitor who-changed infrastructures/cluster --artifact-dir=artifacts/
+		openshift-tests monitor who-changed configmaps/kube-root-ca.crt -n openshift-etcd
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(args); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.ArtifactDir, "artifact-dir", o.ArtifactDir, "The directory of run artifacts to search for audit-log-object-mutations_*.json files.")
+	cmd.Flags().StringVarP(&o.Namespace, "namespace", "n", o.Namespace, "The namespace of the object.  No entry matches every namespace.")
+	return cmd
+}
+
+func (o *whoChangedOptions) Complete(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("exactly one RESOURCE/NAME is required")
+	}
+	parts := strings.Split(args[0], "/")
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return fmt.Errorf("%q must be RESOURCE/NAME", args[0])
+	}
+	o.Resource, o.Name = parts[0], parts[1]
+	return nil
+}
+
+func (o *whoChangedOptions) Run() error {
+	found := false
+	mutations := []auditloganalyzer.ObjectMutation{}
+	err := filepath.WalkDir(o.ArtifactDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := filepath.Base(path)
+		if d.IsDir() || !strings.HasPrefix(name, "audit-log-object-mutations") || !strings.HasSuffix(name, ".json") {
+			return nil
+		}
+		found = true
+		fileMutations, err := auditloganalyzer.ReadObjectMutations(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		mutations = append(mutations, auditloganalyzer.FindObjectMutations(fileMutations, o.Resource, o.Namespace, o.Name)...)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("no audit-log-object-mutations_*.json files found in %s", o.ArtifactDir)
+	}
+	if len(mutations) == 0 {
+		fmt.Fprintf(o.IOStreams.Out, "No recorded changes to %s/%s.\n", o.Resource, o.Name)
+		return nil
+	}
+
+	sort.SliceStable(mutations, func(i, j int) bool {
+		return mutations[i].Time.Before(mutations[j].Time)
+	})
+	w := tabwriter.NewWriter(o.IOStreams.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tVERB\tNAMESPACE\tRESOURCE\tUSER\tUSER AGENT\tAUDIT ID")
+	for _, mutation := range mutations {
+		namespace := mutation.Namespace
+		if len(namespace) == 0 {
+			namespace = "<cluster>"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			mutation.Time.UTC().Format(time.RFC3339),
+			mutation.Verb,
+			namespace,
+			mutation.Resource,
+			mutation.User,
+			mutation.UserAgent,
+			mutation.AuditID,
+		)
+	}
+	return w.Flush()
+}
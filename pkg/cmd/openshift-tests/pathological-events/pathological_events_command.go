@@ -0,0 +1,19 @@
+package pathological_events
+
+import (
+	test_matchers "github.com/openshift/origin/pkg/cmd/openshift-tests/pathological-events/test-matchers"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+func NewPathologicalEventsCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "pathological-events",
+		Short:         "Inspect the allowed pathological event matchers.",
+		SilenceErrors: true,
+	}
+	cmd.AddCommand(
+		test_matchers.NewTestMatchersCommand(streams),
+	)
+	return cmd
+}
@@ -0,0 +1,240 @@
+package test_matchers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/monitortestlibrary/pathologicaleventlibrary"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+type TestMatchersFlags struct {
+	IntervalsDir string
+	FilePattern  string
+	MatchersFile string
+	Upgrade      bool
+	Topology     string
+	OutputType   string
+
+	genericclioptions.IOStreams
+}
+
+func NewTestMatchersFlags(streams genericclioptions.IOStreams) *TestMatchersFlags {
+	return &TestMatchersFlags{
+		FilePattern: "e2e-events*.json",
+		Topology:    string(configv1.HighlyAvailableTopologyMode),
+		OutputType:  "text",
+		IOStreams:   streams,
+	}
+}
+
+func NewTestMatchersCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	f := NewTestMatchersFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Report which events each pathological event matcher allows in a directory of interval files.",
+		Long: `
+		Run every registered pathological event matcher against the kube event intervals found in a
+		directory of interval files (typically e2e-events_<timestamp>.json from CI artifacts) and report,
+		per matcher, which pathological events it allowed. Matchers that allowed nothing may be dead,
+		matchers that allowed a lot may be over-broad.
+
+		openshift-tests pathological-events test --intervals-dir ./artifacts --matchers-file my-matchers.yaml
+		`,
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o, err := f.ToOptions()
+			if err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (f *TestMatchersFlags) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&f.IntervalsDir, "intervals-dir", f.IntervalsDir, "Directory searched recursively for interval files.")
+	flags.StringVar(&f.FilePattern, "file-pattern", f.FilePattern, "Glob used to select interval files within --intervals-dir.")
+	flags.StringVar(&f.MatchersFile, "matchers-file", f.MatchersFile, "Optional YAML file of additional matchers to register, in the same format as allowed_pathological_events.yaml.")
+	flags.BoolVar(&f.Upgrade, "upgrade", f.Upgrade, "Use the upgrade set of matchers instead of the universal set.")
+	flags.StringVar(&f.Topology, "topology", f.Topology, "Control plane topology to evaluate topology specific matchers against.")
+	flags.StringVarP(&f.OutputType, "output", "o", f.OutputType, "Type of output: [json,text]")
+}
+
+func (f *TestMatchersFlags) ToOptions() (*TestMatchersOptions, error) {
+	if len(f.IntervalsDir) == 0 {
+		return nil, fmt.Errorf("missing --intervals-dir")
+	}
+	if _, err := filepath.Match(f.FilePattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid --file-pattern: %w", err)
+	}
+	switch f.OutputType {
+	case "json", "text":
+	default:
+		return nil, fmt.Errorf("unknown --output %q", f.OutputType)
+	}
+
+	o := &TestMatchersOptions{
+		IntervalsDir: f.IntervalsDir,
+		FilePattern:  f.FilePattern,
+		Upgrade:      f.Upgrade,
+		Topology:     configv1.TopologyMode(f.Topology),
+		OutputType:   f.OutputType,
+		IOStreams:    f.IOStreams,
+	}
+	if len(f.MatchersFile) > 0 {
+		matchers, err := pathologicaleventlibrary.NewPathologicalEventMatchersFromFile(f.MatchersFile)
+		if err != nil {
+			return nil, err
+		}
+		o.AdditionalMatchers = matchers
+	}
+	return o, nil
+}
+
+type TestMatchersOptions struct {
+	IntervalsDir       string
+	FilePattern        string
+	AdditionalMatchers *pathologicaleventlibrary.PathologicalEventMatchers
+	Upgrade            bool
+	Topology           configv1.TopologyMode
+	OutputType         string
+
+	genericclioptions.IOStreams
+}
+
+func (o *TestMatchersOptions) Run() error {
+	var filenames []string
+	err := filepath.WalkDir(o.IntervalsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if matched, _ := filepath.Match(o.FilePattern, d.Name()); matched {
+			filenames = append(filenames, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(filenames) == 0 {
+		return fmt.Errorf("no files matching %q found in %s", o.FilePattern, o.IntervalsDir)
+	}
+
+	results := pathologicaleventlibrary.NewMatcherTestResults()
+	for _, filename := range filenames {
+		intervals, err := monitorserialization.EventsFromFile(filename)
+		if err != nil {
+			return fmt.Errorf("unable to read %s: %w", filename, err)
+		}
+
+		// Some matchers are built from the final intervals of a run, so the registry is rebuilt per file.
+		registry, err := o.newRegistry(intervals)
+		if err != nil {
+			return err
+		}
+		results.Record(filename, registry, intervals, o.Topology)
+	}
+
+	if o.OutputType == "json" {
+		out, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(o.Out, string(out))
+		return err
+	}
+	return o.printText(len(filenames), results)
+}
+
+func (o *TestMatchersOptions) newRegistry(intervals monitorapi.Intervals) (*pathologicaleventlibrary.AllowedPathologicalEventRegistry, error) {
+	var registry *pathologicaleventlibrary.AllowedPathologicalEventRegistry
+	if o.Upgrade {
+		registry = pathologicaleventlibrary.NewUpgradePathologicalEventMatchers(nil, intervals)
+	} else {
+		registry = pathologicaleventlibrary.NewUniversalPathologicalEventMatchers(nil, intervals)
+	}
+	if o.AdditionalMatchers == nil {
+		return registry, nil
+	}
+	if err := registry.AddPathologicalEventMatchers(o.AdditionalMatchers.Universal); err != nil {
+		return nil, err
+	}
+	if o.Upgrade {
+		if err := registry.AddPathologicalEventMatchers(o.AdditionalMatchers.Upgrade); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+func (o *TestMatchersOptions) printText(numFiles int, results *pathologicaleventlibrary.MatcherTestResults) error {
+	fmt.Fprintf(o.Out, "Evaluated %d matchers against %d interval files.\n\n", len(results.Matchers), numFiles)
+
+	w := tabwriter.NewWriter(o.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "MATCHER\tMATCHED\tALLOWED\tNAMESPACES")
+	for _, result := range results.Sorted() {
+		namespaces := map[string]bool{}
+		for _, event := range result.Allowed {
+			namespaces[event.Namespace] = true
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\n", result.Name, result.Matched, len(result.Allowed), len(namespaces))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, result := range results.Sorted() {
+		if len(result.Allowed) == 0 {
+			continue
+		}
+		fmt.Fprintf(o.Out, "\n%s allowed:\n", result.Name)
+		for _, event := range result.Allowed {
+			fmt.Fprintf(o.Out, "  %s\n", formatEvent(event))
+		}
+	}
+
+	for _, result := range results.Sorted() {
+		if len(result.OverThreshold) == 0 {
+			continue
+		}
+		fmt.Fprintf(o.Out, "\n%s matched but did not allow, repeated more than %d times:\n", result.Name, result.RepeatThresholdOverride)
+		for _, event := range result.OverThreshold {
+			fmt.Fprintf(o.Out, "  %s\n", formatEvent(event))
+		}
+	}
+
+	if unused := results.Unused(); len(unused) > 0 {
+		fmt.Fprintf(o.Out, "\nMatchers that allowed no pathological events:\n  %s\n", strings.Join(unused, "\n  "))
+	}
+	if len(results.Disallowed) > 0 {
+		fmt.Fprintf(o.Out, "\nPathological events no matcher allowed:\n")
+		for _, event := range results.Disallowed {
+			fmt.Fprintf(o.Out, "  %s\n", formatEvent(event))
+		}
+	}
+	return nil
+}
+
+func formatEvent(event pathologicaleventlibrary.MatchedEvent) string {
+	return fmt.Sprintf("%s - reason/%s %s (%d times) [%s]", event.Locator, event.Reason, event.Message, event.Count, event.Source)
+}
@@ -111,6 +111,11 @@ func (o *RunUpgradeSuiteOptions) Run(ctx context.Context) error {
 		UpgradeTargetPayloadImagePullSpec: o.ToImage,
 		ExactMonitorTests:                 o.GinkgoRunSuiteOptions.ExactMonitorTests,
 		DisableMonitorTests:               o.GinkgoRunSuiteOptions.DisableMonitorTests,
+		DisruptionBackendsFile:            o.GinkgoRunSuiteOptions.DisruptionBackendsFile,
+		PodNetworkNodeMatrix:              o.GinkgoRunSuiteOptions.PodNetworkNodeMatrix,
+		LogRulesFile:                      o.GinkgoRunSuiteOptions.LogRulesFile,
+		StreamAuditLogs:                   o.GinkgoRunSuiteOptions.StreamAuditLogs,
+		CertificateExpiryWarningDays:      o.GinkgoRunSuiteOptions.CertificateExpiryWarningDays,
 	}
 
 	o.GinkgoRunSuiteOptions.CommandEnv = o.TestCommandEnvironment()
@@ -28,11 +28,26 @@ func NewRunCommand(streams genericclioptions.IOStreams) *cobra.Command {
 		command with the --file argument. You may also pipe a list of test names, one per line, on
 		standard input by passing "-f -".
 
+		If you specify the --in-cluster-driver argument, the suite is run from a Job in the cluster
+		instead, for clusters this machine cannot reach for the whole run. The output of the Job is
+		streamed back and the --junit-dir contents are copied out when the suite ends.
+
+		When --junit-dir is set, a run-manifest_<timestamp>.json file records the seed the tests were
+		shuffled with, the tests in the order and parallel batches they ran in, and the enabled
+		monitors. Pass it to --replay-manifest to run the same tests in the same way again, for
+		instance to chase failures that depend on test ordering.
+
 		`) + testsuites.SuitesString(testsuites.StandardTestSuites(), "\n\nAvailable test suites:\n\n"),
 
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if f.InClusterDriver {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				return f.runInClusterDriver(ctx, cmd.Flags(), args)
+			}
+
 			o, err := f.ToOptions(args)
 			if err != nil {
 				fmt.Fprintf(f.IOStreams.ErrOut, "error converting to options: %v", err)
@@ -7,6 +7,8 @@ import (
 	"github.com/openshift/origin/pkg/clioptions/kubeconfig"
 	"github.com/openshift/origin/pkg/clioptions/suiteselection"
 	testginkgo "github.com/openshift/origin/pkg/test/ginkgo"
+	"github.com/openshift/origin/pkg/test/inclusterdriver"
+	"github.com/openshift/origin/pkg/testsuites"
 	exutil "github.com/openshift/origin/test/extended/util"
 	"github.com/spf13/pflag"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -22,6 +24,14 @@ type RunSuiteFlags struct {
 
 	FromRepository     string
 	ProviderTypeOrJSON string
+	// SuiteFile defines additional suites that may be selected alongside AvailableSuites.
+	SuiteFile string
+	// ReplayManifest is a run manifest from a previous run whose tests are run again in the same order.
+	ReplayManifest string
+
+	// InClusterDriver runs this command in a Job in the cluster instead of this process.
+	InClusterDriver      bool
+	InClusterDriverImage string
 
 	// Passed to the test process if set
 	UpgradeSuite string
@@ -65,6 +75,10 @@ func (f *RunSuiteFlags) SuiteWithKubeTestInitializationPreSuite() (*clusterdisco
 func (f *RunSuiteFlags) BindFlags(flags *pflag.FlagSet) {
 	flags.StringVar(&f.FromRepository, "from-repository", f.FromRepository, "A container image repository to retrieve test images from.")
 	flags.StringVar(&f.ProviderTypeOrJSON, "provider", f.ProviderTypeOrJSON, "The cluster infrastructure provider. Will automatically default to the correct value.")
+	flags.StringVar(&f.SuiteFile, "suite-file", f.SuiteFile, "A YAML file defining additional test suites that can be selected by name, see pkg/testsuites/README.md.")
+	flags.StringVar(&f.ReplayManifest, "replay-manifest", f.ReplayManifest, "A run-manifest_*.json file from the --junit-dir of a previous run. Runs the same tests in the same order, parallel batches and with the same monitors.")
+	flags.BoolVar(&f.InClusterDriver, "in-cluster-driver", f.InClusterDriver, "Run the suite from a Job in the cluster, streaming its output and copying --junit-dir back when it ends.")
+	flags.StringVar(&f.InClusterDriverImage, "in-cluster-driver-image", inclusterdriver.DefaultImage, "The image containing openshift-tests for --in-cluster-driver.")
 	f.GinkgoRunSuiteOptions.BindFlags(flags)
 	f.TestSuiteSelectionFlags.BindFlags(flags)
 	f.OutputFlags.BindFlags(flags)
@@ -97,13 +111,34 @@ func (f *RunSuiteFlags) ToOptions(args []string) (*RunSuiteOptions, error) {
 	if err != nil {
 		return nil, err
 	}
+	if len(f.ReplayManifest) > 0 {
+		manifest, err := testginkgo.RunManifestFromFile(f.ReplayManifest)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load --replay-manifest: %w", err)
+		}
+		switch {
+		case len(args) == 0:
+			args = []string{manifest.Suite}
+		case args[0] != manifest.Suite:
+			return nil, fmt.Errorf("--replay-manifest is for suite %q, not %q", manifest.Suite, args[0])
+		}
+		ginkgoOptions.ReplayManifest = manifest
+	}
+	availableSuites := f.AvailableSuites
+	if len(f.SuiteFile) > 0 {
+		fileSuites, err := testsuites.TestSuitesFromFile(f.SuiteFile, f.AvailableSuites)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load --suite-file: %w", err)
+		}
+		availableSuites = append(append([]*testginkgo.TestSuite{}, f.AvailableSuites...), fileSuites...)
+	}
 	suite, err := f.TestSuiteSelectionFlags.SelectSuite(
-		f.AvailableSuites,
+		availableSuites,
 		args,
 		kubeconfig.NewDiscoveryGetter(adminRESTConfig),
 		kubeconfig.NewConfigClientGetter(adminRESTConfig),
 		f.GinkgoRunSuiteOptions.DryRun,
-		providerConfig.MatchFn(),
+		providerConfig.SkipReason,
 	)
 	if err != nil {
 		return nil, err
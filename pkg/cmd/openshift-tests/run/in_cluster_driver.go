@@ -0,0 +1,125 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path"
+	"syscall"
+
+	"github.com/openshift/origin/pkg/clioptions/kubeconfig"
+	testginkgo "github.com/openshift/origin/pkg/test/ginkgo"
+	"github.com/openshift/origin/pkg/test/inclusterdriver"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// runInClusterDriver runs this invocation of the run command in a Job in the cluster.  The test process exit code is
+// returned as a testginkgo.ExitError so that the local process exits the same way.
+func (f *RunSuiteFlags) runInClusterDriver(ctx context.Context, flags *pflag.FlagSet, args []string) error {
+	// interrupting still removes the Job and its namespace
+	ctx, cancel := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	restConfig, err := kubeconfig.GetStaticRESTConfig()
+	if err != nil {
+		return fmt.Errorf("unable to get admin rest config, %w", err)
+	}
+
+	closeFn, err := f.OutputFlags.ConfigureIOStreams(f.IOStreams, f)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	driverOptions, err := f.inClusterDriverOptions(flags, args, os.Stdin)
+	if err != nil {
+		return err
+	}
+	exitCode, err := inclusterdriver.Run(ctx, restConfig, *driverOptions)
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return testginkgo.ExitError{Code: exitCode}
+	}
+	return nil
+}
+
+// inClusterDriverOptions rebuilds the arguments of the run command from the flags that were set.  Files read by the
+// run command are passed to the pod in the input configmap, and directories it writes are copied back.
+func (f *RunSuiteFlags) inClusterDriverOptions(flags *pflag.FlagSet, args []string, stdin io.Reader) (*inclusterdriver.Options, error) {
+	o := &inclusterdriver.Options{
+		Image:        f.InClusterDriverImage,
+		InputFiles:   map[string][]byte{},
+		ArtifactDirs: map[string]string{},
+		Out:          f.Out,
+		ErrOut:       f.ErrOut,
+	}
+
+	var flagErr error
+	addInputFile := func(name string, data []byte, err error) {
+		if err != nil {
+			if flagErr == nil {
+				flagErr = err
+			}
+			return
+		}
+		o.InputFiles[name] = data
+	}
+	flags.Visit(func(flag *pflag.Flag) {
+		switch flag.Name {
+		case "in-cluster-driver", "in-cluster-driver-image":
+		case "output-file":
+			// output is streamed back to this process, which writes it to --output-file
+		case "junit-dir":
+			o.ArtifactDirs["junit"] = f.GinkgoRunSuiteOptions.JUnitDir
+			o.Args = append(o.Args, "--junit-dir="+path.Join(inclusterdriver.ArtifactDir, "junit"))
+		case "file":
+			if f.TestSuiteSelectionFlags.TestFile == "-" {
+				data, err := io.ReadAll(stdin)
+				addInputFile("tests", data, err)
+			} else {
+				data, err := os.ReadFile(f.TestSuiteSelectionFlags.TestFile)
+				addInputFile("tests", data, err)
+			}
+			o.Args = append(o.Args, "--file="+path.Join(inclusterdriver.InputDir, "tests"))
+		case "suite-file":
+			data, err := os.ReadFile(f.SuiteFile)
+			addInputFile("suites.yaml", data, err)
+			o.Args = append(o.Args, "--suite-file="+path.Join(inclusterdriver.InputDir, "suites.yaml"))
+		case "replay-manifest":
+			data, err := os.ReadFile(f.ReplayManifest)
+			addInputFile("run-manifest.json", data, err)
+			o.Args = append(o.Args, "--replay-manifest="+path.Join(inclusterdriver.InputDir, "run-manifest.json"))
+		case "disruption-backends":
+			data, err := os.ReadFile(f.GinkgoRunSuiteOptions.DisruptionBackendsFile)
+			addInputFile("disruption-backends.yaml", data, err)
+			o.Args = append(o.Args, "--disruption-backends="+path.Join(inclusterdriver.InputDir, "disruption-backends.yaml"))
+		case "log-rules":
+			data, err := os.ReadFile(f.GinkgoRunSuiteOptions.LogRulesFile)
+			addInputFile("log-rules.yaml", data, err)
+			o.Args = append(o.Args, "--log-rules="+path.Join(inclusterdriver.InputDir, "log-rules.yaml"))
+		default:
+			if sliceValue, ok := flag.Value.(pflag.SliceValue); ok {
+				for _, value := range sliceValue.GetSlice() {
+					o.Args = append(o.Args, fmt.Sprintf("--%s=%s", flag.Name, value))
+				}
+				return
+			}
+			o.Args = append(o.Args, fmt.Sprintf("--%s=%s", flag.Name, flag.Value.String()))
+		}
+	})
+	if flagErr != nil {
+		return nil, flagErr
+	}
+	o.Args = append(o.Args, args...)
+
+	if artifactDir := os.Getenv("ARTIFACT_DIR"); len(artifactDir) > 0 {
+		o.ArtifactDirs["artifact-dir"] = artifactDir
+		o.Env = append(o.Env, corev1.EnvVar{Name: "ARTIFACT_DIR", Value: path.Join(inclusterdriver.ArtifactDir, "artifact-dir")})
+	}
+	return o, nil
+}
@@ -13,6 +13,7 @@ import (
 	testginkgo "github.com/openshift/origin/pkg/test/ginkgo"
 	"github.com/openshift/origin/pkg/version"
 	"github.com/openshift/origin/test/extended/util/image"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/klog/v2"
 	k8simage "k8s.io/kubernetes/test/utils/image"
@@ -80,10 +81,27 @@ func (o *RunSuiteOptions) Run(ctx context.Context) error {
 		stabilitySetting = o.Suite.ClusterStabilityDuringTest
 	}
 
+	// --monitor replaces the monitor tests chosen by the suite, --disable-monitor adds to the ones it disables.
+	exactMonitorTests := o.GinkgoRunSuiteOptions.ExactMonitorTests
+	if len(exactMonitorTests) == 0 {
+		exactMonitorTests = o.Suite.ExactMonitorTests
+	}
+	disableMonitorTests := sets.List(sets.New[string](o.Suite.DisableMonitorTests...).Insert(o.GinkgoRunSuiteOptions.DisableMonitorTests...))
+	if manifest := o.GinkgoRunSuiteOptions.ReplayManifest; manifest != nil {
+		// a replay runs the monitor tests of the run it replays
+		exactMonitorTests = manifest.Monitors
+		disableMonitorTests = nil
+	}
+
 	monitorTestInfo := monitortestframework.MonitorTestInitializationInfo{
-		ClusterStabilityDuringTest: monitortestframework.ClusterStabilityDuringTest(stabilitySetting),
-		ExactMonitorTests:          o.GinkgoRunSuiteOptions.ExactMonitorTests,
-		DisableMonitorTests:        o.GinkgoRunSuiteOptions.DisableMonitorTests,
+		ClusterStabilityDuringTest:   monitortestframework.ClusterStabilityDuringTest(stabilitySetting),
+		ExactMonitorTests:            exactMonitorTests,
+		DisableMonitorTests:          disableMonitorTests,
+		DisruptionBackendsFile:       o.GinkgoRunSuiteOptions.DisruptionBackendsFile,
+		PodNetworkNodeMatrix:         o.GinkgoRunSuiteOptions.PodNetworkNodeMatrix,
+		LogRulesFile:                 o.GinkgoRunSuiteOptions.LogRulesFile,
+		StreamAuditLogs:              o.GinkgoRunSuiteOptions.StreamAuditLogs,
+		CertificateExpiryWarningDays: o.GinkgoRunSuiteOptions.CertificateExpiryWarningDays,
 	}
 
 	o.GinkgoRunSuiteOptions.CommandEnv = o.TestCommandEnvironment()
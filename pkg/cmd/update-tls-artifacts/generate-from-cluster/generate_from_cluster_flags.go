@@ -0,0 +1,114 @@
+package generate_from_cluster
+
+import (
+	"context"
+	"fmt"
+
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+
+	ensure_no_violation_regression "github.com/openshift/origin/pkg/cmd/update-tls-artifacts/ensure-no-violation-regression"
+	"github.com/openshift/origin/pkg/cmd/update-tls-artifacts/generate-owners/tlsmetadatadefaults"
+	ownership "github.com/openshift/origin/tls"
+)
+
+// GenerateFromClusterFlags gets bound to cobra commands and arguments.  It is used to validate input and then produce
+// the Options struct.  Options struct is intended to be embeddable and re-useable without cobra.
+type GenerateFromClusterFlags struct {
+	ConfigFlags *genericclioptions.ConfigFlags
+
+	TLSInfoDir                  string
+	RawDataFile                 string
+	OpenShiftTestsImagePullSpec string
+	SkipOnDisk                  bool
+
+	genericclioptions.IOStreams
+}
+
+func NewGenerateFromClusterCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	f := NewGenerateFromClusterFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:   "generate-from-cluster",
+		Short: "Collect TLS artifacts from a live cluster and check them against the TLS registry.",
+		Long: `
+		Collect in-cluster secrets and configmaps and the on-disk certificates of every node from the
+		cluster in the current kubeconfig, and write the result to the raw data directory. Every
+		requirement is then evaluated against the checked-in raw data plus this snapshot, reporting the
+		changes generate-ownership would make and any violations the snapshot regresses.
+		`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := f.Validate()
+			if err != nil {
+				return err
+			}
+
+			o, err := f.ToOptions()
+			if err != nil {
+				return err
+			}
+			return o.Run(context.Background())
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func NewGenerateFromClusterFlags(streams genericclioptions.IOStreams) *GenerateFromClusterFlags {
+	return &GenerateFromClusterFlags{
+		ConfigFlags: genericclioptions.NewConfigFlags(false),
+		TLSInfoDir:  "tls",
+		IOStreams:   streams,
+	}
+}
+
+func (f *GenerateFromClusterFlags) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&f.TLSInfoDir, "ownership-dir", f.TLSInfoDir, "The directory holding the TLS ownership info to compare against.")
+	flags.StringVar(&f.RawDataFile, "raw-data-file", f.RawDataFile, "Where to write the collected PKIList. Defaults to <ownership-dir>/raw-data/raw-tls-artifacts-<job type>.json.")
+	flags.StringVar(&f.OpenShiftTestsImagePullSpec, "openshift-tests-image", f.OpenShiftTestsImagePullSpec, "Image used to collect on-disk certificates. Defaults to the tests image of the cluster's release payload.")
+	flags.BoolVar(&f.SkipOnDisk, "skip-on-disk", f.SkipOnDisk, "Only collect in-cluster secrets and configmaps.")
+	f.ConfigFlags.AddFlags(flags)
+}
+
+func (f *GenerateFromClusterFlags) Validate() error {
+	if len(f.TLSInfoDir) == 0 {
+		return fmt.Errorf("--ownership-dir must be specified")
+	}
+	return nil
+}
+
+func (f *GenerateFromClusterFlags) ToOptions() (*GenerateFromClusterOptions, error) {
+	restConfig, err := f.ConfigFlags.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	configClient, err := configclient.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GenerateFromClusterOptions{
+		RESTConfig:                  restConfig,
+		KubeClient:                  kubeClient,
+		ConfigClient:                configClient,
+		TLSInfoDir:                  f.TLSInfoDir,
+		RawDataFile:                 f.RawDataFile,
+		OpenShiftTestsImagePullSpec: f.OpenShiftTestsImagePullSpec,
+		SkipOnDisk:                  f.SkipOnDisk,
+		Requirements:                tlsmetadatadefaults.GetDefaultTLSRequirements(),
+		ViolationRegressionOptions:  ensure_no_violation_regression.NewEnsureNoViolationRegressionOptions(ownership.AllViolations, f.IOStreams),
+
+		IOStreams: f.IOStreams,
+	}, nil
+}
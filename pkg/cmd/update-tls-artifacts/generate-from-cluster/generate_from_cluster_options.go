@@ -0,0 +1,176 @@
+package generate_from_cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	"github.com/openshift/library-go/pkg/certs/cert-inspection/certgraphanalysis"
+	"github.com/openshift/library-go/pkg/certs/cert-inspection/certgraphapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/certs/certcollection"
+	ensure_no_violation_regression "github.com/openshift/origin/pkg/cmd/update-tls-artifacts/ensure-no-violation-regression"
+	generate_owners "github.com/openshift/origin/pkg/cmd/update-tls-artifacts/generate-owners"
+	"github.com/openshift/origin/pkg/cmd/update-tls-artifacts/generate-owners/tlsmetadatainterfaces"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/monitortests/network/disruptionpodnetwork"
+)
+
+type GenerateFromClusterOptions struct {
+	RESTConfig   *rest.Config
+	KubeClient   kubernetes.Interface
+	ConfigClient configclient.Interface
+
+	TLSInfoDir                  string
+	RawDataFile                 string
+	OpenShiftTestsImagePullSpec string
+	SkipOnDisk                  bool
+	Requirements                []tlsmetadatainterfaces.Requirement
+	ViolationRegressionOptions  *ensure_no_violation_regression.EnsureNoViolationRegressionOptions
+
+	genericclioptions.IOStreams
+}
+
+func (o *GenerateFromClusterOptions) Run(ctx context.Context) error {
+	rawDataFile, err := o.rawDataFilename(ctx)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := o.collect(ctx)
+	if err != nil {
+		return err
+	}
+	if err := writePKIList(rawDataFile, snapshot); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "Wrote cluster TLS artifacts to %s\n", rawDataFile)
+
+	// The snapshot replaces any checked-in raw data for the same job type, so the comparison shows what
+	// generate-ownership would produce once the snapshot is committed.
+	rawDataByFilename, err := generate_owners.ReadRawDataFromDir(o.TLSInfoDir)
+	if err != nil {
+		return fmt.Errorf("failure reading raw data: %w", err)
+	}
+	rawDataByFilename[filepath.Base(rawDataFile)] = snapshot
+	rawData, err := generate_owners.RawDataInFilenameOrder(rawDataByFilename)
+	if err != nil {
+		return fmt.Errorf("failure reading raw data: %w", err)
+	}
+
+	errs := []error{}
+	numDiffs := 0
+	for _, requirement := range o.Requirements {
+		result, err := requirement.InspectRequirement(rawData)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failure inspecting for %v: %w", requirement.GetName(), err))
+			continue
+		}
+
+		diff, ok, err := result.DiffExistingContent(o.TLSInfoDir)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("failure diffing for %v: %w", requirement.GetName(), err))
+		case len(diff) > 0:
+			numDiffs++
+			fmt.Fprintf(o.Out, "\nrequirement/%v differs from %s:\n%v\n", requirement.GetName(), o.TLSInfoDir, diff)
+		case !ok:
+			numDiffs++
+			fmt.Fprintf(o.Out, "\nrequirement/%v differs from %s, but no details included\n", requirement.GetName(), o.TLSInfoDir)
+		}
+	}
+
+	// violations are checked against the snapshot alone, the same way the e2e test checks a single run.
+	regressions, _, err := o.ViolationRegressionOptions.HaveViolationsRegressed([]*certgraphapi.PKIList{snapshot})
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, regression := range regressions {
+		fmt.Fprintln(o.Out, regression)
+	}
+
+	if numDiffs > 0 {
+		errs = append(errs, fmt.Errorf("%d requirements differ from %s, run generate-ownership to update them", numDiffs, o.TLSInfoDir))
+	}
+	if len(regressions) > 0 {
+		errs = append(errs, fmt.Errorf("%d violation regressions found", len(regressions)))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (o *GenerateFromClusterOptions) rawDataFilename(ctx context.Context) (string, error) {
+	if len(o.RawDataFile) > 0 {
+		return o.RawDataFile, nil
+	}
+
+	jobType, err := platformidentification.GetJobType(ctx, o.RESTConfig)
+	if err != nil {
+		return "", fmt.Errorf("unable to determine job type: %w", err)
+	}
+	featureGates, err := o.ConfigClient.ConfigV1().FeatureGates().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(o.TLSInfoDir, "raw-data", certcollection.RawTLSArtifactFilename(jobType, featureGates.Spec.FeatureSet)), nil
+}
+
+func (o *GenerateFromClusterOptions) collect(ctx context.Context) (*certgraphapi.PKIList, error) {
+	masters, err := certcollection.ControlPlaneNodes(ctx, o.KubeClient)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(o.Out, "Collecting in-cluster TLS artifacts\n")
+	inClusterPKIContent, err := certcollection.GatherCertsFromPlatformNamespaces(ctx, o.KubeClient, masters)
+	if err != nil {
+		return nil, fmt.Errorf("failure collecting in-cluster TLS artifacts: %w", err)
+	}
+	if o.SkipOnDisk {
+		return inClusterPKIContent, nil
+	}
+
+	testsImagePullSpec := o.OpenShiftTestsImagePullSpec
+	if len(testsImagePullSpec) == 0 {
+		testsImagePullSpec, err = disruptionpodnetwork.GetOpenshiftTestsImagePullSpec(ctx, o.RESTConfig, "", nil)
+		if err != nil {
+			return nil, fmt.Errorf("unable to determine openshift-tests image, use --openshift-tests-image or --skip-on-disk: %w", err)
+		}
+	}
+
+	nodeList, err := o.KubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	nodes := []*corev1.Node{}
+	for i := range nodeList.Items {
+		nodes = append(nodes, &nodeList.Items[i])
+	}
+
+	fmt.Fprintf(o.Out, "Collecting on-disk TLS artifacts from %d nodes\n", len(nodes))
+	onDiskPKIContent, err := certcollection.FetchOnDiskCertificates(ctx, o.KubeClient, o.RESTConfig, nodes, testsImagePullSpec)
+	if err != nil {
+		return nil, fmt.Errorf("failure collecting on-disk TLS artifacts: %w", err)
+	}
+
+	return certgraphanalysis.MergePKILists(ctx, inClusterPKIContent, onDiskPKIContent), nil
+}
+
+func writePKIList(filename string, pkiList *certgraphapi.PKIList) error {
+	jsonBytes, err := json.MarshalIndent(pkiList, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, jsonBytes, 0644)
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/openshift/library-go/pkg/certs/cert-inspection/certgraphapi"
 
@@ -71,9 +72,18 @@ func (o *GenerateOwnersOptions) Run() error {
 }
 
 func (o *GenerateOwnersOptions) getRawDataFromDir() ([]*certgraphapi.PKIList, error) {
-	ret := []*certgraphapi.PKIList{}
+	rawDataByFilename, err := ReadRawDataFromDir(o.TLSInfoDir)
+	if err != nil {
+		return nil, err
+	}
+	return RawDataInFilenameOrder(rawDataByFilename)
+}
+
+// ReadRawDataFromDir reads every PKIList in <tlsInfoDir>/raw-data, keyed by filename.
+func ReadRawDataFromDir(tlsInfoDir string) (map[string]*certgraphapi.PKIList, error) {
+	ret := map[string]*certgraphapi.PKIList{}
 
-	rawDataDir := filepath.Join(o.TLSInfoDir, "raw-data")
+	rawDataDir := filepath.Join(tlsInfoDir, "raw-data")
 	err := filepath.WalkDir(rawDataDir, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -92,7 +102,7 @@ func (o *GenerateOwnersOptions) getRawDataFromDir() ([]*certgraphapi.PKIList, er
 		if err != nil {
 			return err
 		}
-		ret = append(ret, currPKI)
+		ret[d.Name()] = currPKI
 
 		return nil
 	})
@@ -100,6 +110,17 @@ func (o *GenerateOwnersOptions) getRawDataFromDir() ([]*certgraphapi.PKIList, er
 		return nil, err
 	}
 
+	return ret, nil
+}
+
+// RawDataInFilenameOrder flattens raw data read by ReadRawDataFromDir and verifies it is consistent.
+func RawDataInFilenameOrder(rawDataByFilename map[string]*certgraphapi.PKIList) ([]*certgraphapi.PKIList, error) {
+	filenames := sets.StringKeySet(rawDataByFilename).List()
+	ret := []*certgraphapi.PKIList{}
+	for _, filename := range filenames {
+		ret = append(ret, rawDataByFilename[filename])
+	}
+
 	// verification that our raw data is consistent
 	if _, err := tlsmetadatainterfaces.ProcessByLocation(ret); err != nil {
 		return nil, err
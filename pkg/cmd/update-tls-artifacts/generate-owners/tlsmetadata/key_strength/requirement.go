@@ -0,0 +1,72 @@
+package key_strength
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/openshift/library-go/pkg/certs/cert-inspection/certgraphapi"
+	"github.com/openshift/origin/pkg/cmd/update-tls-artifacts/generate-owners/tlsmetadatainterfaces"
+)
+
+// minimumKeySize is the smallest key, in bits, allowed for each public key algorithm.  Algorithms that are not listed
+// are not allowed.
+var minimumKeySize = map[string]int{
+	"RSA":     2048,
+	"ECDSA":   256,
+	"Ed25519": 256,
+}
+
+// forbiddenSignatureHashes are hash functions that no longer provide collision resistance.
+var forbiddenSignatureHashes = []string{"MD2", "MD5", "SHA1"}
+
+func NewKeyStrengthRequirement() tlsmetadatainterfaces.Requirement {
+	md := tlsmetadatainterfaces.NewMarkdown("")
+	md.Text("Certificates must use a strong key and signature.")
+	md.OrderedListStart()
+	md.NewOrderedListItem()
+	md.Text("RSA keys must be at least 2048 bits.")
+	md.NewOrderedListItem()
+	md.Text("ECDSA keys must be at least 256 bits.")
+	md.NewOrderedListItem()
+	md.Text("Ed25519 keys are allowed, other public key algorithms are not.")
+	md.NewOrderedListItem()
+	md.Text("Signatures must not use MD2, MD5 or SHA1.")
+	md.OrderedListEnd()
+
+	return tlsmetadatainterfaces.NewCertificateRequirement(
+		"key-strength",
+		"Key Strength of TLS Artifacts",
+		string(md.ExactBytes()),
+		checkKeyStrength,
+	)
+}
+
+func checkKeyStrength(_ *certgraphapi.PKIList, certKeyPair *certgraphapi.CertKeyPair) (string, string) {
+	metadata := certKeyPair.Spec.CertMetadata
+	observed := fmt.Sprintf("%s %s, %s", metadata.PublicKeyAlgorithm, metadata.PublicKeyBitSize, metadata.SignatureAlgorithm)
+
+	for _, hash := range forbiddenSignatureHashes {
+		if strings.HasPrefix(metadata.SignatureAlgorithm, hash+"-") {
+			return observed, fmt.Sprintf("signature algorithm %s uses %s", metadata.SignatureAlgorithm, hash)
+		}
+	}
+
+	minimum, ok := minimumKeySize[metadata.PublicKeyAlgorithm]
+	if !ok {
+		return observed, fmt.Sprintf("public key algorithm %q is not allowed", metadata.PublicKeyAlgorithm)
+	}
+	// PublicKeyBitSize looks like "2048 bit" or "256 bit, P-256 curve".  Ed25519 keys have no size recorded and
+	// are always 256 bit.
+	if len(metadata.PublicKeyBitSize) == 0 && metadata.PublicKeyAlgorithm == "Ed25519" {
+		return observed, ""
+	}
+	bits, err := strconv.Atoi(strings.SplitN(metadata.PublicKeyBitSize, " ", 2)[0])
+	if err != nil {
+		return observed, fmt.Sprintf("unable to determine key size from %q", metadata.PublicKeyBitSize)
+	}
+	if bits < minimum {
+		return observed, fmt.Sprintf("%s key is %d bit, at least %d bit is required", metadata.PublicKeyAlgorithm, bits, minimum)
+	}
+	return observed, ""
+}
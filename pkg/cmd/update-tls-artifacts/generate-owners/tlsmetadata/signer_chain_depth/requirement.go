@@ -0,0 +1,83 @@
+package signer_chain_depth
+
+import (
+	"fmt"
+
+	"github.com/openshift/library-go/pkg/certs/cert-inspection/certgraphapi"
+
+	"github.com/openshift/origin/pkg/cmd/update-tls-artifacts/generate-owners/tlsmetadatainterfaces"
+)
+
+// maximumChainDepth is the number of signers allowed above a certificate, including the root.  A depth of two
+// allows one intermediate signer.
+const maximumChainDepth = 2
+
+func NewSignerChainDepthRequirement() tlsmetadatainterfaces.Requirement {
+	md := tlsmetadatainterfaces.NewMarkdown("")
+	md.Text("Certificates must be issued by a short chain of signers.")
+	md.Textf("At most %d signers, including the self-signed root, may be above a certificate.", maximumChainDepth)
+	md.Text("Every intermediate signer is another key that has to be protected and rotated, and another certificate")
+	md.Text("every client has to be sent.")
+	md.Text("")
+	md.Text("Signers are found by issuer common name among the certificates and CA bundles collected from the same cluster.")
+	md.Text("Chains that leave the collected data, for instance to an external CA, are counted up to that point.")
+
+	return tlsmetadatainterfaces.NewCertificateRequirement(
+		"signer-chain-depth",
+		"Signer Chain Depth of TLS Artifacts",
+		string(md.ExactBytes()),
+		checkSignerChainDepth,
+	)
+}
+
+// signersByCommonName indexes every signer in the raw data of a single cluster.  Signer common names usually embed
+// their creation time, so they are unique within a cluster.
+func signersByCommonName(pkiList *certgraphapi.PKIList) map[string]certgraphapi.CertIdentifier {
+	ret := map[string]certgraphapi.CertIdentifier{}
+	for _, curr := range pkiList.CertKeyPairs.Items {
+		if curr.Spec.Details.SignerDetails == nil {
+			continue
+		}
+		ret[curr.Spec.CertMetadata.CertIdentifier.CommonName] = curr.Spec.CertMetadata.CertIdentifier
+	}
+	for _, caBundle := range pkiList.CertificateAuthorityBundles.Items {
+		for _, curr := range caBundle.Spec.CertificateMetadata {
+			if _, ok := ret[curr.CertIdentifier.CommonName]; !ok {
+				ret[curr.CertIdentifier.CommonName] = curr.CertIdentifier
+			}
+		}
+	}
+	return ret
+}
+
+// chainDepth counts the signers above identifier that can be found in signers.
+func chainDepth(identifier certgraphapi.CertIdentifier, signers map[string]certgraphapi.CertIdentifier) int {
+	depth := 0
+	seen := map[string]bool{identifier.CommonName: true}
+	current := identifier
+	for current.Issuer != nil && len(current.Issuer.CommonName) > 0 {
+		issuerName := current.Issuer.CommonName
+		if issuerName == current.CommonName {
+			// self-signed
+			break
+		}
+		depth++
+		signer, ok := signers[issuerName]
+		if !ok || seen[issuerName] {
+			break
+		}
+		seen[issuerName] = true
+		current = signer
+	}
+	return depth
+}
+
+func checkSignerChainDepth(pkiList *certgraphapi.PKIList, certKeyPair *certgraphapi.CertKeyPair) (string, string) {
+	identifier := certKeyPair.Spec.CertMetadata.CertIdentifier
+	depth := chainDepth(identifier, signersByCommonName(pkiList))
+	observed := fmt.Sprintf("chain depth %d", depth)
+	if depth > maximumChainDepth {
+		return observed, fmt.Sprintf("chain depth is %d, at most %d is allowed", depth, maximumChainDepth)
+	}
+	return observed, ""
+}
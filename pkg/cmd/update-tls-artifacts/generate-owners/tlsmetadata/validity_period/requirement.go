@@ -0,0 +1,107 @@
+package validity_period
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/openshift/library-go/pkg/certs/cert-inspection/certgraphapi"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"github.com/openshift/origin/pkg/cmd/update-tls-artifacts/generate-owners/tlsmetadatainterfaces"
+)
+
+const day = 24 * time.Hour
+
+// maximumValidity is the longest a certificate of each class may be valid for.  Signers are long-lived because
+// rotating them requires distributing a new CA bundle, leaf certificates are cheap to rotate.
+var maximumValidity = map[string]time.Duration{
+	"signer":  10 * 365 * day,
+	"serving": 2 * 365 * day,
+	"client":  365 * day,
+}
+
+func NewValidityPeriodRequirement() tlsmetadatainterfaces.Requirement {
+	md := tlsmetadatainterfaces.NewMarkdown("")
+	md.Text("Certificates must not be valid for longer than their class allows.")
+	md.OrderedListStart()
+	md.NewOrderedListItem()
+	md.Textf("Signers may be valid for up to %v.", duration.HumanDuration(maximumValidity["signer"]))
+	md.NewOrderedListItem()
+	md.Textf("Serving certificates may be valid for up to %v.", duration.HumanDuration(maximumValidity["serving"]))
+	md.NewOrderedListItem()
+	md.Textf("Client certificates may be valid for up to %v.", duration.HumanDuration(maximumValidity["client"]))
+	md.OrderedListEnd()
+	md.Text("")
+	md.Text("Certificates with more than one usage are held to the limit of their most permissive class.")
+
+	return tlsmetadatainterfaces.NewCertificateRequirement(
+		"validity-period",
+		"Validity Period of TLS Artifacts",
+		string(md.ExactBytes()),
+		checkValidityPeriod,
+	)
+}
+
+// certificateClass classifies a certificate by its usages, matching how certgraphanalysis assigns CertType.
+func certificateClass(metadata certgraphapi.CertKeyMetadata) string {
+	for _, usage := range metadata.Usages {
+		if usage == "KeyUsageCertSign" {
+			return "signer"
+		}
+	}
+	for _, usage := range metadata.ExtendedUsages {
+		if usage == "ExtKeyUsageServerAuth" {
+			return "serving"
+		}
+	}
+	return "client"
+}
+
+func checkValidityPeriod(_ *certgraphapi.PKIList, certKeyPair *certgraphapi.CertKeyPair) (string, string) {
+	metadata := certKeyPair.Spec.CertMetadata
+	class := certificateClass(metadata)
+	observed := fmt.Sprintf("%s valid for %s", class, metadata.ValidityDuration)
+
+	validity, err := parseHumanDuration(metadata.ValidityDuration)
+	if err != nil {
+		return observed, err.Error()
+	}
+	if validity > maximumValidity[class] {
+		return observed, fmt.Sprintf("%s certificate is valid for %s, at most %s is allowed", class, metadata.ValidityDuration, duration.HumanDuration(maximumValidity[class]))
+	}
+	return observed, ""
+}
+
+var humanDurationRegex = regexp.MustCompile(`^(?:(\d+)y)?(?:(\d+)d)?(?:(\d+)h)?(?:(\d+)m)?(?:(\d+)s)?$`)
+
+// parseHumanDuration reverses duration.HumanDuration, which is how certgraphanalysis records validity.  The result
+// is approximate, HumanDuration drops smaller units for long durations.
+func parseHumanDuration(in string) (time.Duration, error) {
+	if in == "<invalid>" || len(in) == 0 {
+		return 0, fmt.Errorf("unable to parse validity %q", in)
+	}
+	// durations under two seconds are reported as "<2s"
+	if in[0] == '<' {
+		in = in[1:]
+	}
+	matches := humanDurationRegex.FindStringSubmatch(in)
+	if matches == nil {
+		return 0, fmt.Errorf("unable to parse validity %q", in)
+	}
+
+	units := []time.Duration{365 * day, day, time.Hour, time.Minute, time.Second}
+	var ret time.Duration
+	for i, unit := range units {
+		if len(matches[i+1]) == 0 {
+			continue
+		}
+		value, err := strconv.Atoi(matches[i+1])
+		if err != nil {
+			return 0, fmt.Errorf("unable to parse validity %q: %w", in, err)
+		}
+		ret += time.Duration(value) * unit
+	}
+	return ret, nil
+}
@@ -0,0 +1,42 @@
+package wildcard_sans
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift/library-go/pkg/certs/cert-inspection/certgraphapi"
+	"github.com/openshift/origin/pkg/cmd/update-tls-artifacts/generate-owners/tlsmetadatainterfaces"
+)
+
+func NewWildcardSANsRequirement() tlsmetadatainterfaces.Requirement {
+	md := tlsmetadatainterfaces.NewMarkdown("")
+	md.Text("Serving certificates must not use wildcard subject alternative names.")
+	md.Text("A wildcard certificate terminates every name under its domain, so a compromised key allows impersonating")
+	md.Text("services that were never meant to share it.  List each name the certificate terminates instead.")
+
+	return tlsmetadatainterfaces.NewCertificateRequirement(
+		"wildcard-sans",
+		"Wildcard Subject Alternative Names of TLS Artifacts",
+		string(md.ExactBytes()),
+		checkWildcardSANs,
+	)
+}
+
+func checkWildcardSANs(_ *certgraphapi.PKIList, certKeyPair *certgraphapi.CertKeyPair) (string, string) {
+	servingDetails := certKeyPair.Spec.Details.ServingCertDetails
+	if servingDetails == nil {
+		return "not a serving certificate", ""
+	}
+
+	// the names themselves are not reported, wildcards usually include the cluster domain which differs for every run.
+	numWildcards := 0
+	for _, name := range servingDetails.DNSNames {
+		if strings.Contains(name, "*") {
+			numWildcards++
+		}
+	}
+	if numWildcards > 0 {
+		return "wildcard names", fmt.Sprintf("serving certificate has %d wildcard names", numWildcards)
+	}
+	return "no wildcard names", ""
+}
@@ -0,0 +1,215 @@
+package tlsmetadatainterfaces
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/openshift/library-go/pkg/certs/cert-inspection/certgraphapi"
+	"github.com/openshift/origin/pkg/certs"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// CertificateCheckFunc inspects a single certificate.  It returns a short, stable description of what was observed
+// and, when the certificate does not meet the requirement, why.  pkiList is the raw data the certificate came from
+// so checks can look up related certificates like signers.
+type CertificateCheckFunc func(pkiList *certgraphapi.PKIList, certKeyPair *certgraphapi.CertKeyPair) (observed, violation string)
+
+type certificateRequirement struct {
+	// requirementName is a unique name for metadata requirement
+	requirementName string
+	// title for the markdown
+	title string
+	// explanationMD is exactly the markdown to include that explains the purposes of the check
+	explanationMD string
+	check         CertificateCheckFunc
+}
+
+// NewCertificateRequirement creates a requirement on the details of the certificates themselves, rather than on the
+// metadata of the resources holding them.  Only in-cluster certificates are inspected.
+func NewCertificateRequirement(requirementName, title, explanationMD string, check CertificateCheckFunc) Requirement {
+	return certificateRequirement{
+		requirementName: requirementName,
+		title:           title,
+		explanationMD:   explanationMD,
+		check:           check,
+	}
+}
+
+// CertificateRequirementStatus is the <name>.json content of a certificate requirement.
+type CertificateRequirementStatus struct {
+	CertKeyPairs []CertificateRequirementCertKeyPair `json:"certKeyPairs"`
+}
+
+type CertificateRequirementCertKeyPair struct {
+	SecretLocation      certgraphapi.InClusterSecretLocation `json:"secretLocation"`
+	OwningJiraComponent string                               `json:"owningJiraComponent"`
+	// Observed holds what was seen across all raw data.  The same secret may hold different certificates on different
+	// platforms.
+	Observed   []string `json:"observed"`
+	Violations []string `json:"violations,omitempty"`
+}
+
+func (o certificateRequirement) GetName() string {
+	return o.requirementName
+}
+
+func (o certificateRequirement) InspectRequirement(rawData []*certgraphapi.PKIList) (RequirementResult, error) {
+	pkiInfo, err := ProcessByLocation(rawData)
+	if err != nil {
+		return nil, fmt.Errorf("transforming raw data %v: %w", o.GetName(), err)
+	}
+
+	status := o.inspectCertificates(rawData, pkiInfo)
+	statusJSONBytes, err := json.MarshalIndent(status, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("failure marshalling %v.json: %w", o.GetName(), err)
+	}
+	markdown := o.generateInspectionMarkdown(status)
+	violations := generateViolationJSONForCertificateRequirement(status, pkiInfo)
+	violationJSONBytes, err := json.MarshalIndent(violations, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("failure marshalling %v-violations.json: %w", o.GetName(), err)
+	}
+
+	return NewRequirementResult(
+		o.GetName(),
+		statusJSONBytes,
+		markdown,
+		violationJSONBytes)
+}
+
+func (o certificateRequirement) inspectCertificates(rawData []*certgraphapi.PKIList, pkiInfo *certs.PKIRegistryInfo) *CertificateRequirementStatus {
+	observedByLocation := map[certgraphapi.InClusterSecretLocation]sets.Set[string]{}
+	violationsByLocation := map[certgraphapi.InClusterSecretLocation]sets.Set[string]{}
+	for _, currPKI := range rawData {
+		for i := range currPKI.CertKeyPairs.Items {
+			curr := &currPKI.CertKeyPairs.Items[i]
+			if len(curr.Spec.SecretLocations) == 0 {
+				continue
+			}
+			observed, violation := o.check(currPKI, curr)
+			for _, location := range curr.Spec.SecretLocations {
+				if _, ok := observedByLocation[location]; !ok {
+					observedByLocation[location] = sets.New[string]()
+					violationsByLocation[location] = sets.New[string]()
+				}
+				observedByLocation[location].Insert(observed)
+				if len(violation) > 0 {
+					violationsByLocation[location].Insert(violation)
+				}
+			}
+		}
+	}
+
+	owners := map[certgraphapi.InClusterSecretLocation]string{}
+	for _, curr := range pkiInfo.CertKeyPairs {
+		if curr.InClusterLocation != nil {
+			owners[curr.InClusterLocation.SecretLocation] = curr.InClusterLocation.CertKeyInfo.OwningJiraComponent
+		}
+	}
+
+	ret := &CertificateRequirementStatus{}
+	for location, observed := range observedByLocation {
+		owner := owners[location]
+		if len(owner) == 0 {
+			owner = UnknownOwner
+		}
+		ret.CertKeyPairs = append(ret.CertKeyPairs, CertificateRequirementCertKeyPair{
+			SecretLocation:      location,
+			OwningJiraComponent: owner,
+			Observed:            sets.List(observed),
+			Violations:          sets.List(violationsByLocation[location]),
+		})
+	}
+	sort.Slice(ret.CertKeyPairs, func(i, j int) bool {
+		return secretLocationLess(ret.CertKeyPairs[i].SecretLocation, ret.CertKeyPairs[j].SecretLocation)
+	})
+	return ret
+}
+
+func secretLocationLess(lhs, rhs certgraphapi.InClusterSecretLocation) bool {
+	if lhs.Namespace != rhs.Namespace {
+		return lhs.Namespace < rhs.Namespace
+	}
+	return lhs.Name < rhs.Name
+}
+
+func (o certificateRequirement) generateInspectionMarkdown(status *CertificateRequirementStatus) []byte {
+	compliantByOwner := map[string][]CertificateRequirementCertKeyPair{}
+	violatingByOwner := map[string][]CertificateRequirementCertKeyPair{}
+	numViolators := 0
+	for _, curr := range status.CertKeyPairs {
+		if len(curr.Violations) > 0 {
+			violatingByOwner[curr.OwningJiraComponent] = append(violatingByOwner[curr.OwningJiraComponent], curr)
+			numViolators++
+			continue
+		}
+		compliantByOwner[curr.OwningJiraComponent] = append(compliantByOwner[curr.OwningJiraComponent], curr)
+	}
+
+	md := NewMarkdown(o.title)
+	md.Title(2, "How to meet the requirement")
+	md.ExactText(o.explanationMD)
+
+	if numViolators > 0 {
+		md.Title(2, fmt.Sprintf("Items Do NOT Meet the Requirement (%d)", numViolators))
+		for _, owner := range sets.StringKeySet(violatingByOwner).List() {
+			certs := violatingByOwner[owner]
+			md.Title(3, fmt.Sprintf("%s (%d)", owner, len(certs)))
+			md.Title(4, fmt.Sprintf("Certificates (%d)", len(certs)))
+			md.OrderedListStart()
+			for _, curr := range certs {
+				md.NewOrderedListItem()
+				md.Textf("ns/%v secret/%v\n", curr.SecretLocation.Namespace, curr.SecretLocation.Name)
+				for _, violation := range curr.Violations {
+					md.Textf("**Violation:** %v\n", violation)
+				}
+				md.Text("\n")
+			}
+			md.OrderedListEnd()
+			md.Text("\n")
+		}
+	}
+
+	md.Title(2, fmt.Sprintf("Items That DO Meet the Requirement (%d)", len(status.CertKeyPairs)-numViolators))
+	for _, owner := range sets.StringKeySet(compliantByOwner).List() {
+		certs := compliantByOwner[owner]
+		md.Title(3, fmt.Sprintf("%s (%d)", owner, len(certs)))
+		md.Title(4, fmt.Sprintf("Certificates (%d)", len(certs)))
+		md.OrderedListStart()
+		for _, curr := range certs {
+			md.NewOrderedListItem()
+			md.Textf("ns/%v secret/%v\n", curr.SecretLocation.Namespace, curr.SecretLocation.Name)
+			for _, observed := range curr.Observed {
+				md.Textf("**Observed:** %v\n", observed)
+			}
+			md.Text("\n")
+		}
+		md.OrderedListEnd()
+		md.Text("\n")
+	}
+
+	return md.Bytes()
+}
+
+func generateViolationJSONForCertificateRequirement(status *CertificateRequirementStatus, pkiInfo *certs.PKIRegistryInfo) *certs.PKIRegistryInfo {
+	violating := sets.New[certgraphapi.InClusterSecretLocation]()
+	for _, curr := range status.CertKeyPairs {
+		if len(curr.Violations) > 0 {
+			violating.Insert(curr.SecretLocation)
+		}
+	}
+
+	ret := &certs.PKIRegistryInfo{}
+	for i := range pkiInfo.CertKeyPairs {
+		curr := pkiInfo.CertKeyPairs[i]
+		if curr.InClusterLocation == nil {
+			continue
+		}
+		if violating.Has(curr.InClusterLocation.SecretLocation) {
+			ret.CertKeyPairs = append(ret.CertKeyPairs, curr)
+		}
+	}
+	return ret
+}
@@ -18,6 +18,7 @@ import (
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apiserver/pkg/apis/audit"
 	"k8s.io/client-go/rest"
@@ -179,6 +180,27 @@ func NewRouteBackend(clientConfig *rest.Config, namespace, name, disruptionBacke
 	return ret
 }
 
+// NewServiceBackend constructs a BackendSampler suitable for use against a service, see NewServiceHostGetter for how the
+// service is reached.
+func NewServiceBackend(clientConfig *rest.Config, namespace, name string, port intstr.IntOrString, scheme, disruptionBackendName, path string, connectionType monitorapi.BackendConnectionType) *BackendSampler {
+	historicalBackendDisruptionDataName := fmt.Sprintf("%s-%v-connections", disruptionBackendName, connectionType)
+
+	ret := &BackendSampler{
+		connectionType:      connectionType,
+		locator:             monitorapi.NewLocator().LocateServiceForDisruptionCheck(historicalBackendDisruptionDataName, OpenshiftTestsSource, namespace, name, connectionType),
+		path:                path,
+		hostGetter:          NewServiceHostGetter(clientConfig, namespace, name, port, scheme),
+		consumptionFinished: make(chan struct{}),
+	}
+
+	// TODO return error?  This is programmer error
+	if len(ret.GetDisruptionBackendName()) == 0 {
+		panic("missing disruption backend")
+	}
+
+	return ret
+}
+
 // WithBearerTokenAuth sets bearer tokens to use
 func (b *BackendSampler) WithBearerTokenAuth(token, tokenFile string) *BackendSampler {
 	b.bearerToken = token
@@ -3,11 +3,16 @@ package backenddisruption
 import (
 	"context"
 	"fmt"
+	"net"
+	"strconv"
 	"sync"
 	"sync/atomic"
 
 	routeclientset "github.com/openshift/client-go/route/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
 
@@ -98,3 +103,92 @@ func (g *routeHostGetter) GetHost() (string, error) {
 
 	return "", fmt.Errorf("missing in route")
 }
+
+type serviceHostGetter struct {
+	clientConfig     *rest.Config
+	serviceNamespace string
+	serviceName      string
+	// port is the name or number of the service port, the first port is used when it is empty.
+	port   intstr.IntOrString
+	scheme string
+
+	hostGetterLock sync.Mutex
+	// host is the scheme://host:port part of the URL
+	host atomic.Value
+}
+
+// NewServiceHostGetter returns the load balancer ingress of a service of type LoadBalancer and the cluster IP of other
+// services, which is only reachable when running in the cluster.
+func NewServiceHostGetter(clientConfig *rest.Config, serviceNamespace, serviceName string, port intstr.IntOrString, scheme string) HostGetter {
+	return &serviceHostGetter{
+		clientConfig:     clientConfig,
+		serviceNamespace: serviceNamespace,
+		serviceName:      serviceName,
+		port:             port,
+		scheme:           scheme,
+	}
+}
+
+func (g *serviceHostGetter) GetHost() (string, error) {
+	existingHost := g.host.Load()
+	if existingHost != nil {
+		host := existingHost.(string)
+		if len(host) > 0 {
+			return host, nil
+		}
+	}
+	g.hostGetterLock.Lock()
+	defer g.hostGetterLock.Unlock()
+	client, err := kubernetes.NewForConfig(g.clientConfig)
+	if err != nil {
+		return "", err
+	}
+	service, err := client.CoreV1().Services(g.serviceNamespace).Get(context.Background(), g.serviceName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	host, err := serviceHost(service, g.port)
+	if err != nil {
+		return "", err
+	}
+	url := fmt.Sprintf("%s://%s", g.scheme, host)
+	g.host.Store(url)
+	return url, nil
+}
+
+// serviceHost returns the host:port to reach the port of the service on.
+func serviceHost(service *corev1.Service, port intstr.IntOrString) (string, error) {
+	if len(service.Spec.Ports) == 0 {
+		return "", fmt.Errorf("service %s/%s has no ports", service.Namespace, service.Name)
+	}
+	servicePort := service.Spec.Ports[0]
+	if port != (intstr.IntOrString{}) {
+		found := false
+		for _, candidate := range service.Spec.Ports {
+			if (port.Type == intstr.String && candidate.Name == port.StrVal) || (port.Type == intstr.Int && candidate.Port == port.IntVal) {
+				servicePort, found = candidate, true
+				break
+			}
+		}
+		if !found {
+			return "", fmt.Errorf("service %s/%s has no port %s", service.Namespace, service.Name, port.String())
+		}
+	}
+
+	if service.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		for _, ingress := range service.Status.LoadBalancer.Ingress {
+			host := ingress.IP
+			if len(ingress.Hostname) > 0 {
+				host = ingress.Hostname
+			}
+			if len(host) > 0 {
+				return net.JoinHostPort(host, strconv.Itoa(int(servicePort.Port))), nil
+			}
+		}
+		return "", fmt.Errorf("missing load balancer ingress in service %s/%s", service.Namespace, service.Name)
+	}
+	if len(service.Spec.ClusterIP) == 0 || service.Spec.ClusterIP == corev1.ClusterIPNone {
+		return "", fmt.Errorf("service %s/%s has no cluster IP", service.Namespace, service.Name)
+	}
+	return net.JoinHostPort(service.Spec.ClusterIP, strconv.Itoa(int(servicePort.Port))), nil
+}
@@ -0,0 +1,71 @@
+package backenddisruption
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func Test_serviceHost(t *testing.T) {
+	ports := []corev1.ServicePort{{Name: "http", Port: 80}, {Name: "https", Port: 443}}
+	tests := []struct {
+		name    string
+		service corev1.Service
+		port    intstr.IntOrString
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "cluster IP defaults to the first port",
+			service: corev1.Service{Spec: corev1.ServiceSpec{ClusterIP: "172.30.0.10", Ports: ports}},
+			want:    "172.30.0.10:80",
+		},
+		{
+			name:    "port by name",
+			service: corev1.Service{Spec: corev1.ServiceSpec{ClusterIP: "172.30.0.10", Ports: ports}},
+			port:    intstr.FromString("https"),
+			want:    "172.30.0.10:443",
+		},
+		{
+			name:    "unknown port",
+			service: corev1.Service{Spec: corev1.ServiceSpec{ClusterIP: "172.30.0.10", Ports: ports}},
+			port:    intstr.FromInt(8080),
+			wantErr: true,
+		},
+		{
+			name:    "headless",
+			service: corev1.Service{Spec: corev1.ServiceSpec{ClusterIP: corev1.ClusterIPNone, Ports: ports}},
+			wantErr: true,
+		},
+		{
+			name: "load balancer hostname",
+			service: corev1.Service{
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, ClusterIP: "172.30.0.10", Ports: ports},
+				Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{
+					{IP: "10.0.0.1", Hostname: "lb.example.com"},
+				}}},
+			},
+			port: intstr.FromInt(443),
+			want: "lb.example.com:443",
+		},
+		{
+			name:    "load balancer without ingress",
+			service: corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, ClusterIP: "172.30.0.10", Ports: ports}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.service.ObjectMeta = metav1.ObjectMeta{Namespace: "ns", Name: "svc"}
+			got, err := serviceHost(&tt.service, tt.port)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
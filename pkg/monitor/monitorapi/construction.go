@@ -125,6 +125,12 @@ func (b *LocatorBuilder) CloudNodeMetric(nodeName string, metric string) Locator
 		Build()
 }
 
+// CloudDiskMetric locates a metric of one of the disks attached to the VM of a node.
+func (b *LocatorBuilder) CloudDiskMetric(nodeName, disk, metric string) Locator {
+	b.annotations[LocatorDiskKey] = disk
+	return b.CloudNodeMetric(nodeName, metric)
+}
+
 func (b *LocatorBuilder) ClusterVersion(cv *v1.ClusterVersion) Locator {
 	b.targetType = LocatorTypeClusterVersion
 	b.annotations[LocatorClusterVersionKey] = cv.Name
@@ -208,6 +214,30 @@ func (b *LocatorBuilder) LocateNamespace(namespaceName string) Locator {
 		Build()
 }
 
+// EventRate locates the rate of kube events with a given reason, emitted for objects of a given kind in a namespace.
+func (b *LocatorBuilder) EventRate(namespace, kind, reason string) Locator {
+	b.targetType = LocatorTypeKubeEvent
+	if len(namespace) > 0 {
+		b.annotations[LocatorNamespaceKey] = namespace
+	}
+	b.annotations[LocatorKindKey] = kind
+	b.annotations[LocatorReasonKey] = reason
+	return b.Build()
+}
+
+// Secret uses the same generic Kind locator kube events fall back to for objects without a dedicated locator.
+func (b *LocatorBuilder) Secret(namespace, name string) Locator {
+	b.targetType = LocatorTypeKind
+	b.annotations[LocatorSecretKey] = name
+	return b.withNamespace(namespace).Build()
+}
+
+func (b *LocatorBuilder) ConfigMap(namespace, name string) Locator {
+	b.targetType = LocatorTypeKind
+	b.annotations[LocatorConfigMapKey] = name
+	return b.withNamespace(namespace).Build()
+}
+
 func (b *LocatorBuilder) withNamespace(namespace string) *LocatorBuilder {
 	b.annotations[LocatorNamespaceKey] = namespace
 	return b
@@ -238,6 +268,11 @@ func (b *LocatorBuilder) withRoute(route string) *LocatorBuilder {
 	return b
 }
 
+func (b *LocatorBuilder) withService(service string) *LocatorBuilder {
+	b.annotations[LocatorServiceKey] = service
+	return b
+}
+
 func (b *LocatorBuilder) withTargetType(targetType LocatorType) *LocatorBuilder {
 	b.targetType = targetType
 	return b
@@ -257,6 +292,29 @@ func (b *LocatorBuilder) LocateRouteForDisruptionCheck(backendDisruptionName, th
 		Build()
 }
 
+func (b *LocatorBuilder) LocateServiceForDisruptionCheck(backendDisruptionName, thisInstanceName, ns, name string, connectionType BackendConnectionType) Locator {
+	return b.
+		withDisruptionRequiredOnly(backendDisruptionName, thisInstanceName).
+		withNamespace(ns).
+		withService(name).
+		withConnectionType(connectionType).
+		Build()
+}
+
+// LocateNodePairDisruptionCheck locates a disruption check from one node to another.  Zones are left out when unknown.
+func (b *LocatorBuilder) LocateNodePairDisruptionCheck(backendDisruptionName, thisInstanceName, sourceNode, sourceZone, targetNode, targetZone string, connectionType BackendConnectionType) Locator {
+	b = b.withDisruptionRequiredOnly(backendDisruptionName, thisInstanceName).withConnectionType(connectionType)
+	b.annotations[LocatorSourceNodeKey] = sourceNode
+	b.annotations[LocatorTargetNodeKey] = targetNode
+	if len(sourceZone) > 0 {
+		b.annotations[LocatorSourceZoneKey] = sourceZone
+	}
+	if len(targetZone) > 0 {
+		b.annotations[LocatorTargetZoneKey] = targetZone
+	}
+	return b.Build()
+}
+
 func (b *LocatorBuilder) LocateDisruptionCheck(backendDisruptionName, thisInstanceName string, connectionType BackendConnectionType) Locator {
 	return b.
 		withDisruptionRequiredOnly(backendDisruptionName, thisInstanceName).
@@ -287,6 +345,15 @@ func (b *LocatorBuilder) KubeAPIServerWithLB(loadBalancer string) Locator {
 	return b.Build()
 }
 
+// KubeAPIServerRequests locates the requests of one verb on one resource, resource.group for resources outside the core group.
+func (b *LocatorBuilder) KubeAPIServerRequests(verb, resource string) Locator {
+	b.targetType = LocatorTypeAPIServer
+	b.annotations[LocatorServerKey] = "kube-apiserver"
+	b.annotations[LocatorVerbKey] = verb
+	b.annotations[LocatorResourceKey] = resource
+	return b.Build()
+}
+
 // TODO decide whether we want to allow "random" locator keys.  deads2k is -1 on random locator keys and thinks we should enumerate every possible key we special case.
 func (b *LocatorBuilder) KubeEvent(event *corev1.Event) Locator {
 
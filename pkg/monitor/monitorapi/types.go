@@ -126,6 +126,7 @@ const (
 	LocatorContainerKey       LocatorKey = "container"
 	LocatorAlertKey           LocatorKey = "alert"
 	LocatorRouteKey           LocatorKey = "route"
+	LocatorServiceKey         LocatorKey = "service"
 	// LocatorBackendDisruptionNameKey holds the value used to store and locate historical data related to the amount of disruption.
 	LocatorBackendDisruptionNameKey LocatorKey = "backend-disruption-name"
 	LocatorDisruptionKey            LocatorKey = "disruption"
@@ -134,9 +135,20 @@ const (
 	LocatorConnectionKey            LocatorKey = "connection"
 	LocatorProtocolKey              LocatorKey = "protocol"
 	LocatorTargetKey                LocatorKey = "target"
+	LocatorSourceNodeKey            LocatorKey = "source-node"
+	LocatorSourceZoneKey            LocatorKey = "source-zone"
+	LocatorTargetNodeKey            LocatorKey = "target-node"
+	LocatorTargetZoneKey            LocatorKey = "target-zone"
 	LocatorRowKey                   LocatorKey = "row"
 	LocatorServerKey                LocatorKey = "server"
 	LocatorMetricKey                LocatorKey = "metric"
+	LocatorDiskKey                  LocatorKey = "disk"
+	LocatorKindKey                  LocatorKey = "kind"
+	LocatorReasonKey                LocatorKey = "reason"
+	LocatorSecretKey                LocatorKey = "secret"
+	LocatorConfigMapKey             LocatorKey = "configmap"
+	LocatorVerbKey                  LocatorKey = "verb"
+	LocatorResourceKey              LocatorKey = "resource"
 )
 
 type Locator struct {
@@ -156,6 +168,11 @@ const (
 	DisruptionSamplerOutageBeganEventReason IntervalReason = "DisruptionSamplerOutageBegan"
 	GracefulAPIServerShutdown               IntervalReason = "GracefulAPIServerShutdown"
 	IncompleteAPIServerShutdown             IntervalReason = "IncompleteAPIServerShutdown"
+	DisruptionAttributedReason              IntervalReason = "DisruptionAttributed"
+	DisruptionUnattributedReason            IntervalReason = "DisruptionUnattributed"
+	APIServerSlowRequestsReason             IntervalReason = "APIServerSlowRequests"
+	APIServerErrorBurstReason               IntervalReason = "APIServerErrorBurst"
+	AuditLogLostReason                      IntervalReason = "AuditLogLost"
 
 	HttpClientConnectionLost IntervalReason = "HttpClientConnectionLost"
 
@@ -200,6 +217,19 @@ const (
 	FailedToDeleteCGroupsPath             IntervalReason = "FailedToDeleteCGroupsPath"
 	FailedToAuthenticateWithOpenShiftUser IntervalReason = "FailedToAuthenticateWithOpenShiftUser"
 	FailedContactingAPIReason             IntervalReason = "FailedContactingAPI"
+
+	EventRateAnomalyReason IntervalReason = "EventRateAnomaly"
+
+	CertificateRotatedReason    IntervalReason = "CertificateRotated"
+	CertificateNearExpiryReason IntervalReason = "CertificateNearExpiry"
+	CABundleMissingSignerReason IntervalReason = "CABundleMissingSigner"
+
+	EtcdSlowWALFsyncReason       IntervalReason = "EtcdSlowWALFsync"
+	EtcdSlowBackendCommitReason  IntervalReason = "EtcdSlowBackendCommit"
+	EtcdSlowPeerRoundTripReason  IntervalReason = "EtcdSlowPeerRoundTrip"
+	EtcdProposalsFailedReason    IntervalReason = "EtcdProposalsFailed"
+	EtcdDatabaseFragmentedReason IntervalReason = "EtcdDatabaseFragmented"
+	EtcdDatabaseNearQuotaReason  IntervalReason = "EtcdDatabaseNearQuota"
 )
 
 type AnnotationKey string
@@ -287,6 +317,12 @@ const (
 	SourceNodeState                              = "NodeState"
 	SourcePodState                               = "PodState"
 	SourceCloudMetrics                           = "CloudMetrics"
+	SourceEventRateAnomaly                       = "EventRateAnomaly"
+	SourceCertificateMonitor                     = "CertificateMonitor"
+	SourceDisruptionRootCause                    = "DisruptionRootCause"
+	SourceAuditLog                               = "AuditLog"
+	SourceAuditLogStreamer                       = "AuditLogStreamer"
+	SourceEtcdMetrics                            = "EtcdMetrics"
 )
 
 type Interval struct {
@@ -34,6 +34,22 @@ type MonitorTestInitializationInfo struct {
 
 	// DisableMonitorTests will remove any monitor tests contained in the provided list
 	DisableMonitorTests []string
+
+	// DisruptionBackendsFile defines additional backends to sample for disruption.
+	DisruptionBackendsFile string
+
+	// PodNetworkNodeMatrix samples the pod and host network between pairs of nodes instead of in aggregate.
+	PodNetworkNodeMatrix bool
+
+	// LogRulesFile defines additional rules turning log lines into intervals.
+	LogRulesFile string
+
+	// StreamAuditLogs tails the audit logs during the run instead of downloading them at the end.
+	StreamAuditLogs bool
+
+	// CertificateExpiryWarningDays is how many days before expiry a certificate is reported as close to expiring.
+	// Zero uses the default of the certificate-rotation-monitor.
+	CertificateExpiryWarningDays int
 }
 
 type MonitorTest interface {
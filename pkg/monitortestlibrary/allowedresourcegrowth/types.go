@@ -0,0 +1,34 @@
+package allowedresourcegrowth
+
+import (
+	_ "embed"
+	"sync"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
+)
+
+// queryResults holds the P95 and P99 growth ratio of every resource per job type, aggregated from the
+// resource-growth_*.json files previous runs wrote.  Like the alert and disruption data it is hardcoded so that a
+// degradation over time is caught, and so that people without access to CI data know what is normal on their platform.
+// Resources without data are held to the default growth allowance.
+//
+//go:embed query_results.json
+var queryResults []byte
+
+var (
+	readResults    sync.Once
+	historicalData *historicaldata.ResourceGrowthBestMatcher
+)
+
+func GetHistoricalData() *historicaldata.ResourceGrowthBestMatcher {
+	readResults.Do(
+		func() {
+			var err error
+			historicalData, err = historicaldata.NewResourceGrowthMatcher(queryResults)
+			if err != nil {
+				panic(err)
+			}
+		})
+
+	return historicalData
+}
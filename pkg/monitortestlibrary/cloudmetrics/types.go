@@ -0,0 +1,142 @@
+package cloudmetrics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/client-go/rest"
+)
+
+// CloudNode is a node of the cluster, the VM backing it and the persistent volumes attached to it.
+type CloudNode struct {
+	NodeName string
+	// ProviderID is the spec.providerID of the node, which providers parse into their VM identifiers.
+	ProviderID string
+	Disks      []CloudDisk
+}
+
+// CloudDisk is a CSI persistent volume attached to a node.  The boot disk of the VM is not listed, providers collect
+// its metrics as part of the VM.
+type CloudDisk struct {
+	// Name is the name of the persistent volume.
+	Name   string
+	Driver string
+	// VolumeHandle is the CSI volume handle, which providers parse into their disk identifiers.
+	VolumeHandle string
+}
+
+// Threshold says when the value of a metric is extrenuous.
+type Threshold struct {
+	Metric string
+	Value  float64
+	// Below means the metric is extrenuous under the value, like a credit balance running out.
+	Below bool
+}
+
+func (t Threshold) crossed(value float64) bool {
+	if t.Below {
+		return value < t.Value
+	}
+	return value > t.Value
+}
+
+// Sample is the value of a metric over the period ending at Timestamp.
+type Sample struct {
+	Timestamp time.Time
+	Value     float64
+}
+
+// Series holds the samples of one metric of a VM, or of one of its disks when Disk is set.  Every sample covers the
+// Period ending at its timestamp.
+type Series struct {
+	NodeName string
+	Disk     string
+	Metric   string
+	Period   time.Duration
+	Samples  []Sample
+}
+
+// CloudMetricsProvider collects the metrics of the VMs and disks of a cluster from its cloud.
+type CloudMetricsProvider interface {
+	// Thresholds are the metrics the provider collects and when they are extrenuous.
+	Thresholds() []Threshold
+	// CollectSeries returns the series of every threshold metric for the VMs of the nodes and their disks between
+	// beginning and end.
+	CollectSeries(ctx context.Context, nodes []CloudNode, beginning, end time.Time) ([]Series, error)
+}
+
+// NewProviderFunc creates the provider of a platform.  Errors are reported as flakes, cloud credentials and API quota
+// are not something the run can fix.
+type NewProviderFunc func(ctx context.Context, adminRESTConfig *rest.Config, infrastructure *configv1.Infrastructure) (CloudMetricsProvider, error)
+
+// IntervalsFromSeries builds an interval for every run of consecutive samples that cross the threshold of their metric.
+func IntervalsFromSeries(series []Series, thresholds []Threshold) monitorapi.Intervals {
+	thresholdsByMetric := map[string]Threshold{}
+	for _, threshold := range thresholds {
+		thresholdsByMetric[threshold.Metric] = threshold
+	}
+
+	ret := monitorapi.Intervals{}
+	for _, currSeries := range series {
+		threshold, ok := thresholdsByMetric[currSeries.Metric]
+		if !ok {
+			continue
+		}
+		samples := make([]Sample, len(currSeries.Samples))
+		copy(samples, currSeries.Samples)
+		sort.Slice(samples, func(i, j int) bool {
+			return samples[i].Timestamp.Before(samples[j].Timestamp)
+		})
+
+		var from, to time.Time
+		var peak float64
+		flush := func() {
+			if from.IsZero() {
+				return
+			}
+			locator := monitorapi.NewLocator().CloudNodeMetric(currSeries.NodeName, currSeries.Metric)
+			if len(currSeries.Disk) > 0 {
+				locator = monitorapi.NewLocator().CloudDiskMetric(currSeries.NodeName, currSeries.Disk, currSeries.Metric)
+			}
+			direction := "over"
+			if threshold.Below {
+				direction = "under"
+			}
+			ret = append(ret, monitorapi.NewInterval(monitorapi.SourceCloudMetrics, monitorapi.Warning).
+				Locator(locator).
+				Message(monitorapi.NewMessage().Reason(monitorapi.CloudMetricsExtrenuous).
+					HumanMessage(fmt.Sprintf("Value of metric %s was %s the threshold of %.2f, peaking at %.2f", currSeries.Metric, direction, threshold.Value, peak))).
+				Display().
+				Build(from, to),
+			)
+			from = time.Time{}
+		}
+
+		for _, sample := range samples {
+			if !threshold.crossed(sample.Value) {
+				flush()
+				continue
+			}
+			start := sample.Timestamp.Add(-currSeries.Period)
+			if !from.IsZero() && start.After(to) {
+				// a missing sample ends the run
+				flush()
+			}
+			if from.IsZero() {
+				from = start
+				peak = sample.Value
+			}
+			if threshold.Below && sample.Value < peak || !threshold.Below && sample.Value > peak {
+				peak = sample.Value
+			}
+			to = sample.Timestamp
+		}
+		flush()
+	}
+	sort.Sort(ret)
+	return ret
+}
@@ -0,0 +1,99 @@
+package cloudmetrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestIntervalsFromSeries(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	minute := func(i int) time.Time {
+		return start.Add(time.Duration(i) * time.Minute)
+	}
+	thresholds := []Threshold{
+		{Metric: "VolumeQueueLength", Value: 10},
+		{Metric: "BurstBalance", Value: 20, Below: true},
+	}
+
+	tests := []struct {
+		name          string
+		series        []Series
+		wantLocators  []string
+		wantFrom      []time.Time
+		wantTo        []time.Time
+		wantMessageOf []string
+	}{
+		{
+			name: "consecutive samples over the threshold are merged",
+			series: []Series{{
+				NodeName: "node-a", Metric: "VolumeQueueLength", Period: time.Minute,
+				Samples: []Sample{{minute(1), 1}, {minute(3), 12}, {minute(2), 11}, {minute(4), 2}, {minute(6), 30}},
+			}},
+			wantLocators:  []string{"node-a", "node-a"},
+			wantFrom:      []time.Time{minute(1), minute(5)},
+			wantTo:        []time.Time{minute(3), minute(6)},
+			wantMessageOf: []string{"over the threshold of 10.00, peaking at 12.00", "over the threshold of 10.00, peaking at 30.00"},
+		},
+		{
+			name: "a missing sample ends the run",
+			series: []Series{{
+				NodeName: "node-a", Metric: "VolumeQueueLength", Period: time.Minute,
+				Samples: []Sample{{minute(1), 11}, {minute(3), 12}},
+			}},
+			wantLocators:  []string{"node-a", "node-a"},
+			wantFrom:      []time.Time{minute(0), minute(2)},
+			wantTo:        []time.Time{minute(1), minute(3)},
+			wantMessageOf: []string{"peaking at 11.00", "peaking at 12.00"},
+		},
+		{
+			name: "balances are extrenuous below the threshold and disks are located",
+			series: []Series{{
+				NodeName: "node-b", Disk: "pvc-1", Metric: "BurstBalance", Period: 5 * time.Minute,
+				Samples: []Sample{{minute(5), 50}, {minute(10), 15}, {minute(15), 5}},
+			}},
+			wantLocators:  []string{"pvc-1"},
+			wantFrom:      []time.Time{minute(5)},
+			wantTo:        []time.Time{minute(15)},
+			wantMessageOf: []string{"under the threshold of 20.00, peaking at 5.00"},
+		},
+		{
+			name: "metrics without a threshold are ignored",
+			series: []Series{{
+				NodeName: "node-a", Metric: "Unknown", Period: time.Minute,
+				Samples: []Sample{{minute(1), 100}},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			intervals := IntervalsFromSeries(tt.series, thresholds)
+			if len(intervals) != len(tt.wantFrom) {
+				t.Fatalf("expected %d intervals, got %d: %v", len(tt.wantFrom), len(intervals), intervals)
+			}
+			for i, interval := range intervals {
+				if interval.Source != monitorapi.SourceCloudMetrics {
+					t.Errorf("interval %d: unexpected source %q", i, interval.Source)
+				}
+				if interval.Message.Reason != monitorapi.CloudMetricsExtrenuous {
+					t.Errorf("interval %d: unexpected reason %q", i, interval.Message.Reason)
+				}
+				if !interval.From.Equal(tt.wantFrom[i]) || !interval.To.Equal(tt.wantTo[i]) {
+					t.Errorf("interval %d: expected %v to %v, got %v to %v", i, tt.wantFrom[i], tt.wantTo[i], interval.From, interval.To)
+				}
+				if len(tt.series[0].Disk) > 0 {
+					if disk := interval.Locator.Keys[monitorapi.LocatorDiskKey]; disk != tt.wantLocators[i] {
+						t.Errorf("interval %d: expected disk %q, got %q", i, tt.wantLocators[i], disk)
+					}
+				} else if node := interval.Locator.Keys[monitorapi.LocatorNodeKey]; node != tt.wantLocators[i] {
+					t.Errorf("interval %d: expected node %q, got %q", i, tt.wantLocators[i], node)
+				}
+				if !strings.Contains(interval.Message.HumanMessage, tt.wantMessageOf[i]) {
+					t.Errorf("interval %d: expected message to contain %q, got %q", i, tt.wantMessageOf[i], interval.Message.HumanMessage)
+				}
+			}
+		})
+	}
+}
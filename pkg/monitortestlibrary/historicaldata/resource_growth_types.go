@@ -0,0 +1,118 @@
+package historicaldata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/sirupsen/logrus"
+)
+
+// ResourceGrowthStatisticalData holds the percentiles of how much the object count of a resource grew over a job run,
+// as the ratio of the count at the end to the count at the beginning.
+type ResourceGrowthStatisticalData struct {
+	ResourceGrowthDataKey `json:",inline"`
+	P95                   float64
+	P99                   float64
+	JobRuns               int64
+}
+
+type ResourceGrowthDataKey struct {
+	// Resource is the plural resource qualified with its group, like the apiserver_storage_objects metric labels it,
+	// for instance secrets or replicasets.apps.
+	Resource string
+
+	platformidentification.JobType `json:",inline"`
+}
+
+type ResourceGrowthBestMatcher struct {
+	HistoricalData map[ResourceGrowthDataKey]ResourceGrowthStatisticalData
+}
+
+func NewResourceGrowthMatcher(historicalJSON []byte) (*ResourceGrowthBestMatcher, error) {
+	historicalData := map[ResourceGrowthDataKey]ResourceGrowthStatisticalData{}
+
+	inFile := bytes.NewBuffer(historicalJSON)
+	jsonDecoder := json.NewDecoder(inFile)
+
+	type DecodingPercentile struct {
+		ResourceGrowthDataKey `json:",inline"`
+		P95                   string
+		P99                   string
+		JobRuns               int64
+	}
+	decodingPercentilesList := []DecodingPercentile{}
+
+	if err := jsonDecoder.Decode(&decodingPercentilesList); err != nil {
+		return nil, err
+	}
+
+	for _, currDecoded := range decodingPercentilesList {
+		p95, err := strconv.ParseFloat(currDecoded.P95, 64)
+		if err != nil {
+			return nil, err
+		}
+		p99, err := strconv.ParseFloat(currDecoded.P99, 64)
+		if err != nil {
+			return nil, err
+		}
+		curr := ResourceGrowthStatisticalData{
+			ResourceGrowthDataKey: currDecoded.ResourceGrowthDataKey,
+			P95:                   p95,
+			P99:                   p99,
+			JobRuns:               currDecoded.JobRuns,
+		}
+		historicalData[curr.ResourceGrowthDataKey] = curr
+	}
+
+	return &ResourceGrowthBestMatcher{
+		HistoricalData: historicalData,
+	}, nil
+}
+
+func NewResourceGrowthMatcherWithHistoricalData(data map[ResourceGrowthDataKey]ResourceGrowthStatisticalData) *ResourceGrowthBestMatcher {
+	return &ResourceGrowthBestMatcher{
+		HistoricalData: data,
+	}
+}
+
+func (b *ResourceGrowthBestMatcher) bestMatch(key ResourceGrowthDataKey) (ResourceGrowthStatisticalData, string, error) {
+	exactMatchKey := key
+	logrus.WithField("resource", key.Resource).WithField("entries", len(b.HistoricalData)).
+		Debugf("searching for best match for %+v", key.JobType)
+
+	if percentiles, ok := b.HistoricalData[exactMatchKey]; ok && percentiles.JobRuns >= defaultMinJobRuns {
+		return percentiles, "", nil
+	}
+
+	for _, nextBestGuesser := range nextBestGuessers {
+		nextBestJobType, ok := nextBestGuesser(exactMatchKey.JobType)
+		if !ok {
+			continue
+		}
+		nextBestMatchKey := ResourceGrowthDataKey{
+			Resource: exactMatchKey.Resource,
+			JobType:  nextBestJobType,
+		}
+		if percentiles, ok := b.HistoricalData[nextBestMatchKey]; ok && percentiles.JobRuns >= defaultMinJobRuns {
+			return percentiles, fmt.Sprintf("(no exact match for %#v, fell back to %#v)", exactMatchKey, nextBestMatchKey), nil
+		}
+	}
+
+	// Unlike disruption, no data does not skip the test, the caller falls back to a default growth allowance.
+	return ResourceGrowthStatisticalData{},
+		fmt.Sprintf("(no exact or fuzzy match for resource=%s jobType=%#v)", key.Resource, key.JobType),
+		nil
+}
+
+// BestMatchP99 returns the P99 growth ratio of the best match for the resource and job type, or nil if there is no
+// historical data for it.
+func (b *ResourceGrowthBestMatcher) BestMatchP99(key ResourceGrowthDataKey) (*float64, string, error) {
+	rawData, details, err := b.bestMatch(key)
+	if rawData == (ResourceGrowthStatisticalData{}) {
+		return nil, details, err
+	}
+	return &rawData.P99, details, err
+}
@@ -0,0 +1,200 @@
+package logrules
+
+import (
+	"bufio"
+	"bytes"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Line is a line of a log along with when it was logged and the locator of what logged it.
+type Line struct {
+	Time    time.Time
+	Locator monitorapi.Locator
+	Text    string
+}
+
+// Stream turns the lines of one log into intervals.  Paired rules carry state from one line to the next, so every
+// log, the journal of a unit on one node or one container, needs a stream of its own.
+type Stream struct {
+	rules []Rule
+	open  map[openKey]openInterval
+}
+
+type openKey struct {
+	rule string
+	key  string
+}
+
+type openInterval struct {
+	builder *monitorapi.IntervalBuilder
+	from    time.Time
+}
+
+func NewStream(rules []Rule) *Stream {
+	return &Stream{
+		rules: rules,
+		open:  map[openKey]openInterval{},
+	}
+}
+
+// HandleLine returns the intervals of the rules that match the line, and of the pairs it ends.
+func (s *Stream) HandleLine(line Line) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	for _, rule := range s.rules {
+		if rule.Pair == nil {
+			match := rule.regex.FindStringSubmatch(line.Text)
+			if match == nil {
+				continue
+			}
+			ret = append(ret, rule.intervalBuilder(line, match).Build(line.Time, line.Time.Add(rule.duration)))
+			continue
+		}
+
+		if match := rule.endRegex.FindStringSubmatch(line.Text); match != nil {
+			key := openKey{rule: rule.Name, key: pairKey(rule.endRegex.SubexpNames(), match, rule.Pair.Key)}
+			if open, ok := s.open[key]; ok {
+				ret = append(ret, open.builder.Build(open.from, line.Time))
+				delete(s.open, key)
+			}
+			continue
+		}
+		if match := rule.regex.FindStringSubmatch(line.Text); match != nil {
+			key := openKey{rule: rule.Name, key: pairKey(rule.regex.SubexpNames(), match, rule.Pair.Key)}
+			if _, ok := s.open[key]; ok {
+				// the interval already started, repeated start lines do not restart it
+				continue
+			}
+			s.open[key] = openInterval{builder: rule.intervalBuilder(line, match), from: line.Time}
+		}
+	}
+	return ret
+}
+
+// Close returns the pairs that were started but never ended, ending at end.  A zero end leaves them open.
+func (s *Stream) Close(end time.Time) monitorapi.Intervals {
+	keys := make([]openKey, 0, len(s.open))
+	for key := range s.open {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].rule != keys[j].rule {
+			return keys[i].rule < keys[j].rule
+		}
+		return keys[i].key < keys[j].key
+	})
+
+	ret := monitorapi.Intervals{}
+	for _, key := range keys {
+		open := s.open[key]
+		ret = append(ret, open.builder.Build(open.from, end))
+		delete(s.open, key)
+	}
+	return ret
+}
+
+func pairKey(names, match []string, key []string) string {
+	values := []string{}
+	for _, capture := range key {
+		values = append(values, submatch(names, match, capture))
+	}
+	return strings.Join(values, "\x00")
+}
+
+func submatch(names, match []string, capture string) string {
+	for i, name := range names {
+		if name == capture {
+			return match[i]
+		}
+	}
+	return ""
+}
+
+func (r Rule) intervalBuilder(line Line, match []string) *monitorapi.IntervalBuilder {
+	names := r.regex.SubexpNames()
+
+	locator := monitorapi.Locator{
+		Type: line.Locator.Type,
+		Keys: map[monitorapi.LocatorKey]string{},
+	}
+	for k, v := range line.Locator.Keys {
+		locator.Keys[k] = v
+	}
+	if r.Locator != nil {
+		if len(r.Locator.Type) > 0 {
+			locator.Type = r.Locator.Type
+		}
+		for key, capture := range r.Locator.Keys {
+			locator.Keys[key] = submatch(names, match, capture)
+		}
+	}
+
+	message := monitorapi.NewMessage()
+	if len(r.Reason) > 0 {
+		message = message.Reason(r.Reason)
+	}
+	for annotation, capture := range r.Annotations {
+		message = message.WithAnnotation(annotation, submatch(names, match, capture))
+	}
+	if len(r.HumanMessage) > 0 {
+		message = message.HumanMessage(submatch(names, match, r.HumanMessage))
+	} else {
+		message = message.HumanMessage(line.Text)
+	}
+
+	builder := monitorapi.NewInterval(r.IntervalSource, r.level).
+		Locator(locator).
+		Message(message)
+	if r.Display {
+		builder = builder.Display()
+	}
+	return builder
+}
+
+// IntervalsFromJournal matches the rules of a systemd unit against its journal on a node, as read by GetNodeLog.
+// Pairs that are still open at the end of the journal are left open.
+func IntervalsFromJournal(rules []Rule, systemdUnit, nodeName string, journal []byte) monitorapi.Intervals {
+	stream := NewStream(RulesForSystemdUnit(rules, systemdUnit))
+	if len(stream.rules) == 0 {
+		return nil
+	}
+	nodeLocator := monitorapi.NewLocator().NodeFromName(nodeName)
+
+	ret := monitorapi.Intervals{}
+	scanner := bufio.NewScanner(bytes.NewBuffer(journal))
+	for scanner.Scan() {
+		currLine := scanner.Text()
+		ret = append(ret, stream.HandleLine(Line{
+			Time:    SystemdJournalLogTime(currLine),
+			Locator: nodeLocator,
+			Text:    currLine,
+		})...)
+	}
+	return append(ret, stream.Close(time.Time{})...)
+}
+
+// RulesForSystemdUnit returns the rules that read the journal of the unit.
+func RulesForSystemdUnit(rules []Rule, systemdUnit string) []Rule {
+	var ret []Rule
+	for _, rule := range rules {
+		if rule.Source.SystemdUnit == systemdUnit {
+			ret = append(ret, rule)
+		}
+	}
+	return ret
+}
+
+// SystemdUnits returns the units the rules read the journal of.
+func SystemdUnits(rules []Rule) []string {
+	units := sets.New[string]()
+	for _, rule := range rules {
+		if len(rule.Source.SystemdUnit) > 0 {
+			units.Insert(rule.Source.SystemdUnit)
+		}
+	}
+	return sets.List(units)
+}
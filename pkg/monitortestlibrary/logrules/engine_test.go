@@ -0,0 +1,125 @@
+package logrules
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+const testRules = `
+rules:
+- name: image-pull
+  source: {systemdUnit: crio}
+  regex: 'Pulling image: (?P<image>\S+)'
+  locator:
+    keys:
+      image: image
+  humanMessage: image
+  level: Info
+  reason: ImagePull
+  intervalSource: NodeState
+  display: true
+  pair:
+    endRegex: 'Pulled image: (?P<image>\S+)'
+    key: [image]
+- name: oom
+  source: {systemdUnit: crio}
+  regex: 'oom killed (?P<pid>\d+)'
+  annotations:
+    pid: pid
+  level: Error
+  intervalSource: NodeState
+  duration: 1s
+- name: kubelet
+  source: {systemdUnit: kubelet}
+  regex: 'oom'
+  intervalSource: KubeletLog
+`
+
+func TestIntervalsFromJournal(t *testing.T) {
+	rules, err := RulesFromYAML([]byte(testRules))
+	if err != nil {
+		t.Fatal(err)
+	}
+	journal := strings.Join([]string{
+		`Sep 27 08:00:00.000000 node-0 crio[1]: Pulling image: quay.io/a`,
+		`Sep 27 08:00:01.000000 node-0 crio[1]: Pulling image: quay.io/b`,
+		// repeated start lines do not restart the interval
+		`Sep 27 08:00:02.000000 node-0 crio[1]: Pulling image: quay.io/a`,
+		`Sep 27 08:00:03.000000 node-0 crio[1]: oom killed 42`,
+		`Sep 27 08:00:05.000000 node-0 crio[1]: Pulled image: quay.io/a`,
+	}, "\n")
+
+	intervals := IntervalsFromJournal(rules, "crio", "node-0", []byte(journal))
+	if len(intervals) != 3 {
+		t.Fatalf("expected 3 intervals, got %d:\n%s", len(intervals), strings.Join(intervals.Strings(), "\n"))
+	}
+
+	oom := intervals[0]
+	if oom.Level != monitorapi.Error || oom.Message.Annotations["pid"] != "42" || oom.Message.HumanMessage != `Sep 27 08:00:03.000000 node-0 crio[1]: oom killed 42` {
+		t.Errorf("unexpected oom interval %s", oom)
+	}
+	if oom.To.Sub(oom.From) != time.Second {
+		t.Errorf("expected the oom interval to last 1s, got %s", oom.To.Sub(oom.From))
+	}
+
+	pullA := intervals[1]
+	if pullA.Locator.Keys[monitorapi.LocatorNodeKey] != "node-0" || pullA.Locator.Keys["image"] != "quay.io/a" {
+		t.Errorf("unexpected locator %s", pullA.Locator.OldLocator())
+	}
+	if pullA.Message.Reason != "ImagePull" || pullA.Message.HumanMessage != "quay.io/a" || !pullA.Display {
+		t.Errorf("unexpected image pull interval %s", pullA)
+	}
+	if pullA.To.Sub(pullA.From) != 5*time.Second {
+		t.Errorf("expected the pull of quay.io/a to last 5s, got %s", pullA.To.Sub(pullA.From))
+	}
+
+	// the pull of quay.io/b never ended
+	pullB := intervals[2]
+	if pullB.Locator.Keys["image"] != "quay.io/b" || !pullB.To.IsZero() {
+		t.Errorf("expected the pull of quay.io/b to be left open, got %s", pullB)
+	}
+}
+
+func TestStreamClose(t *testing.T) {
+	rules, err := RulesFromYAML([]byte(testRules))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Minute)
+	locator := monitorapi.NewLocator().ContainerFromNames("openshift-etcd", "etcd-0", "uid", "etcd")
+
+	stream := NewStream(rules)
+	if intervals := stream.HandleLine(Line{Time: start, Locator: locator, Text: "Pulling image: quay.io/a"}); len(intervals) != 0 {
+		t.Errorf("expected the start line to open an interval, got %s", strings.Join(intervals.Strings(), "\n"))
+	}
+	intervals := stream.Close(end)
+	if len(intervals) != 1 {
+		t.Fatalf("expected the open interval to be closed, got %d", len(intervals))
+	}
+	if !intervals[0].From.Equal(start) || !intervals[0].To.Equal(end) {
+		t.Errorf("expected the interval to end at %s, got %s", end, intervals[0])
+	}
+	if intervals[0].Locator.Keys[monitorapi.LocatorPodKey] != "etcd-0" {
+		t.Errorf("expected the container locator to be kept, got %s", intervals[0].Locator.OldLocator())
+	}
+	if intervals := stream.Close(end); len(intervals) != 0 {
+		t.Errorf("expected nothing to be left open, got %d", len(intervals))
+	}
+}
+
+func TestSystemdUnits(t *testing.T) {
+	rules, err := RulesFromYAML([]byte(testRules))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if units := SystemdUnits(rules); strings.Join(units, ",") != "crio,kubelet" {
+		t.Errorf("unexpected units %v", units)
+	}
+	if sources, _ := PodSources(rules); len(sources) != 0 {
+		t.Errorf("unexpected pod sources %v", sources)
+	}
+}
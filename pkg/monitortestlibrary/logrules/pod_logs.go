@@ -0,0 +1,78 @@
+package logrules
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/podaccess"
+)
+
+// PodLogHandler is a podaccess.LogHandler that records the intervals of rules matching the lines streamed by a
+// PodsStreamer.  Every container gets a stream of its own.
+type PodLogHandler struct {
+	recorder monitorapi.RecorderWriter
+	rules    []Rule
+
+	lock    sync.Mutex
+	streams map[string]*Stream
+}
+
+func NewPodLogHandler(recorder monitorapi.RecorderWriter, rules []Rule) *PodLogHandler {
+	return &PodLogHandler{
+		recorder: recorder,
+		rules:    rules,
+		streams:  map[string]*Stream{},
+	}
+}
+
+func (h *PodLogHandler) HandleLogLine(logLine podaccess.LogLineContent) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	key := logLine.Locator.OldLocator()
+	stream, ok := h.streams[key]
+	if !ok {
+		stream = NewStream(h.rules)
+		h.streams[key] = stream
+	}
+	h.recorder.AddIntervals(stream.HandleLine(Line{
+		Time:    logLine.Instant,
+		Locator: logLine.Locator,
+		Text:    logLine.Line,
+	})...)
+}
+
+// Close records the pairs that were started but never ended, ending at end.  Call it once the PodsStreamer finished.
+func (h *PodLogHandler) Close(end time.Time) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	keys := make([]string, 0, len(h.streams))
+	for key := range h.streams {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		h.recorder.AddIntervals(h.streams[key].Close(end)...)
+	}
+	h.streams = map[string]*Stream{}
+}
+
+// PodSources returns the distinct pod sources of the rules, and the rules reading each.
+func PodSources(rules []Rule) ([]Pods, map[Pods][]Rule) {
+	var sources []Pods
+	rulesBySource := map[Pods][]Rule{}
+	for _, rule := range rules {
+		if rule.Source.Pod == nil {
+			continue
+		}
+		source := *rule.Source.Pod
+		if _, ok := rulesBySource[source]; !ok {
+			sources = append(sources, source)
+		}
+		rulesBySource[source] = append(rulesBySource[source], rule)
+	}
+	return sources, rulesBySource
+}
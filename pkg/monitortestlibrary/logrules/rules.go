@@ -0,0 +1,216 @@
+package logrules
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+// RulesFile is the serialized form of a list of rules.
+type RulesFile struct {
+	Rules []RuleDefinition `json:"rules"`
+}
+
+// RuleDefinition turns the lines of a log that match its regex into intervals.
+type RuleDefinition struct {
+	// Name identifies the rule in errors and tests.
+	Name   string    `json:"name"`
+	Source LogSource `json:"source"`
+	// Regex is matched against every line.  Its named captures are referenced by Locator, Annotations and HumanMessage.
+	Regex string `json:"regex"`
+
+	// Locator sets keys of the locator from captures, the locator otherwise is the node of a systemd unit, or the
+	// container of a pod log.
+	Locator *LocatorMapping `json:"locator,omitempty"`
+	// Annotations maps annotation keys to the names of the captures that hold their values.
+	Annotations map[monitorapi.AnnotationKey]string `json:"annotations,omitempty"`
+	// HumanMessage is the name of the capture that holds the message, it defaults to the whole line.
+	HumanMessage string `json:"humanMessage,omitempty"`
+
+	// Level is Info, the default, Warning or Error.
+	Level          string                    `json:"level,omitempty"`
+	Reason         monitorapi.IntervalReason `json:"reason,omitempty"`
+	IntervalSource monitorapi.IntervalSource `json:"intervalSource"`
+	// Duration is the length of intervals from a single line, it defaults to none.
+	Duration string `json:"duration,omitempty"`
+	// Display shows the intervals on the timeline.
+	Display bool `json:"display,omitempty"`
+
+	// Pair makes a line matching Regex start an interval that the next line matching Pair.EndRegex ends.
+	Pair *Pair `json:"pair,omitempty"`
+}
+
+// LogSource is where a rule reads lines from, either a systemd unit on every node or a container of some pods.
+type LogSource struct {
+	SystemdUnit string `json:"systemdUnit,omitempty"`
+	Pod         *Pods  `json:"pod,omitempty"`
+}
+
+type Pods struct {
+	Namespace     string `json:"namespace"`
+	LabelSelector string `json:"labelSelector"`
+	Container     string `json:"container"`
+}
+
+type LocatorMapping struct {
+	// Type replaces the type of the locator.
+	Type monitorapi.LocatorType `json:"type,omitempty"`
+	// Keys maps locator keys to the names of the captures that hold their values.
+	Keys map[monitorapi.LocatorKey]string `json:"keys,omitempty"`
+}
+
+type Pair struct {
+	EndRegex string `json:"endRegex"`
+	// Key names the captures that must be equal on the start and end lines, so that concurrent intervals, of
+	// different pods for instance, are not mixed up.
+	Key []string `json:"key,omitempty"`
+}
+
+// Rule is a validated RuleDefinition with its regexes compiled.
+type Rule struct {
+	RuleDefinition
+
+	regex    *regexp.Regexp
+	endRegex *regexp.Regexp
+	level    monitorapi.IntervalLevel
+	duration time.Duration
+}
+
+// RulesFromFile reads and validates a RulesFile.
+func RulesFromFile(filename string) ([]Rule, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := RulesFromYAML(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return rules, nil
+}
+
+// RulesFromYAML parses a RulesFile.  Unknown fields are rejected so a typo does not silently change what is matched.
+func RulesFromYAML(data []byte) ([]Rule, error) {
+	file := &RulesFile{}
+	if err := yaml.UnmarshalStrict(data, file); err != nil {
+		return nil, err
+	}
+
+	names := sets.New[string]()
+	var rules []Rule
+	for _, definition := range file.Rules {
+		if len(definition.Name) == 0 {
+			return nil, fmt.Errorf("every rule must have a name")
+		}
+		if names.Has(definition.Name) {
+			return nil, fmt.Errorf("%q is defined more than once", definition.Name)
+		}
+		names.Insert(definition.Name)
+
+		rule, err := definition.toRule()
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", definition.Name, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// MustRulesFromYAML is RulesFromYAML for rules embedded in the binary.
+func MustRulesFromYAML(data []byte) []Rule {
+	rules, err := RulesFromYAML(data)
+	if err != nil {
+		panic(err)
+	}
+	return rules
+}
+
+func (d RuleDefinition) toRule() (Rule, error) {
+	rule := Rule{RuleDefinition: d}
+
+	switch {
+	case len(d.Source.SystemdUnit) > 0 && d.Source.Pod != nil:
+		return Rule{}, fmt.Errorf("source must be either a systemdUnit or a pod")
+	case len(d.Source.SystemdUnit) == 0 && d.Source.Pod == nil:
+		return Rule{}, fmt.Errorf("source must specify a systemdUnit or a pod")
+	case d.Source.Pod != nil:
+		if len(d.Source.Pod.Namespace) == 0 || len(d.Source.Pod.Container) == 0 {
+			return Rule{}, fmt.Errorf("pod sources must specify a namespace and container")
+		}
+		if _, err := labels.Parse(d.Source.Pod.LabelSelector); err != nil {
+			return Rule{}, fmt.Errorf("invalid labelSelector: %w", err)
+		}
+	}
+
+	if len(d.Regex) == 0 {
+		return Rule{}, fmt.Errorf("regex must not be empty")
+	}
+	var err error
+	if rule.regex, err = regexp.Compile(d.Regex); err != nil {
+		return Rule{}, fmt.Errorf("invalid regex: %w", err)
+	}
+	captures := sets.New[string](rule.regex.SubexpNames()...)
+	captureNames := []string{}
+	if len(d.HumanMessage) > 0 {
+		captureNames = append(captureNames, d.HumanMessage)
+	}
+	for _, capture := range d.Annotations {
+		captureNames = append(captureNames, capture)
+	}
+	if d.Locator != nil {
+		for _, capture := range d.Locator.Keys {
+			captureNames = append(captureNames, capture)
+		}
+	}
+	if d.Pair != nil {
+		if len(d.Pair.EndRegex) == 0 {
+			return Rule{}, fmt.Errorf("pair.endRegex must not be empty")
+		}
+		if rule.endRegex, err = regexp.Compile(d.Pair.EndRegex); err != nil {
+			return Rule{}, fmt.Errorf("invalid pair.endRegex: %w", err)
+		}
+		endCaptures := sets.New[string](rule.endRegex.SubexpNames()...)
+		for _, capture := range d.Pair.Key {
+			if len(capture) == 0 || !captures.Has(capture) || !endCaptures.Has(capture) {
+				return Rule{}, fmt.Errorf("pair key %q must be a named capture of both regex and pair.endRegex", capture)
+			}
+		}
+	}
+	for _, capture := range captureNames {
+		if len(capture) == 0 || !captures.Has(capture) {
+			return Rule{}, fmt.Errorf("%q is not a named capture of regex", capture)
+		}
+	}
+
+	switch d.Level {
+	case "", "Info":
+		rule.level = monitorapi.Info
+	case "Warning":
+		rule.level = monitorapi.Warning
+	case "Error":
+		rule.level = monitorapi.Error
+	default:
+		return Rule{}, fmt.Errorf("unknown level %q, expected Info, Warning or Error", d.Level)
+	}
+	if len(d.IntervalSource) == 0 {
+		return Rule{}, fmt.Errorf("intervalSource must not be empty")
+	}
+	if len(d.Duration) > 0 {
+		if d.Pair != nil {
+			return Rule{}, fmt.Errorf("duration is not valid with pair, paired intervals last until their end line")
+		}
+		if rule.duration, err = time.ParseDuration(d.Duration); err != nil {
+			return Rule{}, fmt.Errorf("invalid duration: %w", err)
+		}
+		if rule.duration < 0 {
+			return Rule{}, fmt.Errorf("duration must not be negative")
+		}
+	}
+	return rule, nil
+}
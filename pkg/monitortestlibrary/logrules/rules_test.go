@@ -0,0 +1,128 @@
+package logrules
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRulesFromYAML_invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		rules       string
+		expectedErr string
+	}{
+		{
+			name: "unknown field",
+			rules: `
+rules:
+- name: a
+  source: {systemdUnit: kubelet}
+  regex: foo
+  intervalSource: KubeletLog
+  levle: Warning
+`,
+			expectedErr: `unknown field "levle"`,
+		},
+		{
+			name: "duplicate name",
+			rules: `
+rules:
+- name: a
+  source: {systemdUnit: kubelet}
+  regex: foo
+  intervalSource: KubeletLog
+- name: a
+  source: {systemdUnit: crio}
+  regex: bar
+  intervalSource: KubeletLog
+`,
+			expectedErr: `"a" is defined more than once`,
+		},
+		{
+			name: "no source",
+			rules: `
+rules:
+- name: a
+  regex: foo
+  intervalSource: KubeletLog
+`,
+			expectedErr: "source must specify a systemdUnit or a pod",
+		},
+		{
+			name: "both sources",
+			rules: `
+rules:
+- name: a
+  source:
+    systemdUnit: kubelet
+    pod: {namespace: openshift-etcd, labelSelector: app=etcd, container: etcd}
+  regex: foo
+  intervalSource: KubeletLog
+`,
+			expectedErr: "source must be either a systemdUnit or a pod",
+		},
+		{
+			name: "missing capture",
+			rules: `
+rules:
+- name: a
+  source: {systemdUnit: kubelet}
+  regex: 'pod (?P<pod>\S+)'
+  humanMessage: message
+  intervalSource: KubeletLog
+`,
+			expectedErr: `"message" is not a named capture of regex`,
+		},
+		{
+			name: "pair key missing from end",
+			rules: `
+rules:
+- name: a
+  source: {systemdUnit: kubelet}
+  regex: 'started (?P<pod>\S+)'
+  intervalSource: KubeletLog
+  pair:
+    endRegex: 'stopped'
+    key: [pod]
+`,
+			expectedErr: `pair key "pod" must be a named capture of both regex and pair.endRegex`,
+		},
+		{
+			name: "paired duration",
+			rules: `
+rules:
+- name: a
+  source: {systemdUnit: kubelet}
+  regex: started
+  intervalSource: KubeletLog
+  duration: 1s
+  pair:
+    endRegex: stopped
+`,
+			expectedErr: "duration is not valid with pair",
+		},
+		{
+			name: "unknown level",
+			rules: `
+rules:
+- name: a
+  source: {systemdUnit: kubelet}
+  regex: foo
+  level: Critical
+  intervalSource: KubeletLog
+`,
+			expectedErr: `unknown level "Critical"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := RulesFromYAML([]byte(tt.rules))
+			if err == nil {
+				t.Fatalf("expected an error containing %q", tt.expectedErr)
+			}
+			if !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("expected an error containing %q, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
@@ -66,3 +66,18 @@ func GetNodeLogFile(ctx context.Context, client kubernetes.Interface, nodeName,
 
 	return ioutil.ReadAll(in)
 }
+
+// StreamNodeLogFileFrom streams a file below /var/log of a node starting at a byte offset, so that growing files can
+// be tailed.  The kubelet serves the files with range support, a request past the end of the file fails as
+// RequestedRangeNotSatisfiable.
+func StreamNodeLogFileFrom(ctx context.Context, client kubernetes.Interface, nodeName, filename string, offset int64) (io.ReadCloser, error) {
+	path := client.CoreV1().RESTClient().Get().
+		Namespace("").Name(nodeName).
+		Resource("nodes").SubResource("proxy", "logs").Suffix(filename).URL().Path
+
+	req := client.CoreV1().RESTClient().Get().RequestURI(path).
+		SetHeader("Accept", "text/plain, */*").
+		SetHeader("Range", fmt.Sprintf("bytes=%d-", offset))
+
+	return req.Stream(ctx)
+}
@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	v1 "github.com/openshift/api/config/v1"
@@ -72,6 +73,11 @@ type SimplePathologicalEventMatcher struct {
 	// topology limits the exception to a specific topology. (e.g. single replica)
 	// This is only considered in the context of Allows, not Matches.
 	topology *v1.TopologyMode
+
+	// expires is an optional date after which this matcher no longer allows the event to repeat. It will
+	// still match so the events continue to be flagged as interesting and charted.
+	// This is only considered in the context of Allows, not Matches.
+	expires *time.Time
 }
 
 func (ade *SimplePathologicalEventMatcher) Name() string {
@@ -125,9 +131,27 @@ func (ade *SimplePathologicalEventMatcher) Allows(i monitorapi.Interval, topolog
 		logrus.WithField("allower", ade.Name).Debugf("cluster did not match topology")
 		return false
 	}
+
+	if ade.expires != nil && time.Now().After(*ade.expires) {
+		if _, warned := warnedExpiredMatchers.LoadOrStore(ade.Name(), true); !warned {
+			logrus.WithField("allower", ade.Name()).Warningf("matcher expired on %s", ade.expires.Format(time.DateOnly))
+		}
+		return false
+	}
 	return true
 }
 
+// warnedExpiredMatchers holds the names of the expired matchers that were already logged.  Allows is called for
+// every repeating event and registries are rebuilt for every set of intervals, so the warning is only logged once per
+// matcher name.
+var warnedExpiredMatchers sync.Map
+
+// repeatThreshold returns the number of times an event may repeat and still be allowed by this matcher, or 0 when
+// the matcher does not override the threshold.
+func (ade *SimplePathologicalEventMatcher) repeatThreshold() int {
+	return ade.repeatThresholdOverride
+}
+
 type AllowedPathologicalEventRegistry struct {
 	matchers map[string]EventMatcher
 }
@@ -184,6 +208,18 @@ func (r *AllowedPathologicalEventRegistry) AllowedByAny(
 	return false, nil
 }
 
+// Matchers returns all registered matchers sorted by name.
+func (r *AllowedPathologicalEventRegistry) Matchers() []EventMatcher {
+	ret := make([]EventMatcher, 0, len(r.matchers))
+	for _, m := range r.matchers {
+		ret = append(ret, m)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name() < ret[j].Name()
+	})
+	return ret
+}
+
 func (r *AllowedPathologicalEventRegistry) GetMatcherByName(name string) (EventMatcher, error) {
 
 	matcher, ok := r.matchers[name]
@@ -407,11 +443,6 @@ func NewUniversalPathologicalEventMatchers(kubeConfig *rest.Config, finalInterva
 		jira: "https://bugzilla.redhat.com/show_bug.cgi?id=2017435",
 	})
 
-	registry.AddPathologicalEventMatcherOrDie(&SimplePathologicalEventMatcher{
-		name:              "MessageChangedFromFEFF",
-		messageHumanRegex: regexp.MustCompile(`message changed from "\\ufeff`),
-	})
-
 	// This was originally intended to be limited to only during the openshift/build test suite, however it was
 	// never hooked up and was just ignored everywhere. We do not have the capability to detect if
 	// events were within specific test suites yet. Leaving them as an always allow for now.
@@ -433,18 +464,6 @@ func NewUniversalPathologicalEventMatchers(kubeConfig *rest.Config, finalInterva
 		neverAllow:        true,
 	})
 
-	// There is an "event leak" for RecreatingTerminatedPod/RecreatingFailedPod/SuccessfulDelete
-	// events on Statefulsets. Two of those started to be heavily emitted in Kube v1.29
-	// Ignore them until https://issues.redhat.com/browse/OCPBUGS-27262 is fixed
-	registry.AddPathologicalEventMatcherOrDie(&SimplePathologicalEventMatcher{
-		name: "LeakyStatefulsetEvents",
-		locatorKeyRegexes: map[monitorapi.LocatorKey]*regexp.Regexp{
-			monitorapi.LocatorNamespaceKey: regexp.MustCompile(`^openshift-(monitoring|user-workload-monitoring)$`),
-		},
-		messageReasonRegex: regexp.MustCompile(`^RecreatingTerminatedPod|RecreatingFailedPod|SuccessfulDelete$`),
-		messageHumanRegex:  regexp.MustCompile(`.*StatefulSet.*`),
-	})
-
 	registry.AddPathologicalEventMatcherOrDie(AllowBackOffRestartingFailedContainer)
 
 	registry.AddPathologicalEventMatcherOrDie(AllowOVNReadiness)
@@ -465,6 +484,11 @@ func NewUniversalPathologicalEventMatchers(kubeConfig *rest.Config, finalInterva
 	registry.AddPathologicalEventMatcherOrDie(ErrorUpdatingEndpointSlices)
 	registry.AddPathologicalEventMatcherOrDie(MarketplaceStartupProbeFailure)
 
+	// Matchers that need no runtime logic are defined in allowed_pathological_events.yaml.
+	for _, m := range getEmbeddedMatchers().Universal {
+		registry.AddPathologicalEventMatcherOrDie(m)
+	}
+
 	// Inject the dynamic allowance for etcd readiness probe failures based on the number of
 	// etcd revisions the cluster went through.
 	etcdMatcher, err := newDuplicatedEventsAllowedWhenEtcdRevisionChange(context.TODO(), kubeConfig)
@@ -542,6 +566,10 @@ func NewUpgradePathologicalEventMatchers(kubeConfig *rest.Config, finalIntervals
 	m := newFailedSchedulingDuringNodeUpdatePathologicalEventMatcher(finalIntervals)
 	registry.AddPathologicalEventMatcherOrDie(m)
 
+	for _, m := range getEmbeddedMatchers().Upgrade {
+		registry.AddPathologicalEventMatcherOrDie(m)
+	}
+
 	return registry
 }
 
@@ -0,0 +1,179 @@
+package pathologicaleventlibrary
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	v1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+//go:embed allowed_pathological_events.yaml
+var allowedPathologicalEventsYAML []byte
+
+var (
+	readEmbeddedMatchers sync.Once
+	embeddedMatchers     *PathologicalEventMatchers
+)
+
+// getEmbeddedMatchers returns the matchers defined in allowed_pathological_events.yaml.
+func getEmbeddedMatchers() *PathologicalEventMatchers {
+	readEmbeddedMatchers.Do(
+		func() {
+			var err error
+			embeddedMatchers, err = NewPathologicalEventMatchersFromYAML(allowedPathologicalEventsYAML)
+			if err != nil {
+				panic(err)
+			}
+		})
+
+	return embeddedMatchers
+}
+
+// PathologicalEventMatcherFile is the serialized form of a set of SimplePathologicalEventMatchers.
+type PathologicalEventMatcherFile struct {
+	// Universal matchers apply to all jobs.
+	Universal []PathologicalEventMatcherDefinition `json:"universal,omitempty"`
+	// Upgrade matchers only apply to upgrade jobs.
+	Upgrade []PathologicalEventMatcherDefinition `json:"upgrade,omitempty"`
+}
+
+// PathologicalEventMatcherDefinition mirrors the fields of SimplePathologicalEventMatcher.
+type PathologicalEventMatcherDefinition struct {
+	Name                    string                           `json:"name"`
+	LocatorKeyRegexes       map[monitorapi.LocatorKey]string `json:"locatorKeyRegexes,omitempty"`
+	MessageReasonRegex      string                           `json:"messageReasonRegex,omitempty"`
+	MessageHumanRegex       string                           `json:"messageHumanRegex,omitempty"`
+	RepeatThresholdOverride int                              `json:"repeatThresholdOverride,omitempty"`
+	NeverAllow              bool                             `json:"neverAllow,omitempty"`
+	Topology                v1.TopologyMode                  `json:"topology,omitempty"`
+	Jira                    string                           `json:"jira,omitempty"`
+	// Expires is a date in YYYY-MM-DD format.
+	Expires string `json:"expires,omitempty"`
+}
+
+// PathologicalEventMatchers holds the matchers parsed from a PathologicalEventMatcherFile.
+type PathologicalEventMatchers struct {
+	Universal []*SimplePathologicalEventMatcher
+	Upgrade   []*SimplePathologicalEventMatcher
+}
+
+// NewPathologicalEventMatchersFromFile reads a PathologicalEventMatcherFile from disk.
+func NewPathologicalEventMatchersFromFile(filename string) (*PathologicalEventMatchers, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	matchers, err := NewPathologicalEventMatchersFromYAML(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return matchers, nil
+}
+
+// NewPathologicalEventMatchersFromYAML parses a PathologicalEventMatcherFile. Unknown fields are rejected
+// so a typo does not silently produce an over-broad matcher.
+func NewPathologicalEventMatchersFromYAML(data []byte) (*PathologicalEventMatchers, error) {
+	file := &PathologicalEventMatcherFile{}
+	if err := yaml.UnmarshalStrict(data, file); err != nil {
+		return nil, err
+	}
+
+	names := sets.NewString()
+	ret := &PathologicalEventMatchers{}
+	for _, defs := range []struct {
+		definitions []PathologicalEventMatcherDefinition
+		into        *[]*SimplePathologicalEventMatcher
+	}{
+		{definitions: file.Universal, into: &ret.Universal},
+		{definitions: file.Upgrade, into: &ret.Upgrade},
+	} {
+		for _, def := range defs.definitions {
+			if names.Has(def.Name) {
+				return nil, fmt.Errorf("%q is defined more than once", def.Name)
+			}
+			names.Insert(def.Name)
+
+			matcher, err := def.toMatcher()
+			if err != nil {
+				return nil, err
+			}
+			*defs.into = append(*defs.into, matcher)
+		}
+	}
+	return ret, nil
+}
+
+func (d PathologicalEventMatcherDefinition) toMatcher() (*SimplePathologicalEventMatcher, error) {
+	if len(d.Name) == 0 {
+		return nil, fmt.Errorf("must specify a name for pathological event matchers")
+	}
+	if len(d.LocatorKeyRegexes) == 0 && len(d.MessageReasonRegex) == 0 && len(d.MessageHumanRegex) == 0 {
+		return nil, fmt.Errorf("%q: must specify at least one of locatorKeyRegexes, messageReasonRegex or messageHumanRegex", d.Name)
+	}
+	if d.RepeatThresholdOverride < 0 {
+		return nil, fmt.Errorf("%q: repeatThresholdOverride must not be negative", d.Name)
+	}
+
+	matcher := &SimplePathologicalEventMatcher{
+		name:                    d.Name,
+		jira:                    d.Jira,
+		repeatThresholdOverride: d.RepeatThresholdOverride,
+		neverAllow:              d.NeverAllow,
+	}
+
+	var err error
+	if len(d.LocatorKeyRegexes) > 0 {
+		matcher.locatorKeyRegexes = map[monitorapi.LocatorKey]*regexp.Regexp{}
+		for k, v := range d.LocatorKeyRegexes {
+			if matcher.locatorKeyRegexes[k], err = regexp.Compile(v); err != nil {
+				return nil, fmt.Errorf("%q: invalid locatorKeyRegexes[%s]: %w", d.Name, k, err)
+			}
+		}
+	}
+	if len(d.MessageReasonRegex) > 0 {
+		if matcher.messageReasonRegex, err = regexp.Compile(d.MessageReasonRegex); err != nil {
+			return nil, fmt.Errorf("%q: invalid messageReasonRegex: %w", d.Name, err)
+		}
+	}
+	if len(d.MessageHumanRegex) > 0 {
+		if matcher.messageHumanRegex, err = regexp.Compile(d.MessageHumanRegex); err != nil {
+			return nil, fmt.Errorf("%q: invalid messageHumanRegex: %w", d.Name, err)
+		}
+	}
+
+	switch d.Topology {
+	case "":
+	case v1.HighlyAvailableTopologyMode, v1.SingleReplicaTopologyMode, v1.ExternalTopologyMode:
+		topology := d.Topology
+		matcher.topology = &topology
+	default:
+		return nil, fmt.Errorf("%q: unknown topology %q", d.Name, d.Topology)
+	}
+
+	if len(d.Expires) > 0 {
+		expires, err := time.ParseInLocation(time.DateOnly, d.Expires, time.UTC)
+		if err != nil {
+			return nil, fmt.Errorf("%q: expires must be in YYYY-MM-DD format: %w", d.Name, err)
+		}
+		matcher.expires = &expires
+	}
+
+	return matcher, nil
+}
+
+// AddPathologicalEventMatchers registers every matcher, failing on the first name collision.
+func (r *AllowedPathologicalEventRegistry) AddPathologicalEventMatchers(matchers []*SimplePathologicalEventMatcher) error {
+	for _, m := range matchers {
+		if err := r.AddPathologicalEventMatcher(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package pathologicaleventlibrary
+
+import (
+	"testing"
+
+	v1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddedMatchersParse(t *testing.T) {
+	matchers := getEmbeddedMatchers()
+	require.NotNil(t, matchers)
+	assert.NotEmpty(t, matchers.Universal)
+}
+
+func TestNewPathologicalEventMatchersFromYAML(t *testing.T) {
+	tests := []struct {
+		name        string
+		yaml        string
+		expectedErr string
+		interval    monitorapi.Interval
+		topology    v1.TopologyMode
+		expectAllow bool
+	}{
+		{
+			name: "locator and message regexes",
+			yaml: `
+universal:
+- name: FooBackOff
+  locatorKeyRegexes:
+    namespace: ^openshift-foo$
+  messageReasonRegex: ^BackOff$
+  messageHumanRegex: Back-off restarting
+`,
+			interval:    BuildTestDupeKubeEvent("openshift-foo", "foo-1", "BackOff", "Back-off restarting failed container", 50),
+			expectAllow: true,
+		},
+		{
+			name: "locator regex does not match",
+			yaml: `
+universal:
+- name: FooBackOff
+  locatorKeyRegexes:
+    namespace: ^openshift-foo$
+`,
+			interval:    BuildTestDupeKubeEvent("openshift-bar", "bar-1", "BackOff", "Back-off restarting failed container", 50),
+			expectAllow: false,
+		},
+		{
+			name: "over repeat threshold override",
+			yaml: `
+universal:
+- name: FooBackOff
+  messageReasonRegex: ^BackOff$
+  repeatThresholdOverride: 40
+`,
+			interval:    BuildTestDupeKubeEvent("openshift-foo", "foo-1", "BackOff", "Back-off restarting failed container", 50),
+			expectAllow: false,
+		},
+		{
+			name: "wrong topology",
+			yaml: `
+universal:
+- name: FooBackOff
+  messageReasonRegex: ^BackOff$
+  topology: SingleReplica
+`,
+			interval:    BuildTestDupeKubeEvent("openshift-foo", "foo-1", "BackOff", "Back-off restarting failed container", 50),
+			topology:    v1.HighlyAvailableTopologyMode,
+			expectAllow: false,
+		},
+		{
+			name: "expired",
+			yaml: `
+universal:
+- name: FooBackOff
+  messageReasonRegex: ^BackOff$
+  expires: "2020-01-01"
+`,
+			interval:    BuildTestDupeKubeEvent("openshift-foo", "foo-1", "BackOff", "Back-off restarting failed container", 50),
+			expectAllow: false,
+		},
+		{
+			name: "not yet expired",
+			yaml: `
+universal:
+- name: FooBackOff
+  messageReasonRegex: ^BackOff$
+  expires: "2999-01-01"
+`,
+			interval:    BuildTestDupeKubeEvent("openshift-foo", "foo-1", "BackOff", "Back-off restarting failed container", 50),
+			expectAllow: true,
+		},
+		{
+			name: "unknown field",
+			yaml: `
+universal:
+- name: FooBackOff
+  messageRegex: ^BackOff$
+`,
+			expectedErr: `unknown field "messageRegex"`,
+		},
+		{
+			name: "duplicate name",
+			yaml: `
+universal:
+- name: FooBackOff
+  messageReasonRegex: ^BackOff$
+upgrade:
+- name: FooBackOff
+  messageReasonRegex: ^BackOff$
+`,
+			expectedErr: `"FooBackOff" is defined more than once`,
+		},
+		{
+			name: "invalid regex",
+			yaml: `
+universal:
+- name: FooBackOff
+  messageReasonRegex: ^(BackOff$
+`,
+			expectedErr: "invalid messageReasonRegex",
+		},
+		{
+			name: "matches everything",
+			yaml: `
+universal:
+- name: FooBackOff
+`,
+			expectedErr: "must specify at least one of",
+		},
+		{
+			name: "bad expiry",
+			yaml: `
+universal:
+- name: FooBackOff
+  messageReasonRegex: ^BackOff$
+  expires: next week
+`,
+			expectedErr: "expires must be in YYYY-MM-DD format",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchers, err := NewPathologicalEventMatchersFromYAML([]byte(tt.yaml))
+			if len(tt.expectedErr) > 0 {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, matchers.Universal, 1)
+			assert.Equal(t, tt.expectAllow, matchers.Universal[0].Allows(tt.interval, tt.topology))
+		})
+	}
+}
+
+func TestMatcherTestResults(t *testing.T) {
+	matchers, err := NewPathologicalEventMatchersFromYAML([]byte(`
+universal:
+- name: FooBackOff
+  locatorKeyRegexes:
+    namespace: ^openshift-foo$
+  messageReasonRegex: ^BackOff$
+- name: AnyBackOff
+  messageReasonRegex: ^BackOff$
+- name: NeverSeen
+  messageReasonRegex: ^NeverSeen$
+- name: BarUnhealthy
+  locatorKeyRegexes:
+    namespace: ^openshift-bar$
+  messageReasonRegex: ^Unhealthy$
+  repeatThresholdOverride: 40
+`))
+	require.NoError(t, err)
+	registry := &AllowedPathologicalEventRegistry{matchers: map[string]EventMatcher{}}
+	require.NoError(t, registry.AddPathologicalEventMatchers(matchers.Universal))
+
+	intervals := monitorapi.Intervals{
+		BuildTestDupeKubeEvent("openshift-foo", "foo-1", "BackOff", "Back-off restarting failed container", 50),
+		BuildTestDupeKubeEvent("openshift-bar", "bar-1", "BackOff", "Back-off restarting failed container", 50),
+		BuildTestDupeKubeEvent("openshift-bar", "bar-1", "BackOff", "Back-off restarting failed container", 2),
+		BuildTestDupeKubeEvent("openshift-bar", "bar-1", "Unhealthy", "Readiness probe failed", 50),
+	}
+	results := NewMatcherTestResults()
+	results.Record("e2e-events.json", registry, intervals, v1.HighlyAvailableTopologyMode)
+
+	assert.Equal(t, 1, results.Matchers["FooBackOff"].Matched)
+	assert.Len(t, results.Matchers["FooBackOff"].Allowed, 1)
+	assert.Equal(t, 3, results.Matchers["AnyBackOff"].Matched)
+	assert.Len(t, results.Matchers["AnyBackOff"].Allowed, 2)
+	assert.Equal(t, 1, results.Matchers["BarUnhealthy"].Matched)
+	assert.Equal(t, 40, results.Matchers["BarUnhealthy"].RepeatThresholdOverride)
+	assert.Len(t, results.Matchers["BarUnhealthy"].OverThreshold, 1)
+	assert.Equal(t, []string{"BarUnhealthy", "NeverSeen"}, results.Unused())
+	require.Len(t, results.Disallowed, 1)
+	assert.Equal(t, "Unhealthy", results.Disallowed[0].Reason)
+	assert.Equal(t, "AnyBackOff", results.Sorted()[0].Name)
+}
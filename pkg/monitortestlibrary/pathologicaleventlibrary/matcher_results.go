@@ -0,0 +1,131 @@
+package pathologicaleventlibrary
+
+import (
+	"sort"
+
+	v1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// MatchedEvent is a kube event interval that a matcher was evaluated against.
+type MatchedEvent struct {
+	// Source identifies where the interval came from, typically the intervals file.
+	Source    string `json:"source"`
+	Locator   string `json:"locator"`
+	Reason    string `json:"reason"`
+	Message   string `json:"message"`
+	Count     int    `json:"count"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// MatcherTestResult records what a single matcher did across all the intervals it was tested against.
+type MatcherTestResult struct {
+	Name string `json:"name"`
+	// Matched is the number of kube event intervals the matcher matched, regardless of how often they repeated.
+	Matched int `json:"matched"`
+	// Allowed are the events that repeated pathologically and were allowed by this matcher.
+	Allowed []MatchedEvent `json:"allowed,omitempty"`
+	// RepeatThresholdOverride is the number of repeats the matcher allows instead of DuplicateEventThreshold, 0
+	// when it does not override it.
+	RepeatThresholdOverride int `json:"repeatThresholdOverride,omitempty"`
+	// OverThreshold are the events the matcher matched that repeated more than its RepeatThresholdOverride, so it
+	// did not allow them.
+	OverThreshold []MatchedEvent `json:"overThreshold,omitempty"`
+}
+
+// repeatThresholdMatcher is implemented by matchers that may override how many times an event is allowed to repeat.
+type repeatThresholdMatcher interface {
+	repeatThreshold() int
+}
+
+// MatcherTestResults accumulates MatcherTestResults per matcher name, plus the pathological events
+// no matcher allowed.
+type MatcherTestResults struct {
+	Matchers   map[string]*MatcherTestResult `json:"matchers"`
+	Disallowed []MatchedEvent                `json:"disallowed,omitempty"`
+}
+
+func NewMatcherTestResults() *MatcherTestResults {
+	return &MatcherTestResults{Matchers: map[string]*MatcherTestResult{}}
+}
+
+// Record evaluates every matcher in the registry against every kube event interval. Unlike AllowedByAny,
+// each matcher is checked independently so overlapping matchers all get credit for what they allow.
+func (r *MatcherTestResults) Record(source string, registry *AllowedPathologicalEventRegistry, intervals monitorapi.Intervals, topology v1.TopologyMode) {
+	matchers := registry.Matchers()
+	for _, m := range matchers {
+		if _, ok := r.Matchers[m.Name()]; !ok {
+			r.Matchers[m.Name()] = &MatcherTestResult{Name: m.Name()}
+		}
+		if thresholdMatcher, ok := m.(repeatThresholdMatcher); ok {
+			r.Matchers[m.Name()].RepeatThresholdOverride = thresholdMatcher.repeatThreshold()
+		}
+	}
+
+	for _, interval := range intervals {
+		if interval.Source != monitorapi.SourceKubeEvent {
+			continue
+		}
+		count := GetTimesAnEventHappened(interval.Message)
+		event := MatchedEvent{
+			Source:    source,
+			Locator:   interval.Locator.OldLocator(),
+			Reason:    string(interval.Message.Reason),
+			Message:   interval.Message.HumanMessage,
+			Count:     count,
+			Namespace: interval.Locator.Keys[monitorapi.LocatorNamespaceKey],
+		}
+
+		allowed := false
+		for _, m := range matchers {
+			result := r.Matchers[m.Name()]
+			if !m.Matches(interval) {
+				continue
+			}
+			result.Matched++
+			if count <= DuplicateEventThreshold {
+				continue
+			}
+			if result.RepeatThresholdOverride != 0 && count > result.RepeatThresholdOverride {
+				result.OverThreshold = append(result.OverThreshold, event)
+				continue
+			}
+			if m.Allows(interval, topology) {
+				result.Allowed = append(result.Allowed, event)
+				allowed = true
+			}
+		}
+		if count > DuplicateEventThreshold && !allowed {
+			r.Disallowed = append(r.Disallowed, event)
+		}
+	}
+}
+
+// Unused returns the names of matchers that never allowed a pathological event. These are candidates
+// for removal.
+func (r *MatcherTestResults) Unused() []string {
+	ret := []string{}
+	for name, result := range r.Matchers {
+		if len(result.Allowed) == 0 {
+			ret = append(ret, name)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Sorted returns the results ordered by the number of allowed events, most first, so over-broad
+// matchers are easy to spot.
+func (r *MatcherTestResults) Sorted() []*MatcherTestResult {
+	ret := make([]*MatcherTestResult, 0, len(r.Matchers))
+	for _, result := range r.Matchers {
+		ret = append(ret, result)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if len(ret[i].Allowed) != len(ret[j].Allowed) {
+			return len(ret[i].Allowed) > len(ret[j].Allowed)
+		}
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
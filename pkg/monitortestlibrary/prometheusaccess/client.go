@@ -0,0 +1,71 @@
+package prometheusaccess
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	routeclient "github.com/openshift/client-go/route/clientset/versioned"
+	"github.com/openshift/library-go/test/library/metrics"
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	prometheustypes "github.com/prometheus/common/model"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// NewPrometheusClient returns a client for the in-cluster Thanos querier once it is connected to all Prometheus
+// sidecars, or nil if the cluster has no monitoring stack.
+func NewPrometheusClient(ctx context.Context, restConfig *rest.Config) (prometheusv1.API, error) {
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	routeClient, err := routeclient.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = kubeClient.CoreV1().Namespaces().Get(ctx, "openshift-monitoring", metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+
+	prometheusClient, err := metrics.NewPrometheusClient(ctx, kubeClient, routeClient)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure that all Thanos queriers are connected to all Prometheus sidecars
+	// before fetching the alerts. This avoids retrieving partial data
+	// (possibly with gaps) when after an upgrade, one of the Prometheus
+	// sidecars hasn't been reconnected yet to the Thanos queriers.
+	if err = wait.PollImmediateWithContext(ctx, 5*time.Second, 5*time.Minute, func(context.Context) (bool, error) {
+		v, warningsForQuery, err := prometheusClient.Query(ctx, `min(count by(pod) (thanos_store_nodes_grpc_connections{store_type="sidecar"})) == min(kube_statefulset_replicas{statefulset="prometheus-k8s"})`, time.Time{})
+		if err != nil {
+			return false, err
+		}
+
+		if len(warningsForQuery) > 0 {
+			fmt.Printf("#### warnings \n\t%v\n", strings.Join(warningsForQuery, "\n\t"))
+		}
+
+		if v.Type() != prometheustypes.ValVector {
+			return false, fmt.Errorf("expecting a vector type, got %q", v.Type().String())
+		}
+
+		if len(v.(prometheustypes.Vector)) == 0 {
+			fmt.Printf("#### at least one Prometheus sidecar isn't ready\n")
+			return false, nil
+		}
+
+		return true, nil
+	}); err != nil {
+		return nil, fmt.Errorf("Thanos queriers not connected to all Prometheus sidecars: %w", err)
+	}
+
+	return prometheusClient, nil
+}
@@ -0,0 +1,254 @@
+package awsmetricsanalyzer
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/origin/pkg/monitortestlibrary/cloudmetrics"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	cloudWatchService    = "monitoring"
+	cloudWatchAPIVersion = "2010-08-01"
+	ebsCSIDriver         = "ebs.csi.aws.com"
+
+	// maxMetricDataQueries is the most queries a GetMetricData request takes.
+	maxMetricDataQueries = 500
+)
+
+// awsMetric is a CloudWatch metric and when it is extrenuous.  These can be adjusted based on values collected in real
+// test environments.
+type awsMetric struct {
+	namespace string
+	name      string
+	// dimension is InstanceId for VM metrics and VolumeId for disk metrics.
+	dimension string
+	stat      string
+	period    time.Duration
+	threshold float64
+	below     bool
+}
+
+var (
+	// instanceMetrics are published every five minutes without detailed monitoring.  The balances explain a VM whose
+	// disks or CPU are throttled, the EBS ones include the boot disk etcd writes to.
+	instanceMetrics = []awsMetric{
+		{namespace: "AWS/EC2", name: "EBSIOBalance%", dimension: "InstanceId", stat: "Minimum", period: 5 * time.Minute, threshold: 20, below: true},
+		{namespace: "AWS/EC2", name: "EBSByteBalance%", dimension: "InstanceId", stat: "Minimum", period: 5 * time.Minute, threshold: 20, below: true},
+		{namespace: "AWS/EC2", name: "CPUCreditBalance", dimension: "InstanceId", stat: "Minimum", period: 5 * time.Minute, threshold: 10, below: true},
+	}
+	volumeMetrics = []awsMetric{
+		{namespace: "AWS/EBS", name: "BurstBalance", dimension: "VolumeId", stat: "Minimum", period: time.Minute, threshold: 20, below: true},
+		{namespace: "AWS/EBS", name: "VolumeQueueLength", dimension: "VolumeId", stat: "Average", period: time.Minute, threshold: 10},
+	}
+)
+
+type awsMetricsProvider struct {
+	// endpoint is the CloudWatch endpoint, a local fake in tests.
+	endpoint    string
+	region      string
+	credentials awsCredentials
+	httpClient  *http.Client
+}
+
+// NewProvider collects CloudWatch metrics of the cluster instances and their EBS CSI volumes.
+func NewProvider(ctx context.Context, adminRESTConfig *rest.Config, infrastructure *configv1.Infrastructure) (cloudmetrics.CloudMetricsProvider, error) {
+	if infrastructure.Status.PlatformStatus == nil || infrastructure.Status.PlatformStatus.AWS == nil {
+		return nil, fmt.Errorf("infrastructure has no AWS platform status")
+	}
+	region := infrastructure.Status.PlatformStatus.AWS.Region
+	endpoint := fmt.Sprintf("https://%s.%s.amazonaws.com", cloudWatchService, region)
+	if strings.HasPrefix(region, "cn-") {
+		endpoint += ".cn"
+	}
+	for _, serviceEndpoint := range infrastructure.Status.PlatformStatus.AWS.ServiceEndpoints {
+		if serviceEndpoint.Name == cloudWatchService {
+			endpoint = serviceEndpoint.URL
+		}
+	}
+
+	credentials, err := loadCredentials()
+	if err != nil {
+		return nil, err
+	}
+	return newAWSMetricsProvider(endpoint, region, credentials, &http.Client{Timeout: time.Minute}), nil
+}
+
+func newAWSMetricsProvider(endpoint, region string, credentials awsCredentials, httpClient *http.Client) *awsMetricsProvider {
+	return &awsMetricsProvider{
+		endpoint:    endpoint,
+		region:      region,
+		credentials: credentials,
+		httpClient:  httpClient,
+	}
+}
+
+func (p *awsMetricsProvider) Thresholds() []cloudmetrics.Threshold {
+	ret := []cloudmetrics.Threshold{}
+	for _, metric := range append(append([]awsMetric{}, instanceMetrics...), volumeMetrics...) {
+		ret = append(ret, cloudmetrics.Threshold{Metric: metric.name, Value: metric.threshold, Below: metric.below})
+	}
+	return ret
+}
+
+// metricDataQuery is one series to fetch and the node and disk it belongs to.
+type metricDataQuery struct {
+	metric      awsMetric
+	dimensionID string
+	nodeName    string
+	disk        string
+}
+
+func (p *awsMetricsProvider) CollectSeries(ctx context.Context, nodes []cloudmetrics.CloudNode, beginning, end time.Time) ([]cloudmetrics.Series, error) {
+	queries := []metricDataQuery{}
+	for _, node := range nodes {
+		// aws:///us-east-1a/i-0123456789abcdef0
+		instanceID := path.Base(node.ProviderID)
+		if !strings.HasPrefix(instanceID, "i-") {
+			continue
+		}
+		for _, metric := range instanceMetrics {
+			queries = append(queries, metricDataQuery{metric: metric, dimensionID: instanceID, nodeName: node.NodeName})
+		}
+		for _, disk := range node.Disks {
+			if disk.Driver != ebsCSIDriver {
+				continue
+			}
+			for _, metric := range volumeMetrics {
+				queries = append(queries, metricDataQuery{metric: metric, dimensionID: disk.VolumeHandle, nodeName: node.NodeName, disk: disk.Name})
+			}
+		}
+	}
+
+	ret := []cloudmetrics.Series{}
+	for start := 0; start < len(queries); start += maxMetricDataQueries {
+		batch := queries[start:min(start+maxMetricDataQueries, len(queries))]
+		series, err := p.getMetricData(ctx, batch, beginning, end)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, series...)
+	}
+	return ret, nil
+}
+
+type getMetricDataResponse struct {
+	Result struct {
+		MetricDataResults []struct {
+			ID         string      `xml:"Id"`
+			Timestamps []time.Time `xml:"Timestamps>member"`
+			Values     []float64   `xml:"Values>member"`
+		} `xml:"MetricDataResults>member"`
+		NextToken string `xml:"NextToken"`
+	} `xml:"GetMetricDataResult"`
+}
+
+type errorResponse struct {
+	Error struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"Error"`
+}
+
+// getMetricData fetches a batch of queries through every page of results.
+func (p *awsMetricsProvider) getMetricData(ctx context.Context, queries []metricDataQuery, beginning, end time.Time) ([]cloudmetrics.Series, error) {
+	form := url.Values{}
+	form.Set("Action", "GetMetricData")
+	form.Set("Version", cloudWatchAPIVersion)
+	form.Set("StartTime", beginning.UTC().Format(time.RFC3339))
+	form.Set("EndTime", end.UTC().Format(time.RFC3339))
+	form.Set("ScanBy", "TimestampAscending")
+	series := map[string]*cloudmetrics.Series{}
+	ids := []string{}
+	for i, query := range queries {
+		id := fmt.Sprintf("m%d", i)
+		prefix := fmt.Sprintf("MetricDataQueries.member.%d.", i+1)
+		form.Set(prefix+"Id", id)
+		form.Set(prefix+"MetricStat.Metric.Namespace", query.metric.namespace)
+		form.Set(prefix+"MetricStat.Metric.MetricName", query.metric.name)
+		form.Set(prefix+"MetricStat.Metric.Dimensions.member.1.Name", query.metric.dimension)
+		form.Set(prefix+"MetricStat.Metric.Dimensions.member.1.Value", query.dimensionID)
+		form.Set(prefix+"MetricStat.Period", fmt.Sprint(int(query.metric.period.Seconds())))
+		form.Set(prefix+"MetricStat.Stat", query.metric.stat)
+		series[id] = &cloudmetrics.Series{
+			NodeName: query.nodeName,
+			Disk:     query.disk,
+			Metric:   query.metric.name,
+			Period:   query.metric.period,
+		}
+		ids = append(ids, id)
+	}
+
+	for {
+		response, err := p.do(ctx, form)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range response.Result.MetricDataResults {
+			currSeries, ok := series[result.ID]
+			if !ok || len(result.Timestamps) != len(result.Values) {
+				continue
+			}
+			for i := range result.Timestamps {
+				// CloudWatch stamps a period with its start
+				currSeries.Samples = append(currSeries.Samples, cloudmetrics.Sample{
+					Timestamp: result.Timestamps[i].Add(currSeries.Period),
+					Value:     result.Values[i],
+				})
+			}
+		}
+		if len(response.Result.NextToken) == 0 {
+			break
+		}
+		form.Set("NextToken", response.Result.NextToken)
+	}
+
+	ret := []cloudmetrics.Series{}
+	for _, id := range ids {
+		ret = append(ret, *series[id])
+	}
+	return ret, nil
+}
+
+func (p *awsMetricsProvider) do(ctx context.Context, form url.Values) (*getMetricDataResponse, error) {
+	body := []byte(form.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	signRequest(req, body, p.credentials, p.region, cloudWatchService, time.Now())
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		errResponse := &errorResponse{}
+		if err := xml.Unmarshal(responseBody, errResponse); err != nil || len(errResponse.Error.Code) == 0 {
+			return nil, fmt.Errorf("GetMetricData failed with %d: %s", resp.StatusCode, string(responseBody))
+		}
+		return nil, fmt.Errorf("GetMetricData failed with %d: %s: %s", resp.StatusCode, errResponse.Error.Code, errResponse.Error.Message)
+	}
+
+	response := &getMetricDataResponse{}
+	if err := xml.Unmarshal(responseBody, response); err != nil {
+		return nil, fmt.Errorf("unable to decode GetMetricData response: %w", err)
+	}
+	return response, nil
+}
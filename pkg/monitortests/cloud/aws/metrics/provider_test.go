@@ -0,0 +1,132 @@
+package awsmetricsanalyzer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/cloudmetrics"
+)
+
+func TestSignRequest(t *testing.T) {
+	// get-vanilla from the AWS Signature Version 4 test suite
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentials := awsCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	signRequest(req, nil, credentials, "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	expected := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if actual := req.Header.Get("Authorization"); actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
+
+const getMetricDataPage = `<GetMetricDataResponse xmlns="http://monitoring.amazonaws.com/doc/2010-08-01/">
+  <GetMetricDataResult>
+    <MetricDataResults>
+      <member>
+        <Id>%s</Id>
+        <Timestamps>
+          <member>2024-01-01T10:00:00Z</member>
+        </Timestamps>
+        <Values>
+          <member>%s</member>
+        </Values>
+        <StatusCode>Complete</StatusCode>
+      </member>
+    </MetricDataResults>
+    <NextToken>%s</NextToken>
+  </GetMetricDataResult>
+</GetMetricDataResponse>`
+
+func TestCollectSeries(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/") {
+			t.Errorf("request is not signed: %q", r.Header.Get("Authorization"))
+		}
+		if action := r.PostForm.Get("Action"); action != "GetMetricData" {
+			t.Errorf("unexpected action %q", action)
+		}
+		// three instance metrics for the node and two volume metrics for its EBS volume
+		if name := r.PostForm.Get("MetricDataQueries.member.4.MetricStat.Metric.MetricName"); name != "BurstBalance" {
+			t.Errorf("unexpected fourth metric %q", name)
+		}
+		if volumeID := r.PostForm.Get("MetricDataQueries.member.4.MetricStat.Metric.Dimensions.member.1.Value"); volumeID != "vol-0123" {
+			t.Errorf("unexpected volume %q", volumeID)
+		}
+		if r.PostForm.Get("MetricDataQueries.member.6.Id") != "" {
+			t.Errorf("unexpected sixth query, the volume of another CSI driver should be skipped")
+		}
+		switch r.PostForm.Get("NextToken") {
+		case "":
+			fmt.Fprintf(w, getMetricDataPage, "m0", "5", "page2")
+		case "page2":
+			fmt.Fprintf(w, getMetricDataPage, "m3", "10.5", "")
+		default:
+			t.Errorf("unexpected token %q", r.PostForm.Get("NextToken"))
+		}
+	}))
+	defer server.Close()
+
+	provider := newAWSMetricsProvider(server.URL, "us-east-1", awsCredentials{AccessKeyID: "access", SecretAccessKey: "secret"}, server.Client())
+	nodes := []cloudmetrics.CloudNode{
+		{
+			NodeName:   "node-a",
+			ProviderID: "aws:///us-east-1a/i-0123",
+			Disks: []cloudmetrics.CloudDisk{
+				{Name: "pvc-1", Driver: ebsCSIDriver, VolumeHandle: "vol-0123"},
+				{Name: "pvc-2", Driver: "efs.csi.aws.com", VolumeHandle: "fs-0123"},
+			},
+		},
+		{NodeName: "node-b", ProviderID: "vsphere://not-aws"},
+	}
+	series, err := provider.CollectSeries(context.TODO(), nodes, time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Errorf("expected two pages, got %d requests", requests)
+	}
+	if len(series) != 5 {
+		t.Fatalf("expected 5 series, got %d", len(series))
+	}
+	if series[0].Metric != "EBSIOBalance%" || len(series[0].Samples) != 1 || series[0].Samples[0].Value != 5 {
+		t.Errorf("unexpected instance series %#v", series[0])
+	}
+	// samples are stamped with the end of their period
+	if expected := time.Date(2024, 1, 1, 10, 5, 0, 0, time.UTC); !series[0].Samples[0].Timestamp.Equal(expected) {
+		t.Errorf("expected sample at %v, got %v", expected, series[0].Samples[0].Timestamp)
+	}
+	if series[3].Disk != "pvc-1" || series[3].Metric != "BurstBalance" || len(series[3].Samples) != 1 || series[3].Samples[0].Value != 10.5 {
+		t.Errorf("unexpected volume series %#v", series[3])
+	}
+	if len(series[4].Samples) != 0 {
+		t.Errorf("expected no samples for VolumeQueueLength, got %v", series[4].Samples)
+	}
+}
+
+func TestCollectSeriesError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `<ErrorResponse><Error><Type>Sender</Type><Code>Throttling</Code><Message>Rate exceeded</Message></Error></ErrorResponse>`)
+	}))
+	defer server.Close()
+
+	provider := newAWSMetricsProvider(server.URL, "us-east-1", awsCredentials{AccessKeyID: "access", SecretAccessKey: "secret"}, server.Client())
+	nodes := []cloudmetrics.CloudNode{{NodeName: "node-a", ProviderID: "aws:///us-east-1a/i-0123"}}
+	_, err := provider.CollectSeries(context.TODO(), nodes, time.Now().Add(-time.Hour), time.Now())
+	if err == nil || !strings.Contains(err.Error(), "Throttling: Rate exceeded") {
+		t.Errorf("expected the throttling error, got %v", err)
+	}
+}
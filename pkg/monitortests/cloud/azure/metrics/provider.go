@@ -0,0 +1,156 @@
+package azuremetricsanalyzer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/origin/pkg/monitortestlibrary/cloudmetrics"
+	azureutil "github.com/openshift/origin/test/extended/util/azure"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/legacy-cloud-providers/azure"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// avgOSDiskQueueDepthThreshold defines the threshold for average OS Disk Queue Depth metric.
+	// If the metric average is over the threshold, an interval will be created. This can be adjusted
+	// based on value collected in real test environment.
+	avgOSDiskQueueDepthThreshold = 3.0
+
+	azureProviderIDPrefix = "azure://"
+)
+
+// metricTest is used to group test data such as azure metrics query params and threshold
+type metricTest struct {
+	interval     string
+	period       time.Duration
+	avgThreshold float64
+}
+
+// metricsMap maps a metric name to test parameters. It includes query parameter such as metric intervals.
+// It also includes thresholds that will be compared with the time series instances.
+var metricsMap = map[string]metricTest{
+	"OS Disk Queue Depth": {
+		interval:     "PT1M",
+		period:       time.Minute,
+		avgThreshold: avgOSDiskQueueDepthThreshold,
+	},
+}
+
+type azureMetricsProvider struct {
+	client         *armmonitor.MetricsClient
+	subscriptionID string
+	resourceGroup  string
+}
+
+// NewProvider collects Azure Monitor metrics of the cluster VMs.
+func NewProvider(ctx context.Context, adminRESTConfig *rest.Config, infrastructure *configv1.Infrastructure) (cloudmetrics.CloudMetricsProvider, error) {
+	if infrastructure.Status.PlatformStatus == nil || infrastructure.Status.PlatformStatus.Azure == nil {
+		return nil, fmt.Errorf("infrastructure has no Azure platform status")
+	}
+	kubeClient, err := kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return nil, err
+	}
+	// get resource group
+	resourceGroup := infrastructure.Status.PlatformStatus.Azure.ResourceGroupName
+
+	// get subscription ID
+	cm, err := kubeClient.CoreV1().ConfigMaps("openshift-config").Get(ctx, "cloud-provider-config", metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	data, ok := cm.Data["config"]
+	if !ok {
+		return nil, fmt.Errorf("No cloud provider config was set in openshift-config/cloud-provider-config")
+	}
+	config := &azure.Config{}
+	if err := yaml.Unmarshal([]byte(data), config); err != nil {
+		return nil, err
+	}
+	subscriptionID := config.SubscriptionID
+
+	azureutil.ExportAzureCredentials()
+
+	// create azure metrics client
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		logrus.WithError(err).Error("default azure credential does not exist")
+		return nil, err
+	}
+	clientFactory, err := armmonitor.NewClientFactory(subscriptionID, cred, nil)
+	if err != nil {
+		logrus.WithError(err).Error("failed to create azure metric client")
+		return nil, err
+	}
+
+	return &azureMetricsProvider{
+		client:         clientFactory.NewMetricsClient(),
+		subscriptionID: subscriptionID,
+		resourceGroup:  resourceGroup,
+	}, nil
+}
+
+func (p *azureMetricsProvider) Thresholds() []cloudmetrics.Threshold {
+	ret := []cloudmetrics.Threshold{}
+	for metric, test := range metricsMap {
+		ret = append(ret, cloudmetrics.Threshold{Metric: metric, Value: test.avgThreshold})
+	}
+	return ret
+}
+
+// resourceID is the provider ID of the node without its scheme, or built from the node name, which is the VM name.
+func (p *azureMetricsProvider) resourceID(node cloudmetrics.CloudNode) string {
+	if strings.HasPrefix(node.ProviderID, azureProviderIDPrefix) {
+		return strings.TrimPrefix(node.ProviderID, azureProviderIDPrefix)
+	}
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/virtualMachines/%s", p.subscriptionID, p.resourceGroup, node.NodeName)
+}
+
+func (p *azureMetricsProvider) CollectSeries(ctx context.Context, nodes []cloudmetrics.CloudNode, beginning, end time.Time) ([]cloudmetrics.Series, error) {
+	ret := []cloudmetrics.Series{}
+	// Specify the time range and interval to query
+	timeRange := fmt.Sprintf("%s/%s", beginning.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339))
+
+	for _, node := range nodes {
+		resourceID := p.resourceID(node)
+		for metric, test := range metricsMap {
+			metric, test := metric, test
+			resp, err := p.client.List(ctx, resourceID, &armmonitor.MetricsClientListOptions{
+				Timespan:        &timeRange,
+				Interval:        &test.interval,
+				Metricnames:     &metric,
+				Metricnamespace: nil,
+			})
+			if err != nil {
+				logrus.WithError(err).Error("error getting metrics")
+				return nil, err
+			}
+			for _, value := range resp.Value {
+				for _, ts := range value.Timeseries {
+					series := cloudmetrics.Series{
+						NodeName: node.NodeName,
+						Metric:   metric,
+						Period:   test.period,
+					}
+					for _, d := range ts.Data {
+						if d.Average == nil || d.TimeStamp == nil {
+							continue
+						}
+						series.Samples = append(series.Samples, cloudmetrics.Sample{Timestamp: *d.TimeStamp, Value: *d.Average})
+					}
+					ret = append(ret, series)
+				}
+			}
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,172 @@
+package cloudmetricscollector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/cloudmetrics"
+	awsmetricsanalyzer "github.com/openshift/origin/pkg/monitortests/cloud/aws/metrics"
+	azuremetricsanalyzer "github.com/openshift/origin/pkg/monitortests/cloud/azure/metrics"
+	gcpmetricsanalyzer "github.com/openshift/origin/pkg/monitortests/cloud/gcp/metrics"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	exutil "github.com/openshift/origin/test/extended/util"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// providers maps every platform with a cloud metrics API to its provider.
+var providers = map[configv1.PlatformType]cloudmetrics.NewProviderFunc{
+	configv1.AWSPlatformType:   awsmetricsanalyzer.NewProvider,
+	configv1.AzurePlatformType: azuremetricsanalyzer.NewProvider,
+	configv1.GCPPlatformType:   gcpmetricsanalyzer.NewProvider,
+}
+
+type cloudMetricsCollector struct {
+	adminRESTConfig    *rest.Config
+	flakeErr           error
+	notSupportedReason error
+}
+
+// NewCloudMetricsCollector puts the VM and disk metrics of the cloud the cluster runs on that cross their thresholds
+// on the timeline.  Throttled disks and exhausted CPU credits explain many etcd and disruption failures.
+func NewCloudMetricsCollector() monitortestframework.MonitorTest {
+	return &cloudMetricsCollector{}
+}
+
+func (w *cloudMetricsCollector) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	w.adminRESTConfig = adminRESTConfig
+	kubeClient, err := kubernetes.NewForConfig(w.adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	isMicroShift, err := exutil.IsMicroShiftCluster(kubeClient)
+	if err != nil {
+		return fmt.Errorf("unable to determine if cluster is MicroShift: %v", err)
+	}
+	if isMicroShift {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{
+			Reason: "platform MicroShift not supported",
+		}
+	}
+	return w.notSupportedReason
+}
+
+// CollectData collects cloud metrics. Since cloud metrics are collected to facilitate debugging, some errors (like cloud throttling) are not considered fatal.
+// They are reported as flakes.
+func (w *cloudMetricsCollector) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+	configClient, err := configclient.NewForConfig(w.adminRESTConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	infra, err := configClient.ConfigV1().Infrastructures().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	if infra.Status.PlatformStatus == nil {
+		return nil, nil, nil
+	}
+	// Only collect on platforms with a provider
+	newProvider, ok := providers[infra.Status.PlatformStatus.Type]
+	if !ok {
+		return nil, nil, nil
+	}
+
+	provider, err := newProvider(ctx, w.adminRESTConfig, infra)
+	if err != nil {
+		logrus.WithError(err).Errorf("failed to create the %s cloud metrics provider", infra.Status.PlatformStatus.Type)
+		w.flakeErr = &monitortestframework.FlakeError{Err: err}
+		return nil, nil, w.flakeErr
+	}
+
+	kubeClient, err := kubernetes.NewForConfig(w.adminRESTConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	nodes, err := listCloudNodes(ctx, kubeClient)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	series, err := provider.CollectSeries(ctx, nodes, beginning, end)
+	if err != nil {
+		logrus.WithError(err).Errorf("failed to fetch %s cloud metrics", infra.Status.PlatformStatus.Type)
+		w.flakeErr = &monitortestframework.FlakeError{Err: err}
+		return nil, nil, w.flakeErr
+	}
+	return cloudmetrics.IntervalsFromSeries(series, provider.Thresholds()), nil, nil
+}
+
+// listCloudNodes lists the nodes with the CSI persistent volumes attached to them.
+func listCloudNodes(ctx context.Context, kubeClient kubernetes.Interface) ([]cloudmetrics.CloudNode, error) {
+	nodes, err := kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	volumeAttachments, err := kubeClient.StorageV1().VolumeAttachments().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	persistentVolumes, err := kubeClient.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	disks := map[string]cloudmetrics.CloudDisk{}
+	for _, pv := range persistentVolumes.Items {
+		if pv.Spec.CSI == nil {
+			continue
+		}
+		disks[pv.Name] = cloudmetrics.CloudDisk{
+			Name:         pv.Name,
+			Driver:       pv.Spec.CSI.Driver,
+			VolumeHandle: pv.Spec.CSI.VolumeHandle,
+		}
+	}
+	nodeDisks := map[string][]cloudmetrics.CloudDisk{}
+	for _, attachment := range volumeAttachments.Items {
+		if !attachment.Status.Attached || attachment.Spec.Source.PersistentVolumeName == nil {
+			continue
+		}
+		disk, ok := disks[*attachment.Spec.Source.PersistentVolumeName]
+		if !ok {
+			continue
+		}
+		nodeDisks[attachment.Spec.NodeName] = append(nodeDisks[attachment.Spec.NodeName], disk)
+	}
+
+	ret := []cloudmetrics.CloudNode{}
+	for _, node := range nodes.Items {
+		ret = append(ret, cloudmetrics.CloudNode{
+			NodeName:   node.Name,
+			ProviderID: node.Spec.ProviderID,
+			Disks:      nodeDisks[node.Name],
+		})
+	}
+	return ret, nil
+}
+
+func (*cloudMetricsCollector) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, nil
+}
+
+func (*cloudMetricsCollector) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return nil, nil
+}
+
+func (*cloudMetricsCollector) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func (*cloudMetricsCollector) Cleanup(ctx context.Context) error {
+	return nil
+}
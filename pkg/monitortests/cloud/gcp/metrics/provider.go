@@ -0,0 +1,149 @@
+package gcpmetricsanalyzer
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/origin/pkg/monitortestlibrary/cloudmetrics"
+	monitoring "google.golang.org/api/monitoring/v3"
+	"google.golang.org/api/option"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	gceProviderIDPrefix = "gce://"
+	computeMetricPrefix = "compute.googleapis.com/"
+
+	alignmentPeriod = time.Minute
+)
+
+// gcpMetric is a Cloud Monitoring metric of compute instances and when it is extrenuous.  These can be adjusted based
+// on values collected in real test environments.
+type gcpMetric struct {
+	// name is the metric type without the compute.googleapis.com/ prefix.
+	name      string
+	aligner   string
+	threshold float64
+	below     bool
+}
+
+// gcpMetrics are reported per instance, the disk ones have a series per disk labeled with its device name, the boot
+// disk etcd writes to included.
+var gcpMetrics = []gcpMetric{
+	// throttled operations per second
+	{name: "instance/disk/throttled_write_ops_count", aligner: "ALIGN_RATE", threshold: 1},
+	{name: "instance/disk/throttled_read_ops_count", aligner: "ALIGN_RATE", threshold: 1},
+	{name: "instance/cpu/utilization", aligner: "ALIGN_MEAN", threshold: 0.9},
+}
+
+type gcpMetricsProvider struct {
+	service   *monitoring.Service
+	projectID string
+}
+
+// NewProvider collects Cloud Monitoring metrics of the cluster instances and their disks with the application default
+// credentials.
+func NewProvider(ctx context.Context, adminRESTConfig *rest.Config, infrastructure *configv1.Infrastructure) (cloudmetrics.CloudMetricsProvider, error) {
+	if infrastructure.Status.PlatformStatus == nil || infrastructure.Status.PlatformStatus.GCP == nil {
+		return nil, fmt.Errorf("infrastructure has no GCP platform status")
+	}
+	service, err := monitoring.NewService(ctx, option.WithScopes(monitoring.MonitoringReadScope))
+	if err != nil {
+		return nil, err
+	}
+	return newGCPMetricsProvider(service, infrastructure.Status.PlatformStatus.GCP.ProjectID), nil
+}
+
+func newGCPMetricsProvider(service *monitoring.Service, projectID string) *gcpMetricsProvider {
+	return &gcpMetricsProvider{
+		service:   service,
+		projectID: projectID,
+	}
+}
+
+func (p *gcpMetricsProvider) Thresholds() []cloudmetrics.Threshold {
+	ret := []cloudmetrics.Threshold{}
+	for _, metric := range gcpMetrics {
+		ret = append(ret, cloudmetrics.Threshold{Metric: metric.name, Value: metric.threshold, Below: metric.below})
+	}
+	return ret
+}
+
+func (p *gcpMetricsProvider) CollectSeries(ctx context.Context, nodes []cloudmetrics.CloudNode, beginning, end time.Time) ([]cloudmetrics.Series, error) {
+	// gce://project/zone/instance-name
+	instanceToNode := map[string]string{}
+	instanceNames := []string{}
+	for _, node := range nodes {
+		if !strings.HasPrefix(node.ProviderID, gceProviderIDPrefix) {
+			continue
+		}
+		instanceName := path.Base(node.ProviderID)
+		instanceToNode[instanceName] = node.NodeName
+		instanceNames = append(instanceNames, fmt.Sprintf("%q", instanceName))
+	}
+	if len(instanceNames) == 0 {
+		return nil, nil
+	}
+
+	ret := []cloudmetrics.Series{}
+	for _, metric := range gcpMetrics {
+		filter := fmt.Sprintf(`metric.type="%s%s" AND metric.labels.instance_name=one_of(%s)`, computeMetricPrefix, metric.name, strings.Join(instanceNames, ","))
+		call := p.service.Projects.TimeSeries.List("projects/" + p.projectID).
+			Filter(filter).
+			IntervalStartTime(beginning.UTC().Format(time.RFC3339)).
+			IntervalEndTime(end.UTC().Format(time.RFC3339)).
+			AggregationAlignmentPeriod(fmt.Sprintf("%ds", int(alignmentPeriod.Seconds()))).
+			AggregationPerSeriesAligner(metric.aligner)
+		err := call.Pages(ctx, func(response *monitoring.ListTimeSeriesResponse) error {
+			for _, timeSeries := range response.TimeSeries {
+				if timeSeries.Metric == nil {
+					continue
+				}
+				nodeName, ok := instanceToNode[timeSeries.Metric.Labels["instance_name"]]
+				if !ok {
+					continue
+				}
+				series := cloudmetrics.Series{
+					NodeName: nodeName,
+					Disk:     timeSeries.Metric.Labels["device_name"],
+					Metric:   metric.name,
+					Period:   alignmentPeriod,
+				}
+				for _, point := range timeSeries.Points {
+					sample, ok := sampleFromPoint(point)
+					if !ok {
+						continue
+					}
+					series.Samples = append(series.Samples, sample)
+				}
+				ret = append(ret, series)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to list %s: %w", metric.name, err)
+		}
+	}
+	return ret, nil
+}
+
+func sampleFromPoint(point *monitoring.Point) (cloudmetrics.Sample, bool) {
+	if point.Interval == nil || point.Value == nil {
+		return cloudmetrics.Sample{}, false
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, point.Interval.EndTime)
+	if err != nil {
+		return cloudmetrics.Sample{}, false
+	}
+	switch {
+	case point.Value.DoubleValue != nil:
+		return cloudmetrics.Sample{Timestamp: timestamp, Value: *point.Value.DoubleValue}, true
+	case point.Value.Int64Value != nil:
+		return cloudmetrics.Sample{Timestamp: timestamp, Value: float64(*point.Value.Int64Value)}, true
+	}
+	return cloudmetrics.Sample{}, false
+}
@@ -0,0 +1,80 @@
+package gcpmetricsanalyzer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/cloudmetrics"
+	monitoring "google.golang.org/api/monitoring/v3"
+	"google.golang.org/api/option"
+)
+
+const timeSeriesResponse = `{
+  "timeSeries": [
+    {
+      "metric": {"type": "compute.googleapis.com/%s", "labels": {"instance_name": "master-0", "device_name": "master-0-boot"}},
+      "points": [
+        {"interval": {"startTime": "2024-01-01T10:00:00Z", "endTime": "2024-01-01T10:01:00Z"}, "value": {"doubleValue": 2.5}},
+        {"interval": {"startTime": "2024-01-01T10:01:00Z", "endTime": "2024-01-01T10:02:00Z"}, "value": {"int64Value": "3"}}
+      ]
+    },
+    {
+      "metric": {"type": "compute.googleapis.com/%s", "labels": {"instance_name": "someone-else"}},
+      "points": []
+    }
+  ]
+}`
+
+func TestCollectSeries(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if !strings.HasSuffix(r.URL.Path, "/projects/my-project/timeSeries") {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		filter := r.URL.Query().Get("filter")
+		if !strings.Contains(filter, `metric.labels.instance_name=one_of("master-0")`) {
+			t.Errorf("unexpected filter %q", filter)
+		}
+		metric := strings.TrimSuffix(strings.TrimPrefix(strings.Split(filter, " AND ")[0], `metric.type="compute.googleapis.com/`), `"`)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, timeSeriesResponse, metric, metric)
+	}))
+	defer server.Close()
+
+	service, err := monitoring.NewService(context.TODO(), option.WithEndpoint(server.URL), option.WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := newGCPMetricsProvider(service, "my-project")
+	nodes := []cloudmetrics.CloudNode{
+		{NodeName: "master-0.c.my-project.internal", ProviderID: "gce://my-project/us-central1-a/master-0"},
+		{NodeName: "worker-0", ProviderID: "aws:///us-east-1a/i-0123"},
+	}
+	series, err := provider.CollectSeries(context.TODO(), nodes, time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != len(gcpMetrics) {
+		t.Errorf("expected a request per metric, got %d", requests)
+	}
+	if len(series) != len(gcpMetrics) {
+		t.Fatalf("expected the series of master-0 only, got %d", len(series))
+	}
+	for i, currSeries := range series {
+		if currSeries.NodeName != "master-0.c.my-project.internal" || currSeries.Disk != "master-0-boot" || currSeries.Metric != gcpMetrics[i].name {
+			t.Errorf("unexpected series %#v", currSeries)
+		}
+		if len(currSeries.Samples) != 2 || currSeries.Samples[0].Value != 2.5 || currSeries.Samples[1].Value != 3 {
+			t.Errorf("unexpected samples %v", currSeries.Samples)
+		}
+		if expected := time.Date(2024, 1, 1, 10, 1, 0, 0, time.UTC); !currSeries.Samples[0].Timestamp.Equal(expected) {
+			t.Errorf("expected sample at %v, got %v", expected, currSeries.Samples[0].Timestamp)
+		}
+	}
+}
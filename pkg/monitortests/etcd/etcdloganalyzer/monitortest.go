@@ -2,6 +2,7 @@ package etcdloganalyzer
 
 import (
 	"context"
+	_ "embed"
 	"encoding/json"
 	"regexp"
 	"strings"
@@ -18,12 +19,20 @@ import (
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/logrules"
 	"github.com/openshift/origin/pkg/monitortestlibrary/podaccess"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
 
+// etcd_log_rules.yaml holds the rules for etcd log messages that only need to be shown on the timeline.
+//
+//go:embed etcd_log_rules.yaml
+var etcdLogRulesYAML []byte
+
+var etcdLogRules = logrules.MustRulesFromYAML(etcdLogRulesYAML)
+
 type etcdLogAnalyzer struct {
 	adminRESTConfig *rest.Config
 
@@ -156,20 +165,13 @@ func (*etcdLogAnalyzer) Cleanup(ctx context.Context) error {
 
 type etcdRecorder struct {
 	recorder monitorapi.RecorderWriter
-	// TODO this limits our ability to have custom messages, we probably want something better
-	subStrings []subStringLevel
+	logRules *logrules.PodLogHandler
 }
 
 func newEtcdRecorder(recorder monitorapi.RecorderWriter) etcdRecorder {
 	return etcdRecorder{
 		recorder: recorder,
-		subStrings: []subStringLevel{
-			{"slow fdatasync", monitorapi.Warning},
-			{"dropped internal Raft message since sending buffer is full", monitorapi.Warning},
-			{"waiting for ReadIndex response took too long, retrying", monitorapi.Warning},
-			{"apply request took too long", monitorapi.Warning},
-			{"is starting a new election", monitorapi.Info},
-		},
+		logRules: logrules.NewPodLogHandler(recorder, etcdLogRules),
 	}
 }
 
@@ -182,20 +184,13 @@ func (g etcdRecorder) HandleLogLine(logLine podaccess.LogLineContent) {
 		return
 	}
 
-	for _, substring := range g.subStrings {
-		if !strings.Contains(parsedLine.Msg, substring.subString) {
-			continue
-		}
-
-		g.recorder.AddIntervals(
-			monitorapi.NewInterval(monitorapi.SourceEtcdLog, monitorapi.Warning).
-				Locator(logLine.Locator).
-				Message(
-					monitorapi.NewMessage().
-						HumanMessage(parsedLine.Msg),
-				).
-				Build(parsedLine.Timestamp, parsedLine.Timestamp.Add(1*time.Second)))
-	}
+	// the rules match the message, at the time etcd logged it
+	g.logRules.HandleLogLine(podaccess.LogLineContent{
+		Instant: parsedLine.Timestamp,
+		Pod:     logLine.Pod,
+		Locator: logLine.Locator,
+		Line:    parsedLine.Msg,
+	})
 
 	var etcdSource monitorapi.IntervalSource = monitorapi.SourceEtcdLeadership
 	messages := []*monitorapi.MessageBuilder{}
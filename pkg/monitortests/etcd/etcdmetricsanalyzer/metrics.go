@@ -0,0 +1,275 @@
+package etcdmetricsanalyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	prometheustypes "github.com/prometheus/common/model"
+)
+
+const (
+	// metricsStep matches the etcd scrape interval, finer steps only repeat samples.
+	metricsStep = 30 * time.Second
+
+	mebibyte = 1024 * 1024
+)
+
+// etcdMetric is queried per etcd pod over the run.  Metrics with a reason are shown on the timeline whenever they
+// cross their threshold, the others are only summarized.
+type etcdMetric struct {
+	name  string
+	query string
+
+	reason    monitorapi.IntervalReason
+	threshold float64
+	// below means the threshold is crossed by going under it instead of over it.
+	below bool
+	// description says what crossing the threshold means.
+	description string
+	format      func(float64) string
+}
+
+func (m etcdMetric) crossed(value float64) bool {
+	if len(m.reason) == 0 {
+		return false
+	}
+	if m.below {
+		return value < m.threshold
+	}
+	return value > m.threshold
+}
+
+func formatSeconds(value float64) string {
+	return time.Duration(value * float64(time.Second)).Round(time.Microsecond).String()
+}
+
+func formatCount(value float64) string {
+	return fmt.Sprintf("%.0f", value)
+}
+
+func formatBytes(value float64) string {
+	return fmt.Sprintf("%.0fMiB", value/mebibyte)
+}
+
+func formatRatio(value float64) string {
+	return fmt.Sprintf("%.0f%%", value*100)
+}
+
+// etcdMetrics follow the etcd hardware guidance: the 99th percentile of WAL fsync should stay under 10ms and of
+// backend commits under 25ms, anything slower is a disk that will eventually cost a leader election.
+var etcdMetrics = []etcdMetric{
+	{
+		name:        "wal-fsync-p99-seconds",
+		query:       `histogram_quantile(0.99, sum by (pod, le) (rate(etcd_disk_wal_fsync_duration_seconds_bucket{namespace="openshift-etcd"}[2m])))`,
+		reason:      monitorapi.EtcdSlowWALFsyncReason,
+		threshold:   0.01,
+		description: "99th percentile WAL fsync above 10ms",
+		format:      formatSeconds,
+	},
+	{
+		name:        "backend-commit-p99-seconds",
+		query:       `histogram_quantile(0.99, sum by (pod, le) (rate(etcd_disk_backend_commit_duration_seconds_bucket{namespace="openshift-etcd"}[2m])))`,
+		reason:      monitorapi.EtcdSlowBackendCommitReason,
+		threshold:   0.025,
+		description: "99th percentile backend commit above 25ms",
+		format:      formatSeconds,
+	},
+	{
+		name:        "peer-round-trip-p99-seconds",
+		query:       `histogram_quantile(0.99, sum by (pod, le) (rate(etcd_network_peer_round_trip_time_seconds_bucket{namespace="openshift-etcd"}[2m])))`,
+		reason:      monitorapi.EtcdSlowPeerRoundTripReason,
+		threshold:   0.1,
+		description: "99th percentile peer round trip above 100ms",
+		format:      formatSeconds,
+	},
+	{
+		name:        "proposals-failed",
+		query:       `sum by (pod) (increase(etcd_server_proposals_failed_total{namespace="openshift-etcd"}[2m]))`,
+		reason:      monitorapi.EtcdProposalsFailedReason,
+		threshold:   0,
+		description: "proposals failed",
+		format:      formatCount,
+	},
+	{
+		name:   "db-size-bytes",
+		query:  `sum by (pod) (etcd_mvcc_db_total_size_in_bytes{namespace="openshift-etcd"})`,
+		format: formatBytes,
+	},
+	{
+		name:   "db-size-in-use-bytes",
+		query:  `sum by (pod) (etcd_mvcc_db_total_size_in_use_in_bytes{namespace="openshift-etcd"})`,
+		format: formatBytes,
+	},
+	{
+		// a small database is not worth defragmenting, however much of it is free
+		name: "db-in-use-ratio",
+		query: `(sum by (pod) (etcd_mvcc_db_total_size_in_use_in_bytes{namespace="openshift-etcd"}) / sum by (pod) (etcd_mvcc_db_total_size_in_bytes{namespace="openshift-etcd"}))` +
+			` and on (pod) sum by (pod) (etcd_mvcc_db_total_size_in_bytes{namespace="openshift-etcd"}) > 104857600`,
+		reason:      monitorapi.EtcdDatabaseFragmentedReason,
+		threshold:   0.5,
+		below:       true,
+		description: "less than half of a database over 100MiB in use",
+		format:      formatRatio,
+	},
+	{
+		name:        "db-quota-used-ratio",
+		query:       `sum by (pod) (etcd_mvcc_db_total_size_in_bytes{namespace="openshift-etcd"}) / sum by (pod) (etcd_server_quota_backend_bytes{namespace="openshift-etcd"})`,
+		reason:      monitorapi.EtcdDatabaseNearQuotaReason,
+		threshold:   0.8,
+		description: "database above 80% of its quota",
+		format:      formatRatio,
+	},
+}
+
+// etcdMember identifies the member an etcd pod runs.
+type etcdMember struct {
+	NodeName string
+	// MemberID is the hex ID etcd logs the member with, or the pod name when the server ID could not be read.
+	MemberID string
+}
+
+// etcdMembersFromServerIDs maps every etcd pod to its member from the etcd_server_id metric.  Pods are named after the
+// node they run on.
+func etcdMembersFromServerIDs(serverIDs prometheustypes.Vector) map[string]etcdMember {
+	ret := map[string]etcdMember{}
+	for _, sample := range serverIDs {
+		pod := string(sample.Metric["pod"])
+		ret[pod] = etcdMember{
+			NodeName: strings.TrimPrefix(pod, "etcd-"),
+			MemberID: string(sample.Metric["server_id"]),
+		}
+	}
+	return ret
+}
+
+func memberFor(members map[string]etcdMember, pod string) etcdMember {
+	if member, ok := members[pod]; ok && len(member.MemberID) > 0 {
+		return member
+	}
+	return etcdMember{NodeName: strings.TrimPrefix(pod, "etcd-"), MemberID: pod}
+}
+
+// intervalsFromMatrix builds an interval for every run of consecutive samples that cross the threshold of the metric.
+// A missing sample ends the run, the value is unknown there.
+func intervalsFromMatrix(metric etcdMetric, matrix prometheustypes.Matrix, members map[string]etcdMember, step time.Duration) monitorapi.Intervals {
+	if len(metric.reason) == 0 {
+		return nil
+	}
+
+	ret := monitorapi.Intervals{}
+	for _, series := range matrix {
+		member := memberFor(members, string(series.Metric["pod"]))
+		var from, to time.Time
+		var peak float64
+		flush := func() {
+			if from.IsZero() {
+				return
+			}
+			ret = append(ret,
+				monitorapi.NewInterval(monitorapi.SourceEtcdMetrics, monitorapi.Warning).
+					Locator(monitorapi.NewLocator().EtcdMemberFromNames(member.NodeName, member.MemberID)).
+					Message(monitorapi.NewMessage().
+						Reason(metric.reason).
+						HumanMessagef("%s, peaked at %s", metric.description, metric.format(peak))).
+					Display().
+					Build(from, to),
+			)
+			from = time.Time{}
+		}
+
+		for _, sample := range series.Values {
+			at := sample.Timestamp.Time()
+			value := float64(sample.Value)
+			if !metric.crossed(value) {
+				flush()
+				continue
+			}
+			if !from.IsZero() && at.Sub(to) > 0 {
+				// the previous sample is missing
+				flush()
+			}
+			if from.IsZero() {
+				from = at
+				peak = value
+			}
+			if metric.below && value < peak || !metric.below && value > peak {
+				peak = value
+			}
+			to = at.Add(step)
+		}
+		flush()
+	}
+	sort.Sort(ret)
+	return ret
+}
+
+type EtcdMemberSummary struct {
+	Pod      string
+	NodeName string
+	MemberID string
+	Metrics  map[string]*EtcdMetricSummary
+}
+
+type EtcdMetricSummary struct {
+	Min  float64
+	Max  float64
+	Mean float64
+	// CrossedThresholdSeconds is how long the metric was past its threshold, metrics without one are only summarized.
+	CrossedThresholdSeconds float64 `json:",omitempty"`
+
+	samples int
+}
+
+// summarize folds the samples of a metric into the summary of every member.
+func summarize(summaries map[string]*EtcdMemberSummary, metric etcdMetric, matrix prometheustypes.Matrix, members map[string]etcdMember, step time.Duration) {
+	for _, series := range matrix {
+		if len(series.Values) == 0 {
+			continue
+		}
+		pod := string(series.Metric["pod"])
+		summary, ok := summaries[pod]
+		if !ok {
+			member := memberFor(members, pod)
+			summary = &EtcdMemberSummary{
+				Pod:      pod,
+				NodeName: member.NodeName,
+				MemberID: member.MemberID,
+				Metrics:  map[string]*EtcdMetricSummary{},
+			}
+			summaries[pod] = summary
+		}
+		metricSummary, ok := summary.Metrics[metric.name]
+		if !ok {
+			metricSummary = &EtcdMetricSummary{Min: float64(series.Values[0].Value), Max: float64(series.Values[0].Value)}
+			summary.Metrics[metric.name] = metricSummary
+		}
+		for _, sample := range series.Values {
+			value := float64(sample.Value)
+			if value < metricSummary.Min {
+				metricSummary.Min = value
+			}
+			if value > metricSummary.Max {
+				metricSummary.Max = value
+			}
+			metricSummary.Mean += (value - metricSummary.Mean) / float64(metricSummary.samples+1)
+			metricSummary.samples++
+			if metric.crossed(value) {
+				metricSummary.CrossedThresholdSeconds += step.Seconds()
+			}
+		}
+	}
+}
+
+func sortedSummaries(summaries map[string]*EtcdMemberSummary) []*EtcdMemberSummary {
+	ret := []*EtcdMemberSummary{}
+	for _, summary := range summaries {
+		ret = append(ret, summary)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Pod < ret[j].Pod
+	})
+	return ret
+}
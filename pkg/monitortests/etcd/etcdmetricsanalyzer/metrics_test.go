@@ -0,0 +1,108 @@
+package etcdmetricsanalyzer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	prometheustypes "github.com/prometheus/common/model"
+)
+
+func metricFor(t *testing.T, name string) etcdMetric {
+	for _, metric := range etcdMetrics {
+		if metric.name == name {
+			return metric
+		}
+	}
+	t.Fatalf("no metric %s", name)
+	return etcdMetric{}
+}
+
+func series(pod string, start time.Time, values ...float64) *prometheustypes.SampleStream {
+	ret := &prometheustypes.SampleStream{Metric: prometheustypes.Metric{"pod": prometheustypes.LabelValue(pod)}}
+	for i, value := range values {
+		if value < 0 {
+			// a missing sample
+			continue
+		}
+		ret.Values = append(ret.Values, prometheustypes.SamplePair{
+			Timestamp: prometheustypes.TimeFromUnixNano(start.Add(time.Duration(i) * metricsStep).UnixNano()),
+			Value:     prometheustypes.SampleValue(value),
+		})
+	}
+	return ret
+}
+
+func TestIntervalsFromMatrix(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(step int) time.Time { return start.Add(time.Duration(step) * metricsStep) }
+
+	members := etcdMembersFromServerIDs(prometheustypes.Vector{
+		{Metric: prometheustypes.Metric{"pod": "etcd-master-0", "server_id": "8e9e05c52164694d"}},
+	})
+	matrix := prometheustypes.Matrix{
+		series("etcd-master-0", start, 0.002, 0.015, 0.045, 0.003, 0.012, -1, 0.011),
+		// no server ID, located by pod
+		series("etcd-master-1", start, 0.002, 0.002, 0.002, 0.002, 0.002, 0.002, 0.020),
+	}
+	intervals := intervalsFromMatrix(metricFor(t, "wal-fsync-p99-seconds"), matrix, members, metricsStep)
+	if len(intervals) != 4 {
+		t.Fatalf("expected 4 intervals, got:\n%s", strings.Join(intervals.Strings(), "\n"))
+	}
+
+	first := intervals[0]
+	if !first.From.Equal(at(1)) || !first.To.Equal(at(3)) {
+		t.Errorf("expected the first slow stretch from %v to %v, got %v to %v", at(1), at(3), first.From, first.To)
+	}
+	if first.Source != monitorapi.SourceEtcdMetrics || first.Message.Reason != monitorapi.EtcdSlowWALFsyncReason || !first.Display {
+		t.Errorf("unexpected interval %s", first.String())
+	}
+	if !strings.Contains(first.Message.HumanMessage, "peaked at 45ms") {
+		t.Errorf("expected the peak in the message, got %q", first.Message.HumanMessage)
+	}
+	if first.Locator.Keys[monitorapi.LocatorEtcdMemberKey] != "8e9e05c52164694d" || first.Locator.Keys[monitorapi.LocatorNodeKey] != "master-0" {
+		t.Errorf("unexpected locator %s", first.Locator.OldLocator())
+	}
+	// the missing sample splits the second stretch
+	if !intervals[1].From.Equal(at(4)) || !intervals[1].To.Equal(at(5)) {
+		t.Errorf("expected a stretch ending at the missing sample, got %s", intervals[1].String())
+	}
+	for _, interval := range intervals[2:] {
+		if interval.Locator.Keys[monitorapi.LocatorNodeKey] == "master-1" && interval.Locator.Keys[monitorapi.LocatorEtcdMemberKey] != "etcd-master-1" {
+			t.Errorf("expected the pod as member when the server ID is unknown, got %s", interval.Locator.OldLocator())
+		}
+	}
+
+	// lower is worse for the in use ratio
+	fragmented := intervalsFromMatrix(metricFor(t, "db-in-use-ratio"), prometheustypes.Matrix{series("etcd-master-0", start, 0.9, 0.4, 0.3, 0.6)}, members, metricsStep)
+	if len(fragmented) != 1 || !strings.Contains(fragmented[0].Message.HumanMessage, "peaked at 30%") {
+		t.Errorf("expected one fragmented stretch peaking at 30%%, got:\n%s", strings.Join(fragmented.Strings(), "\n"))
+	}
+
+	if summaryOnly := intervalsFromMatrix(metricFor(t, "db-size-bytes"), matrix, members, metricsStep); len(summaryOnly) != 0 {
+		t.Errorf("expected no intervals for a summary only metric, got %d", len(summaryOnly))
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	members := map[string]etcdMember{"etcd-master-0": {NodeName: "master-0", MemberID: "8e9e05c52164694d"}}
+
+	summaries := map[string]*EtcdMemberSummary{}
+	summarize(summaries, metricFor(t, "wal-fsync-p99-seconds"), prometheustypes.Matrix{series("etcd-master-0", start, 0.002, 0.015, 0.045, 0.002)}, members, metricsStep)
+	summarize(summaries, metricFor(t, "db-size-bytes"), prometheustypes.Matrix{series("etcd-master-0", start, 100*mebibyte, 300*mebibyte)}, members, metricsStep)
+
+	sorted := sortedSummaries(summaries)
+	if len(sorted) != 1 || sorted[0].MemberID != "8e9e05c52164694d" || sorted[0].NodeName != "master-0" {
+		t.Fatalf("unexpected summaries %+v", sorted)
+	}
+	fsync := sorted[0].Metrics["wal-fsync-p99-seconds"]
+	if fsync.Min != 0.002 || fsync.Max != 0.045 || fsync.Mean != 0.016 || fsync.CrossedThresholdSeconds != 60 {
+		t.Errorf("unexpected fsync summary %+v", fsync)
+	}
+	dbSize := sorted[0].Metrics["db-size-bytes"]
+	if dbSize.Max != 300*mebibyte || dbSize.Mean != 200*mebibyte || dbSize.CrossedThresholdSeconds != 0 {
+		t.Errorf("unexpected db size summary %+v", dbSize)
+	}
+}
@@ -0,0 +1,115 @@
+package etcdmetricsanalyzer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/prometheusaccess"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	prometheustypes "github.com/prometheus/common/model"
+	"github.com/sirupsen/logrus"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/rest"
+)
+
+type etcdMetricsAnalyzer struct {
+	adminRESTConfig *rest.Config
+	summaries       []*EtcdMemberSummary
+}
+
+// NewEtcdMetricsAnalyzer puts slow disks, slow peers, failed proposals and database growth of every etcd member on the
+// timeline from the in-cluster Prometheus, so a slow etcd shows up next to the disruption it caused.  The etcd log
+// analyzer only sees what etcd decided to log.
+func NewEtcdMetricsAnalyzer() monitortestframework.MonitorTest {
+	return &etcdMetricsAnalyzer{}
+}
+
+func (w *etcdMetricsAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	w.adminRESTConfig = adminRESTConfig
+	return nil
+}
+
+func (w *etcdMetricsAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	prometheusClient, err := prometheusaccess.NewPrometheusClient(ctx, w.adminRESTConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	if prometheusClient == nil {
+		return nil, nil, nil
+	}
+
+	members := map[string]etcdMember{}
+	serverIDs, warningsForQuery, err := prometheusClient.Query(ctx, `etcd_server_id{namespace="openshift-etcd"}`, end)
+	switch {
+	case err != nil:
+		// the members are still located by pod
+		logrus.WithError(err).Warning("unable to read the etcd server IDs")
+	case serverIDs.Type() == prometheustypes.ValVector:
+		members = etcdMembersFromServerIDs(serverIDs.(prometheustypes.Vector))
+	}
+	logQueryWarnings(warningsForQuery)
+
+	timeRange := prometheusv1.Range{
+		Start: beginning,
+		End:   end,
+		Step:  metricsStep,
+	}
+	intervals := monitorapi.Intervals{}
+	summaries := map[string]*EtcdMemberSummary{}
+	errs := []error{}
+	for _, metric := range etcdMetrics {
+		result, warningsForQuery, err := prometheusClient.QueryRange(ctx, metric.query, timeRange)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to query %s: %w", metric.name, err))
+			continue
+		}
+		logQueryWarnings(warningsForQuery)
+		if result.Type() != prometheustypes.ValMatrix {
+			errs = append(errs, fmt.Errorf("expecting a matrix for %s, got %q", metric.name, result.Type().String()))
+			continue
+		}
+		matrix := result.(prometheustypes.Matrix)
+		intervals = append(intervals, intervalsFromMatrix(metric, matrix, members, metricsStep)...)
+		summarize(summaries, metric, matrix, members, metricsStep)
+	}
+	w.summaries = sortedSummaries(summaries)
+
+	return intervals, nil, utilerrors.NewAggregate(errs)
+}
+
+func logQueryWarnings(warningsForQuery prometheusv1.Warnings) {
+	if len(warningsForQuery) > 0 {
+		fmt.Printf("#### warnings \n\t%v\n", strings.Join(warningsForQuery, "\n\t"))
+	}
+}
+
+func (*etcdMetricsAnalyzer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, nil
+}
+
+func (*etcdMetricsAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return nil, nil
+}
+
+func (w *etcdMetricsAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	if w.summaries == nil {
+		return nil
+	}
+	jsonContent, err := json.MarshalIndent(w.summaries, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(storageDir, fmt.Sprintf("etcd-metrics-summary%s.json", timeSuffix)), jsonContent, 0644)
+}
+
+func (*etcdMetricsAnalyzer) Cleanup(ctx context.Context) error {
+	return nil
+}
@@ -25,6 +25,8 @@ type AuditLogSummary struct {
 	perUserRequestCount       map[string]*PerUserRequestCount
 	perResourceRequestCount   map[schema.GroupVersionResource]*PerResourceRequestCount
 	perHTTPStatusRequestCount map[int32]*PerHTTPStatusRequestCount
+	requestLatencies          *RequestLatencies
+	objectMutations           *ObjectMutations
 }
 
 type RequestCounts struct {
@@ -88,6 +90,8 @@ func (s *AuditLogSummary) Add(auditEvent *auditv1.Event, auditEventInfo auditEve
 	}
 
 	s.requestCounts.Add(auditEvent)
+	s.requestLatencies.Add(auditEvent)
+	s.objectMutations.Add(auditEvent)
 
 	gvr := auditEventInfo.getGroupVersionResource(auditEvent)
 	if _, ok := s.perResourceRequestCount[gvr]; !ok {
@@ -197,6 +201,8 @@ func (s *PerResourceRequestCount) Add(auditEvent *auditv1.Event, auditEventInfo
 func (s *AuditLogSummary) AddSummary(rhs *AuditLogSummary) {
 	s.lineReadFailureCount += rhs.lineReadFailureCount
 	s.requestCounts.AddSummary(&rhs.requestCounts)
+	s.requestLatencies.AddSummary(rhs.requestLatencies)
+	s.objectMutations.AddSummary(rhs.objectMutations)
 
 	for k, v := range rhs.perUserRequestCount {
 		if _, ok := s.perUserRequestCount[k]; !ok {
@@ -301,6 +307,8 @@ func NewAuditLogSummary() *AuditLogSummary {
 		perUserRequestCount:       map[string]*PerUserRequestCount{},
 		perResourceRequestCount:   map[schema.GroupVersionResource]*PerResourceRequestCount{},
 		perHTTPStatusRequestCount: map[int32]*PerHTTPStatusRequestCount{},
+		requestLatencies:          NewRequestLatencies(),
+		objectMutations:           NewObjectMutations(),
 	}
 }
 func NewRequestCounts() *RequestCounts {
@@ -514,6 +522,25 @@ func WriteAuditLogSummary(artifactDir, timeSuffix string, auditLogSummary *Audit
 
 	writeAuditLogDL(artifactDir, timeSuffix, auditLogSummary)
 
+	if err := writeRequestLatencies(artifactDir, fmt.Sprintf("audit-log-request-latencies_%s.json", timeSuffix), auditLogSummary.requestLatencies); err != nil {
+		return err
+	}
+	if err := writeObjectMutations(artifactDir, fmt.Sprintf("audit-log-object-mutations_%s.json", timeSuffix), auditLogSummary.objectMutations); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func writeRequestLatencies(artifactDir, filename string, requestLatencies *RequestLatencies) error {
+	latenciesBytes, err := json.MarshalIndent(NewSerializedRequestLatencies(requestLatencies), "", "    ")
+	if err != nil {
+		return err
+	}
+	latenciesPath := filepath.Join(artifactDir, filename)
+	if err := os.WriteFile(latenciesPath, latenciesBytes, 0644); err != nil {
+		return fmt.Errorf("failed to write %v: %w", latenciesPath, err)
+	}
 	return nil
 }
 
@@ -14,21 +14,45 @@ import (
 
 type auditLogAnalyzer struct {
 	adminRESTConfig *rest.Config
+	stream          bool
 
-	// auditLogSummary is written during CollectData
+	// streamer tails the audit logs from StartCollection when streaming
+	streamer *AuditLogStreamer
+	// auditLogSummary is written during CollectData, or as the audit logs are streamed
 	auditLogSummary *AuditLogSummary
 }
 
-func NewAuditLogAnalyzer() monitortestframework.MonitorTest {
-	return &auditLogAnalyzer{}
+func NewAuditLogAnalyzer(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTest {
+	return &auditLogAnalyzer{
+		stream: info.StreamAuditLogs,
+	}
 }
 
 func (w *auditLogAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
 	w.adminRESTConfig = adminRESTConfig
-	return nil
+	if !w.stream {
+		return nil
+	}
+
+	kubeClient, err := kubernetes.NewForConfig(w.adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	w.auditLogSummary = NewAuditLogSummary()
+	w.streamer = NewAuditLogStreamer(kubeClient, "kube-apiserver", time.Now(), w.auditLogSummary)
+	return w.streamer.Start(ctx, kubeClient)
 }
 
 func (w *auditLogAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.streamer != nil {
+		lostRanges := w.streamer.Stop(ctx, end)
+		intervals := intervalsFromAuditLogSummary(w.auditLogSummary)
+		for _, lost := range lostRanges {
+			intervals = append(intervals, lost.ToInterval())
+		}
+		return intervals, nil, nil
+	}
+
 	kubeClient, err := kubernetes.NewForConfig(w.adminRESTConfig)
 	if err != nil {
 		return nil, nil, err
@@ -44,8 +68,12 @@ func (*auditLogAnalyzer) ConstructComputedIntervals(ctx context.Context, startin
 	return nil, nil
 }
 
-func (*auditLogAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
+func (w *auditLogAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	junits := evaluateRequestBrownouts(finalIntervals)
+	if w.auditLogSummary != nil {
+		junits = append(junits, evaluateE2EServiceAccountMutations(w.auditLogSummary.objectMutations.Mutations())...)
+	}
+	return junits, nil
 }
 
 func (w *auditLogAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
@@ -70,5 +98,11 @@ func intervalsFromAuditLogs(ctx context.Context, kubeClient kubernetes.Interface
 		return auditLogSummary, nil, err
 	}
 
+	ret = append(ret, intervalsFromAuditLogSummary(auditLogSummary)...)
+
 	return auditLogSummary, ret, nil
 }
+
+func intervalsFromAuditLogSummary(auditLogSummary *AuditLogSummary) monitorapi.Intervals {
+	return auditLogSummary.requestLatencies.Intervals()
+}
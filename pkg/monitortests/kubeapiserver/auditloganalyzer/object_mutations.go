@@ -0,0 +1,207 @@
+package auditloganalyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"k8s.io/apimachinery/pkg/util/sets"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+var mutatingVerbs = sets.New[string]("create", "update", "patch", "delete", "deletecollection")
+
+// clusterConfigGroups hold the cluster scoped objects that configure the platform.
+var clusterConfigGroups = sets.New[string]("config.openshift.io", "operator.openshift.io", "machineconfiguration.openshift.io")
+
+// unrecordedResources are mutated constantly by every controller, or never stored at all.  Endpoints churn with every
+// pod readiness change in the platform namespaces.
+var unrecordedResources = sets.New[string](
+	"events",
+	"events.events.k8s.io",
+	"leases.coordination.k8s.io",
+	"endpoints",
+	"endpointslices.discovery.k8s.io",
+	"tokenreviews.authentication.k8s.io",
+	"subjectaccessreviews.authorization.k8s.io",
+	"selfsubjectaccessreviews.authorization.k8s.io",
+	"localsubjectaccessreviews.authorization.k8s.io",
+	"selfsubjectrulesreviews.authorization.k8s.io",
+)
+
+// e2eServiceAccountRegex matches the service accounts of the namespaces e2e tests create.
+var e2eServiceAccountRegex = regexp.MustCompile(`^system:serviceaccount:(e2e-[^:]+):[^:]+$`)
+
+func isPlatformNamespace(namespace string) bool {
+	return namespace == "default" || strings.HasPrefix(namespace, "openshift-") || strings.HasPrefix(namespace, "kube-")
+}
+
+// ObjectMutation is a successful mutating request, with only what is needed to answer who changed an object.
+type ObjectMutation struct {
+	Time time.Time `json:"time"`
+	Verb string    `json:"verb"`
+	// Resource is resource.group for resources outside the core group, followed by /subresource if there is one.
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	User      string `json:"user"`
+	UserAgent string `json:"userAgent"`
+	AuditID   string `json:"auditID"`
+}
+
+func (m ObjectMutation) String() string {
+	return fmt.Sprintf("%s %s %s/%s[%s] by %s using %q", m.Time.UTC().Format(time.RFC3339), m.Verb, m.Resource, m.Name, m.Namespace, m.User, m.UserAgent)
+}
+
+// ObjectMutations records every successful mutating request on platform namespaces and cluster configuration, and
+// every one an e2e test service account makes outside its namespace, so that who changed something can be answered
+// from the artifacts.  They are written to their own artifact instead of the intervals, a busy run makes tens of
+// thousands of them.  Like AuditLogSummary it is not threadsafe, use one per thread and combine them with AddSummary.
+type ObjectMutations struct {
+	mutations []ObjectMutation
+}
+
+func NewObjectMutations() *ObjectMutations {
+	return &ObjectMutations{}
+}
+
+func (m *ObjectMutations) Add(auditEvent *auditv1.Event) {
+	if auditEvent.Stage != auditv1.StageResponseComplete || !mutatingVerbs.Has(auditEvent.Verb) || auditEvent.ObjectRef == nil {
+		return
+	}
+	if auditEvent.ResponseStatus == nil || auditEvent.ResponseStatus.Code < 200 || auditEvent.ResponseStatus.Code >= 300 {
+		return
+	}
+	objectRef := auditEvent.ObjectRef
+	// status is written by the controllers that own the object, not by whoever changed it
+	if len(objectRef.Resource) == 0 || objectRef.Subresource == "status" {
+		return
+	}
+	resource := objectRef.Resource
+	if len(objectRef.APIGroup) > 0 {
+		resource = resource + "." + objectRef.APIGroup
+	}
+	if unrecordedResources.Has(resource) {
+		return
+	}
+
+	e2eNamespace := ""
+	if matches := e2eServiceAccountRegex.FindStringSubmatch(auditEvent.User.Username); matches != nil {
+		e2eNamespace = matches[1]
+	}
+	switch {
+	case len(e2eNamespace) > 0 && objectRef.Namespace != e2eNamespace:
+	case len(objectRef.Namespace) > 0 && isPlatformNamespace(objectRef.Namespace):
+	case len(objectRef.Namespace) == 0 && clusterConfigGroups.Has(objectRef.APIGroup):
+	default:
+		return
+	}
+
+	if len(objectRef.Subresource) > 0 {
+		resource = resource + "/" + objectRef.Subresource
+	}
+	m.mutations = append(m.mutations, ObjectMutation{
+		Time:      auditEvent.RequestReceivedTimestamp.Time,
+		Verb:      auditEvent.Verb,
+		Resource:  resource,
+		Namespace: objectRef.Namespace,
+		Name:      objectRef.Name,
+		User:      auditEvent.User.Username,
+		UserAgent: auditEvent.UserAgent,
+		AuditID:   string(auditEvent.AuditID),
+	})
+}
+
+func (m *ObjectMutations) AddSummary(rhs *ObjectMutations) {
+	m.mutations = append(m.mutations, rhs.mutations...)
+}
+
+// Mutations returns the recorded mutations, oldest first.
+func (m *ObjectMutations) Mutations() []ObjectMutation {
+	ret := make([]ObjectMutation, len(m.mutations))
+	copy(ret, m.mutations)
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Time.Before(ret[j].Time)
+	})
+	return ret
+}
+
+func writeObjectMutations(artifactDir, filename string, objectMutations *ObjectMutations) error {
+	mutationsBytes, err := json.Marshal(objectMutations.Mutations())
+	if err != nil {
+		return err
+	}
+	mutationsPath := filepath.Join(artifactDir, filename)
+	if err := os.WriteFile(mutationsPath, mutationsBytes, 0644); err != nil {
+		return fmt.Errorf("failed to write %v: %w", mutationsPath, err)
+	}
+	return nil
+}
+
+// ReadObjectMutations reads an audit-log-object-mutations_*.json artifact.
+func ReadObjectMutations(path string) ([]ObjectMutation, error) {
+	mutationsBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	mutations := []ObjectMutation{}
+	if err := json.Unmarshal(mutationsBytes, &mutations); err != nil {
+		return nil, err
+	}
+	return mutations, nil
+}
+
+// FindObjectMutations returns the mutations of an object.  The resource matches with or without its group, an empty
+// namespace matches every namespace.
+func FindObjectMutations(mutations []ObjectMutation, resource, namespace, name string) []ObjectMutation {
+	ret := []ObjectMutation{}
+	for _, mutation := range mutations {
+		if mutation.Name != name {
+			continue
+		}
+		if len(namespace) > 0 && mutation.Namespace != namespace {
+			continue
+		}
+		mutationResource := strings.SplitN(mutation.Resource, "/", 2)[0]
+		if mutationResource != resource && !strings.HasPrefix(mutationResource, resource+".") {
+			continue
+		}
+		ret = append(ret, mutation)
+	}
+	return ret
+}
+
+const e2eServiceAccountTestName = "[sig-auth] e2e test service accounts should not mutate objects outside their namespace"
+
+// evaluateE2EServiceAccountMutations flags a service account of an e2e test namespace changing anything outside of that
+// namespace, tests must not reach into the platform or each other.
+func evaluateE2EServiceAccountMutations(mutations []ObjectMutation) []*junitapi.JUnitTestCase {
+	messages := []string{}
+	for _, mutation := range mutations {
+		matches := e2eServiceAccountRegex.FindStringSubmatch(mutation.User)
+		if matches == nil || mutation.Namespace == matches[1] {
+			continue
+		}
+		messages = append(messages, mutation.String())
+	}
+	if len(messages) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: e2eServiceAccountTestName}}
+	}
+	return []*junitapi.JUnitTestCase{
+		{
+			Name: e2eServiceAccountTestName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("%d objects outside their namespace were mutated by e2e test service accounts:\n\n%s", len(messages), strings.Join(messages, "\n")),
+			},
+		},
+		// Some tests legitimately write outside their namespace, like project requests and role bindings across
+		// namespaces, so this only flakes until those are told apart.
+		{Name: e2eServiceAccountTestName},
+	}
+}
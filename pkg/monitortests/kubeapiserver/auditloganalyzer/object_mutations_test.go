@@ -0,0 +1,124 @@
+package auditloganalyzer
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+func mutatingAuditEvent(user, verb, group, resource, subresource, namespace, name string, code int32, at time.Time) *auditv1.Event {
+	return &auditv1.Event{
+		AuditID:   types.UID("audit-" + name),
+		Stage:     auditv1.StageResponseComplete,
+		Verb:      verb,
+		User:      authnv1.UserInfo{Username: user},
+		UserAgent: "kubectl/v1.29.0",
+		ObjectRef: &auditv1.ObjectReference{
+			APIGroup:    group,
+			Resource:    resource,
+			Subresource: subresource,
+			Namespace:   namespace,
+			Name:        name,
+		},
+		ResponseStatus:           &metav1.Status{Code: code},
+		RequestReceivedTimestamp: metav1.NewMicroTime(at),
+		StageTimestamp:           metav1.NewMicroTime(at.Add(10 * time.Millisecond)),
+	}
+}
+
+func TestObjectMutations(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	const e2eServiceAccount = "system:serviceaccount:e2e-test-foo-abcde:default"
+
+	perNode := []*ObjectMutations{NewObjectMutations(), NewObjectMutations()}
+	perNode[0].Add(mutatingAuditEvent("system:admin", "patch", "config.openshift.io", "infrastructures", "", "", "cluster", 200, start.Add(2*time.Second)))
+	perNode[1].Add(mutatingAuditEvent("system:admin", "update", "", "configmaps", "", "openshift-etcd", "etcd-ca", 200, start.Add(time.Second)))
+	perNode[0].Add(mutatingAuditEvent(e2eServiceAccount, "create", "rbac.authorization.k8s.io", "clusterroles", "", "", "escalate", 201, start.Add(3*time.Second)))
+	for _, ignored := range []*auditv1.Event{
+		// reads
+		mutatingAuditEvent("system:admin", "get", "", "configmaps", "", "openshift-etcd", "etcd-ca", 200, start),
+		// failed
+		mutatingAuditEvent("system:admin", "update", "", "configmaps", "", "openshift-etcd", "conflict", 409, start),
+		// status is written by the owning controller
+		mutatingAuditEvent("system:admin", "update", "config.openshift.io", "clusteroperators", "status", "", "etcd", 200, start),
+		// constantly renewed
+		mutatingAuditEvent("system:admin", "update", "coordination.k8s.io", "leases", "", "openshift-etcd", "leader", 200, start),
+		mutatingAuditEvent("system:kube-controller-manager", "update", "", "endpoints", "", "openshift-etcd", "etcd", 200, start),
+		mutatingAuditEvent("system:kube-controller-manager", "update", "discovery.k8s.io", "endpointslices", "", "openshift-etcd", "etcd-abcde", 200, start),
+		// not a platform namespace
+		mutatingAuditEvent("system:admin", "create", "", "configmaps", "", "e2e-test-foo-abcde", "foo", 201, start),
+		// an e2e service account in its own namespace
+		mutatingAuditEvent(e2eServiceAccount, "create", "", "configmaps", "", "e2e-test-foo-abcde", "bar", 201, start),
+		// cluster scoped, but not configuration
+		mutatingAuditEvent("system:admin", "create", "rbac.authorization.k8s.io", "clusterroles", "", "", "foo", 201, start),
+	} {
+		perNode[1].Add(ignored)
+	}
+
+	mutations := NewObjectMutations()
+	for _, nodeMutations := range perNode {
+		mutations.AddSummary(nodeMutations)
+	}
+	recorded := mutations.Mutations()
+	if len(recorded) != 3 {
+		t.Fatalf("expected 3 recorded mutations, got %d: %v", len(recorded), recorded)
+	}
+	if !recorded[0].Time.Equal(start.Add(time.Second)) {
+		t.Errorf("expected the oldest mutation first, got %v", recorded[0])
+	}
+
+	// the mutations must answer who changed what after a round trip through the artifacts
+	artifactDir := t.TempDir()
+	if err := writeObjectMutations(artifactDir, "audit-log-object-mutations_test.json", mutations); err != nil {
+		t.Fatal(err)
+	}
+	recorded, err := ReadObjectMutations(filepath.Join(artifactDir, "audit-log-object-mutations_test.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := FindObjectMutations(recorded, "infrastructures", "", "cluster")
+	if len(found) != 1 {
+		t.Fatalf("expected the infrastructure patch, got %d", len(found))
+	}
+	expected := ObjectMutation{
+		Time:      start.Add(2 * time.Second),
+		Verb:      "patch",
+		Resource:  "infrastructures.config.openshift.io",
+		Name:      "cluster",
+		User:      "system:admin",
+		UserAgent: "kubectl/v1.29.0",
+		AuditID:   "audit-cluster",
+	}
+	if !found[0].Time.Equal(expected.Time) {
+		t.Errorf("expected the mutation at %v, got %v", expected.Time, found[0].Time)
+	}
+	found[0].Time = expected.Time
+	if found[0] != expected {
+		t.Errorf("expected %#v, got %#v", expected, found[0])
+	}
+
+	if found := FindObjectMutations(recorded, "configmaps", "openshift-etcd", "etcd-ca"); len(found) != 1 {
+		t.Errorf("expected the configmap update, got %d", len(found))
+	}
+	if found := FindObjectMutations(recorded, "configmaps", "openshift-kube-apiserver", "etcd-ca"); len(found) != 0 {
+		t.Errorf("expected nothing in another namespace, got %d", len(found))
+	}
+	if found := FindObjectMutations(recorded, "infrastructures.config.openshift.io", "", "cluster"); len(found) != 1 {
+		t.Errorf("expected the resource to match with its group, got %d", len(found))
+	}
+
+	junits := evaluateE2EServiceAccountMutations(recorded)
+	if len(junits) != 2 || junits[0].FailureOutput == nil || junits[1].FailureOutput != nil {
+		t.Fatalf("expected the e2e service account creating a clusterrole to flake, got %v", junits)
+	}
+	if !strings.Contains(junits[0].FailureOutput.Output, "escalate") {
+		t.Errorf("expected the failure to name the clusterrole, got %s", junits[0].FailureOutput.Output)
+	}
+}
@@ -0,0 +1,434 @@
+package auditloganalyzer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"k8s.io/apimachinery/pkg/util/sets"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+const (
+	// requestLatencySlot is the resolution requests are counted at, sliding windows are made of consecutive slots.
+	requestLatencySlot = 10 * time.Second
+	// requestLatencyWindowSlots is how many slots a sliding window spans.
+	requestLatencyWindowSlots = 6
+
+	// a window with fewer requests than this says nothing about the apiserver
+	minWindowRequests = 10
+	// a window is a slow brownout once this fraction of its requests are slow
+	slowWindowFraction = 0.1
+	// a window is an error burst once this many, and this fraction, of its requests are throttled or failed
+	minWindowErrors     = 5
+	errorWindowFraction = 0.05
+)
+
+// requestClass groups verbs whose requests are expected to take about as long.  The slow thresholds are the upstream
+// apiserver latency SLOs: one second for single objects, five for namespaced lists and thirty for cluster wide lists.
+type requestClass struct {
+	name  string
+	verbs sets.Set[string]
+
+	namespacedSlow time.Duration
+	clusterSlow    time.Duration
+	// allowedBrownout is how long requests of the class may be slow over a whole run before the junit fails.
+	allowedBrownout time.Duration
+}
+
+var requestClasses = []requestClass{
+	{
+		name:            "mutating",
+		verbs:           sets.New[string]("create", "update", "patch", "delete", "deletecollection"),
+		namespacedSlow:  time.Second,
+		clusterSlow:     time.Second,
+		allowedBrownout: 2 * time.Minute,
+	},
+	{
+		name:            "get",
+		verbs:           sets.New[string]("get"),
+		namespacedSlow:  time.Second,
+		clusterSlow:     time.Second,
+		allowedBrownout: 2 * time.Minute,
+	},
+	{
+		name:            "list",
+		verbs:           sets.New[string]("list"),
+		namespacedSlow:  5 * time.Second,
+		clusterSlow:     30 * time.Second,
+		allowedBrownout: 5 * time.Minute,
+	},
+}
+
+// allowedErrorBursts is how long requests may be throttled or failing over a whole run before the junit fails.
+const allowedErrorBursts = time.Minute
+
+// longRunningSubresources stream for as long as the client wants, their latency is meaningless.
+var longRunningSubresources = sets.New[string]("attach", "exec", "log", "portforward", "proxy")
+
+// latencyHistogramBuckets are the upper bounds of the latency histograms, the same as apiserver_request_duration_seconds.
+var latencyHistogramBuckets = []time.Duration{
+	5 * time.Millisecond, 25 * time.Millisecond, 50 * time.Millisecond, 100 * time.Millisecond, 200 * time.Millisecond,
+	400 * time.Millisecond, 600 * time.Millisecond, 800 * time.Millisecond, time.Second, 1250 * time.Millisecond,
+	1500 * time.Millisecond, 2 * time.Second, 3 * time.Second, 4 * time.Second, 5 * time.Second, 6 * time.Second,
+	8 * time.Second, 10 * time.Second, 15 * time.Second, 20 * time.Second, 30 * time.Second, 45 * time.Second,
+	time.Minute,
+}
+
+func requestClassForVerb(verb string) (requestClass, bool) {
+	for _, class := range requestClasses {
+		if class.verbs.Has(verb) {
+			return class, true
+		}
+	}
+	return requestClass{}, false
+}
+
+type requestKind struct {
+	verb     string
+	resource string
+}
+
+type slotCounts struct {
+	total        int
+	slow         int
+	throttled    int
+	serverErrors int
+}
+
+func (c *slotCounts) add(rhs slotCounts) {
+	c.total += rhs.total
+	c.slow += rhs.slow
+	c.throttled += rhs.throttled
+	c.serverErrors += rhs.serverErrors
+}
+
+type requestKindLatencies struct {
+	counts     slotCounts
+	histogram  []int
+	maxLatency time.Duration
+	// slots are keyed by the unix time of their start divided by requestLatencySlot
+	slots map[int64]*slotCounts
+}
+
+// RequestLatencies is the latency distribution and the time series of slow and failed requests, per verb and resource.
+// Like AuditLogSummary it is not threadsafe, use one per thread and combine them with AddSummary.
+type RequestLatencies struct {
+	perRequestKind map[requestKind]*requestKindLatencies
+}
+
+func NewRequestLatencies() *RequestLatencies {
+	return &RequestLatencies{
+		perRequestKind: map[requestKind]*requestKindLatencies{},
+	}
+}
+
+func newRequestKindLatencies() *requestKindLatencies {
+	return &requestKindLatencies{
+		histogram: make([]int, len(latencyHistogramBuckets)+1),
+		slots:     map[int64]*slotCounts{},
+	}
+}
+
+func (l *RequestLatencies) Add(auditEvent *auditv1.Event) {
+	if auditEvent.Stage != auditv1.StageResponseComplete || auditEvent.ResponseStatus == nil || auditEvent.ObjectRef == nil {
+		return
+	}
+	if len(auditEvent.ObjectRef.Resource) == 0 || longRunningSubresources.Has(auditEvent.ObjectRef.Subresource) {
+		return
+	}
+	class, ok := requestClassForVerb(auditEvent.Verb)
+	if !ok {
+		// watches and other long running requests
+		return
+	}
+
+	resource := auditEvent.ObjectRef.Resource
+	if len(auditEvent.ObjectRef.APIGroup) > 0 {
+		resource = resource + "." + auditEvent.ObjectRef.APIGroup
+	}
+	if len(auditEvent.ObjectRef.Subresource) > 0 {
+		resource = resource + "/" + auditEvent.ObjectRef.Subresource
+	}
+	kind := requestKind{verb: auditEvent.Verb, resource: resource}
+	if _, ok := l.perRequestKind[kind]; !ok {
+		l.perRequestKind[kind] = newRequestKindLatencies()
+	}
+	latencies := l.perRequestKind[kind]
+
+	latency := auditEvent.StageTimestamp.Sub(auditEvent.RequestReceivedTimestamp.Time)
+	slowThreshold := class.clusterSlow
+	if len(auditEvent.ObjectRef.Namespace) > 0 {
+		slowThreshold = class.namespacedSlow
+	}
+	counts := slotCounts{total: 1}
+	if latency > slowThreshold {
+		counts.slow = 1
+	}
+	switch code := auditEvent.ResponseStatus.Code; {
+	case code == 429:
+		counts.throttled = 1
+	case code >= 500 && code < 600:
+		counts.serverErrors = 1
+	}
+
+	latencies.counts.add(counts)
+	latencies.histogram[sort.Search(len(latencyHistogramBuckets), func(i int) bool { return latency <= latencyHistogramBuckets[i] })]++
+	if latency > latencies.maxLatency {
+		latencies.maxLatency = latency
+	}
+	slot := auditEvent.RequestReceivedTimestamp.Unix() / int64(requestLatencySlot/time.Second)
+	if _, ok := latencies.slots[slot]; !ok {
+		latencies.slots[slot] = &slotCounts{}
+	}
+	latencies.slots[slot].add(counts)
+}
+
+func (l *RequestLatencies) AddSummary(rhs *RequestLatencies) {
+	for kind, rhsLatencies := range rhs.perRequestKind {
+		if _, ok := l.perRequestKind[kind]; !ok {
+			l.perRequestKind[kind] = newRequestKindLatencies()
+		}
+		latencies := l.perRequestKind[kind]
+		latencies.counts.add(rhsLatencies.counts)
+		for i := range rhsLatencies.histogram {
+			latencies.histogram[i] += rhsLatencies.histogram[i]
+		}
+		if rhsLatencies.maxLatency > latencies.maxLatency {
+			latencies.maxLatency = rhsLatencies.maxLatency
+		}
+		for slot, counts := range rhsLatencies.slots {
+			if _, ok := latencies.slots[slot]; !ok {
+				latencies.slots[slot] = &slotCounts{}
+			}
+			latencies.slots[slot].add(*counts)
+		}
+	}
+}
+
+// quantile estimates a latency quantile as the upper bound of the histogram bucket it falls in.
+func (l *requestKindLatencies) quantile(q float64) time.Duration {
+	rank := int(math.Ceil(q * float64(l.counts.total)))
+	seen := 0
+	for i, count := range l.histogram {
+		seen += count
+		if seen >= rank && count > 0 {
+			if i == len(latencyHistogramBuckets) {
+				return l.maxLatency
+			}
+			return latencyHistogramBuckets[i]
+		}
+	}
+	return l.maxLatency
+}
+
+// Intervals returns a Warning interval for every stretch of time a sliding window over the requests of a verb and
+// resource was either sustainedly slow, or bursting with 429s and 5xxs.  These are apiserver brownouts that are too
+// partial to ever show up as disruption.
+func (l *RequestLatencies) Intervals() monitorapi.Intervals {
+	kinds := make([]requestKind, 0, len(l.perRequestKind))
+	for kind := range l.perRequestKind {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		if kinds[i].verb != kinds[j].verb {
+			return kinds[i].verb < kinds[j].verb
+		}
+		return kinds[i].resource < kinds[j].resource
+	})
+
+	ret := monitorapi.Intervals{}
+	for _, kind := range kinds {
+		latencies := l.perRequestKind[kind]
+		class, _ := requestClassForVerb(kind.verb)
+		locator := monitorapi.NewLocator().KubeAPIServerRequests(kind.verb, kind.resource)
+
+		slowSlots, errorSlots := latencies.brownoutSlots()
+		for _, slots := range mergeSlots(slowSlots) {
+			counts := latencies.sum(slots[0], slots[1])
+			ret = append(ret, monitorapi.NewInterval(monitorapi.SourceAuditLog, monitorapi.Warning).
+				Locator(locator).
+				Message(monitorapi.NewMessage().
+					Reason(monitorapi.APIServerSlowRequestsReason).
+					WithAnnotation(monitorapi.AnnotationCount, fmt.Sprintf("%d", counts.slow)).
+					HumanMessagef("%d of %d %s requests were slower than %s for namespaced or %s for cluster scoped requests",
+						counts.slow, counts.total, class.name, class.namespacedSlow, class.clusterSlow)).
+				Display().
+				Build(slotTime(slots[0]), slotTime(slots[1]+1)))
+		}
+		for _, slots := range mergeSlots(errorSlots) {
+			counts := latencies.sum(slots[0], slots[1])
+			ret = append(ret, monitorapi.NewInterval(monitorapi.SourceAuditLog, monitorapi.Warning).
+				Locator(locator).
+				Message(monitorapi.NewMessage().
+					Reason(monitorapi.APIServerErrorBurstReason).
+					WithAnnotation(monitorapi.AnnotationCount, fmt.Sprintf("%d", counts.throttled+counts.serverErrors)).
+					HumanMessagef("%d of %d requests were throttled with 429 and %d failed with 5xx",
+						counts.throttled, counts.total, counts.serverErrors)).
+				Display().
+				Build(slotTime(slots[0]), slotTime(slots[1]+1)))
+		}
+	}
+	sort.Sort(ret)
+	return ret
+}
+
+// brownoutSlots returns the slots that had slow or failed requests in a window that was a brownout.
+func (l *requestKindLatencies) brownoutSlots() (sets.Set[int64], sets.Set[int64]) {
+	slowSlots := sets.New[int64]()
+	errorSlots := sets.New[int64]()
+	for windowEnd := range l.slots {
+		windowStart := windowEnd - requestLatencyWindowSlots + 1
+		window := l.sum(windowStart, windowEnd)
+		if window.total < minWindowRequests {
+			continue
+		}
+		slow := float64(window.slow)/float64(window.total) >= slowWindowFraction
+		errors := window.throttled + window.serverErrors
+		burst := errors >= minWindowErrors && float64(errors)/float64(window.total) >= errorWindowFraction
+		if !slow && !burst {
+			continue
+		}
+		for slot := windowStart; slot <= windowEnd; slot++ {
+			counts, ok := l.slots[slot]
+			if !ok {
+				continue
+			}
+			if slow && counts.slow > 0 {
+				slowSlots.Insert(slot)
+			}
+			if burst && counts.throttled+counts.serverErrors > 0 {
+				errorSlots.Insert(slot)
+			}
+		}
+	}
+	return slowSlots, errorSlots
+}
+
+func (l *requestKindLatencies) sum(fromSlot, toSlot int64) slotCounts {
+	ret := slotCounts{}
+	for slot := fromSlot; slot <= toSlot; slot++ {
+		if counts, ok := l.slots[slot]; ok {
+			ret.add(*counts)
+		}
+	}
+	return ret
+}
+
+// mergeSlots merges slots less than a window apart into [first, last] ranges.
+func mergeSlots(slots sets.Set[int64]) [][2]int64 {
+	ret := [][2]int64{}
+	for _, slot := range sets.List(slots) {
+		if len(ret) > 0 && slot-ret[len(ret)-1][1] <= requestLatencyWindowSlots {
+			ret[len(ret)-1][1] = slot
+			continue
+		}
+		ret = append(ret, [2]int64{slot, slot})
+	}
+	return ret
+}
+
+func slotTime(slot int64) time.Time {
+	return time.Unix(slot*int64(requestLatencySlot/time.Second), 0).UTC()
+}
+
+// SerializedRequestLatency is the latency distribution of the requests of one verb on one resource.
+type SerializedRequestLatency struct {
+	Verb              string
+	Resource          string
+	RequestCount      int
+	SlowRequestCount  int
+	ThrottledCount    int
+	ServerErrorCount  int
+	P50LatencySeconds float64
+	P90LatencySeconds float64
+	P99LatencySeconds float64
+	MaxLatencySeconds float64
+}
+
+// NewSerializedRequestLatencies orders the distributions slowest first.
+func NewSerializedRequestLatencies(l *RequestLatencies) []SerializedRequestLatency {
+	ret := []SerializedRequestLatency{}
+	for kind, latencies := range l.perRequestKind {
+		ret = append(ret, SerializedRequestLatency{
+			Verb:              kind.verb,
+			Resource:          kind.resource,
+			RequestCount:      latencies.counts.total,
+			SlowRequestCount:  latencies.counts.slow,
+			ThrottledCount:    latencies.counts.throttled,
+			ServerErrorCount:  latencies.counts.serverErrors,
+			P50LatencySeconds: latencies.quantile(0.5).Seconds(),
+			P90LatencySeconds: latencies.quantile(0.9).Seconds(),
+			P99LatencySeconds: latencies.quantile(0.99).Seconds(),
+			MaxLatencySeconds: latencies.maxLatency.Seconds(),
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].P99LatencySeconds != ret[j].P99LatencySeconds {
+			return ret[i].P99LatencySeconds > ret[j].P99LatencySeconds
+		}
+		if ret[i].Verb != ret[j].Verb {
+			return ret[i].Verb < ret[j].Verb
+		}
+		return ret[i].Resource < ret[j].Resource
+	})
+	return ret
+}
+
+// evaluateRequestBrownouts fails a junit per request class whose slow brownouts add up to more than the class allows,
+// and one for error bursts.
+func evaluateRequestBrownouts(finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	slowIntervals := map[string]monitorapi.Intervals{}
+	errorIntervals := monitorapi.Intervals{}
+	for _, interval := range finalIntervals {
+		if interval.Source != monitorapi.SourceAuditLog {
+			continue
+		}
+		switch interval.Message.Reason {
+		case monitorapi.APIServerSlowRequestsReason:
+			class, ok := requestClassForVerb(interval.Locator.Keys[monitorapi.LocatorVerbKey])
+			if !ok {
+				continue
+			}
+			slowIntervals[class.name] = append(slowIntervals[class.name], interval)
+		case monitorapi.APIServerErrorBurstReason:
+			errorIntervals = append(errorIntervals, interval)
+		}
+	}
+
+	ret := []*junitapi.JUnitTestCase{}
+	for _, class := range requestClasses {
+		testName := fmt.Sprintf("[sig-api-machinery] kube-apiserver should not have sustained slow %s requests", class.name)
+		ret = append(ret, brownoutJUnits(testName, slowIntervals[class.name], class.allowedBrownout)...)
+	}
+	testName := "[sig-api-machinery] kube-apiserver should not have bursts of 429 or 5xx responses"
+	ret = append(ret, brownoutJUnits(testName, errorIntervals, allowedErrorBursts)...)
+	return ret
+}
+
+func brownoutJUnits(testName string, intervals monitorapi.Intervals, allowed time.Duration) []*junitapi.JUnitTestCase {
+	var total time.Duration
+	messages := []string{}
+	for _, interval := range intervals {
+		total += interval.To.Sub(interval.From)
+		messages = append(messages, interval.String())
+	}
+	if total <= allowed {
+		return []*junitapi.JUnitTestCase{{Name: testName}}
+	}
+
+	return []*junitapi.JUnitTestCase{
+		{
+			Name: testName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("requests browned out for %s, more than the %s allowed:\n\n%s", total, allowed, strings.Join(messages, "\n")),
+			},
+		},
+		// Brownouts follow the load the tests put on the apiserver, flake until the allowed time is known from real runs.
+		{Name: testName},
+	}
+}
@@ -0,0 +1,122 @@
+package auditloganalyzer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+func auditEvent(verb, namespace, resource string, code int32, received time.Time, latency time.Duration) *auditv1.Event {
+	return &auditv1.Event{
+		Stage: auditv1.StageResponseComplete,
+		Verb:  verb,
+		ObjectRef: &auditv1.ObjectReference{
+			Resource:  resource,
+			Namespace: namespace,
+		},
+		ResponseStatus:           &metav1.Status{Code: code},
+		RequestReceivedTimestamp: metav1.NewMicroTime(received),
+		StageTimestamp:           metav1.NewMicroTime(received.Add(latency)),
+	}
+}
+
+func TestRequestLatenciesIntervals(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	perNode := []*RequestLatencies{NewRequestLatencies(), NewRequestLatencies()}
+	for second := 0; second < 600; second++ {
+		now := start.Add(time.Duration(second) * time.Second)
+		latencies := perNode[second%2]
+
+		// gets are slow for half of the requests between minute 2 and 3
+		getLatency := 10 * time.Millisecond
+		if second >= 120 && second < 180 {
+			getLatency = 2 * time.Second
+		}
+		latencies.Add(auditEvent("get", "ns", "pods", 200, now, getLatency))
+		latencies.Add(auditEvent("get", "ns", "pods", 200, now, 10*time.Millisecond))
+
+		// a namespaced list this slow is a brownout, a cluster scoped one is not
+		latencies.Add(auditEvent("list", "", "secrets", 200, now, 10*time.Second))
+
+		// configmaps are throttled between minute 7 and 8
+		code := int32(200)
+		if second >= 420 && second < 480 {
+			code = 429
+		}
+		latencies.Add(auditEvent("update", "ns", "configmaps", code, now, 10*time.Millisecond))
+
+		// watches are never slow
+		latencies.Add(auditEvent("watch", "ns", "pods", 200, now, time.Hour))
+	}
+	latencies := NewRequestLatencies()
+	for _, nodeLatencies := range perNode {
+		latencies.AddSummary(nodeLatencies)
+	}
+
+	intervals := latencies.Intervals()
+	if len(intervals) != 2 {
+		t.Fatalf("expected a slow get and a throttled update interval, got %d:\n%s", len(intervals), strings.Join(intervals.Strings(), "\n"))
+	}
+
+	slow := intervals[0]
+	if slow.Message.Reason != monitorapi.APIServerSlowRequestsReason || slow.Locator.Keys[monitorapi.LocatorVerbKey] != "get" || slow.Locator.Keys[monitorapi.LocatorResourceKey] != "pods" {
+		t.Errorf("unexpected slow interval %s", slow)
+	}
+	if !slow.From.Equal(start.Add(2*time.Minute)) || !slow.To.Equal(start.Add(3*time.Minute)) {
+		t.Errorf("expected the slow interval to span minute 2 to 3, got %s to %s", slow.From, slow.To)
+	}
+	if slow.Message.Annotations[monitorapi.AnnotationCount] != "60" {
+		t.Errorf("expected 60 slow requests, got %s", slow.Message.Annotations[monitorapi.AnnotationCount])
+	}
+
+	burst := intervals[1]
+	if burst.Message.Reason != monitorapi.APIServerErrorBurstReason || burst.Locator.Keys[monitorapi.LocatorResourceKey] != "configmaps" {
+		t.Errorf("unexpected error burst interval %s", burst)
+	}
+	if !burst.From.Equal(start.Add(7*time.Minute)) || !burst.To.Equal(start.Add(8*time.Minute)) {
+		t.Errorf("expected the error burst to span minute 7 to 8, got %s to %s", burst.From, burst.To)
+	}
+
+	serialized := NewSerializedRequestLatencies(latencies)
+	if len(serialized) != 3 {
+		t.Fatalf("expected 3 request kinds, got %d", len(serialized))
+	}
+	if serialized[0].Verb != "list" || serialized[0].P99LatencySeconds != 10 {
+		t.Errorf("expected the list to be slowest with a p99 of 10s, got %#v", serialized[0])
+	}
+}
+
+func TestEvaluateRequestBrownouts(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	brownout := func(reason monitorapi.IntervalReason, verb string, duration time.Duration) monitorapi.Interval {
+		return monitorapi.NewInterval(monitorapi.SourceAuditLog, monitorapi.Warning).
+			Locator(monitorapi.NewLocator().KubeAPIServerRequests(verb, "pods")).
+			Message(monitorapi.NewMessage().Reason(reason)).
+			Build(start, start.Add(duration))
+	}
+
+	junits := evaluateRequestBrownouts(monitorapi.Intervals{
+		brownout(monitorapi.APIServerSlowRequestsReason, "get", time.Minute),
+		brownout(monitorapi.APIServerSlowRequestsReason, "get", 2*time.Minute),
+		brownout(monitorapi.APIServerSlowRequestsReason, "list", time.Minute),
+		brownout(monitorapi.APIServerErrorBurstReason, "create", 30*time.Second),
+	})
+
+	failed := map[string]bool{}
+	for _, junit := range junits {
+		if junit.FailureOutput != nil {
+			failed[junit.Name] = true
+		}
+	}
+	if len(failed) != 1 || !failed["[sig-api-machinery] kube-apiserver should not have sustained slow get requests"] {
+		t.Errorf("expected only the get junit to fail, got %v", failed)
+	}
+	if len(junits) != 5 {
+		t.Errorf("expected a junit per request class, the error burst junit and a flake, got %d", len(junits))
+	}
+}
@@ -0,0 +1,388 @@
+package auditloganalyzer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/nodeaccess"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// currentAuditLog is the file the apiserver writes to.  It is renamed to audit-<timestamp>.log on rotation.
+	currentAuditLog = "audit.log"
+	// auditLogPollInterval is how often the audit logs of every node are read up to their end.
+	auditLogPollInterval = 30 * time.Second
+)
+
+// AuditEventHandler consumes audit events as the audit logs are streamed.  Handlers are never called concurrently.
+type AuditEventHandler interface {
+	HandleAuditEvent(auditEvent *auditv1.Event)
+}
+
+// HandleAuditEvent makes the summary, along with the request latencies and object mutations it tracks, an
+// AuditEventHandler.
+func (s *AuditLogSummary) HandleAuditEvent(auditEvent *auditv1.Event) {
+	s.Add(auditEvent, auditEventInfo{})
+}
+
+// auditLogFiles reads the audit log files of the nodes.
+type auditLogFiles interface {
+	List(ctx context.Context, nodeName string) ([]string, error)
+	// ReadFrom reads a file from an offset.  It returns no content when there is nothing past the offset.
+	ReadFrom(ctx context.Context, nodeName, filename string, offset int64) (io.ReadCloser, error)
+}
+
+type nodeAuditLogFiles struct {
+	kubeClient kubernetes.Interface
+	apiserver  string
+}
+
+func (f *nodeAuditLogFiles) List(ctx context.Context, nodeName string) ([]string, error) {
+	return getAuditLogFilenames(ctx, f.kubeClient, nodeName, f.apiserver)
+}
+
+func (f *nodeAuditLogFiles) ReadFrom(ctx context.Context, nodeName, filename string, offset int64) (io.ReadCloser, error) {
+	in, err := nodeaccess.StreamNodeLogFileFrom(ctx, f.kubeClient, nodeName, filepath.Join(f.apiserver, filename), offset)
+	var statusErr apierrors.APIStatus
+	if errors.As(err, &statusErr) && statusErr.Status().Code == http.StatusRequestedRangeNotSatisfiable {
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+	return in, err
+}
+
+// LostAuditLogRange is a time range of the audit log of a node that could not be read.
+type LostAuditLogRange struct {
+	NodeName string
+	From     time.Time
+	To       time.Time
+	Reason   string
+}
+
+func (r LostAuditLogRange) ToInterval() monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceAuditLogStreamer, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().NodeFromName(r.NodeName)).
+		Message(monitorapi.NewMessage().
+			Reason(monitorapi.AuditLogLostReason).
+			HumanMessagef("audit events were lost: %s", r.Reason)).
+		Display().
+		Build(r.From, r.To)
+}
+
+// AuditLogStreamer tails the audit logs of the control plane nodes for the whole run and feeds every audit event to
+// its handlers as it is read, instead of downloading every file at the end.  Only the read offset of every file is
+// kept, so memory is bounded by what the handlers keep.
+//
+// Rotation is tracked through the directory listing: a rotated file that was not there before is the file that used
+// to be audit.log, so it is read on from the offset audit.log was read to, and audit.log starts over.  When a node
+// cannot be read, the files are read on from the same offsets once it can be again.  Whatever got rotated away in the
+// meantime shows up as a gap in the audit events and is reported as lost, as is everything after the last read of a
+// node that is still unreachable at the end.
+type AuditLogStreamer struct {
+	files     auditLogFiles
+	beginning time.Time
+	handlers  []AuditEventHandler
+
+	handlerLock sync.Mutex
+	tails       []*nodeAuditLogTail
+	stop        context.CancelFunc
+	wg          sync.WaitGroup
+}
+
+func NewAuditLogStreamer(kubeClient kubernetes.Interface, apiserver string, beginning time.Time, handlers ...AuditEventHandler) *AuditLogStreamer {
+	return newAuditLogStreamer(&nodeAuditLogFiles{kubeClient: kubeClient, apiserver: apiserver}, beginning, handlers...)
+}
+
+func newAuditLogStreamer(files auditLogFiles, beginning time.Time, handlers ...AuditEventHandler) *AuditLogStreamer {
+	return &AuditLogStreamer{
+		files:     files,
+		beginning: beginning,
+		handlers:  handlers,
+	}
+}
+
+// Start tails the audit logs of the control plane nodes until Stop.  Control plane nodes added later are not tailed.
+func (s *AuditLogStreamer) Start(ctx context.Context, kubeClient kubernetes.Interface) error {
+	masterOnly, err := labels.NewRequirement("node-role.kubernetes.io/master", selection.Exists, nil)
+	if err != nil {
+		return err
+	}
+	nodes, err := kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{
+		LabelSelector: labels.NewSelector().Add(*masterOnly).String(),
+	})
+	if err != nil {
+		return err
+	}
+	nodeNames := []string{}
+	for _, node := range nodes.Items {
+		nodeNames = append(nodeNames, node.Name)
+	}
+	s.start(ctx, nodeNames, auditLogPollInterval)
+	return nil
+}
+
+func (s *AuditLogStreamer) start(ctx context.Context, nodeNames []string, pollInterval time.Duration) {
+	ctx, s.stop = context.WithCancel(ctx)
+	for _, nodeName := range nodeNames {
+		tail := &nodeAuditLogTail{
+			streamer: s,
+			nodeName: nodeName,
+			lastRead: s.beginning,
+		}
+		s.tails = append(s.tails, tail)
+
+		s.wg.Add(1)
+		go func(tail *nodeAuditLogTail) {
+			defer s.wg.Done()
+			ticker := time.NewTicker(pollInterval)
+			defer ticker.Stop()
+			for {
+				tail.poll(ctx, time.Time{})
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(tail)
+	}
+}
+
+// Stop reads every audit log up to end one last time, stops tailing and returns the time ranges that were lost.
+func (s *AuditLogStreamer) Stop(ctx context.Context, end time.Time) []LostAuditLogRange {
+	if s.stop != nil {
+		s.stop()
+	}
+	s.wg.Wait()
+
+	wg := sync.WaitGroup{}
+	for _, tail := range s.tails {
+		wg.Add(1)
+		go func(tail *nodeAuditLogTail) {
+			defer wg.Done()
+			tail.poll(ctx, end)
+			tail.finish(end)
+		}(tail)
+	}
+	wg.Wait()
+
+	ret := []LostAuditLogRange{}
+	for _, tail := range s.tails {
+		ret = append(ret, tail.lost...)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if !ret[i].From.Equal(ret[j].From) {
+			return ret[i].From.Before(ret[j].From)
+		}
+		return ret[i].NodeName < ret[j].NodeName
+	})
+	return ret
+}
+
+func (s *AuditLogStreamer) handle(auditEvent *auditv1.Event) {
+	s.handlerLock.Lock()
+	defer s.handlerLock.Unlock()
+	for _, handler := range s.handlers {
+		handler.HandleAuditEvent(auditEvent)
+	}
+}
+
+// nodeAuditLogTail is the read state of the audit logs of one node.  It is only used by one goroutine at a time.
+type nodeAuditLogTail struct {
+	streamer *AuditLogStreamer
+	nodeName string
+
+	// offsets are how far every file has been read, nil until the first listing.  Files that were rotated before
+	// the streaming started are never read.
+	offsets map[string]int64
+	// lastRead is the last time every file was read up to its end.
+	lastRead time.Time
+	// lastEvent is the time of the last audit event read.
+	lastEvent time.Time
+	// failure is why the node could not be read since lastRead, empty while it can be.
+	failure string
+	// recovering is set from a failure to read until the next audit event is read.
+	recovering bool
+	lost       []LostAuditLogRange
+
+	lineReadFailureCount int
+}
+
+// poll reads every audit log of the node to its end.  Events after end are skipped, unless it is zero.
+func (t *nodeAuditLogTail) poll(ctx context.Context, end time.Time) {
+	now := time.Now()
+	if err := t.readAll(ctx, end); err != nil {
+		if len(t.failure) == 0 {
+			logrus.WithError(err).Warningf("unable to read the audit logs of %s, retrying", t.nodeName)
+		}
+		t.failure = err.Error()
+		t.recovering = true
+		return
+	}
+	t.lastRead = now
+	t.failure = ""
+}
+
+func (t *nodeAuditLogTail) readAll(ctx context.Context, end time.Time) error {
+	filenames, err := t.streamer.files.List(ctx, t.nodeName)
+	if err != nil {
+		return err
+	}
+	rotated := []string{}
+	current := false
+	for _, filename := range filenames {
+		switch {
+		case filename == currentAuditLog:
+			current = true
+		case strings.HasPrefix(filename, "audit") && strings.HasSuffix(filename, ".log"):
+			rotated = append(rotated, filename)
+		}
+	}
+	// the rotated files are named by when they were rotated, so they sort oldest first
+	sort.Strings(rotated)
+
+	if t.offsets == nil {
+		t.offsets = map[string]int64{}
+		for _, filename := range rotated {
+			t.offsets[filename] = -1
+		}
+	}
+	newlyRotated := []string{}
+	for _, filename := range rotated {
+		if _, ok := t.offsets[filename]; !ok {
+			newlyRotated = append(newlyRotated, filename)
+		}
+	}
+	for i, filename := range newlyRotated {
+		if i == 0 {
+			// the oldest newly rotated file is what audit.log was read from, the others were never seen
+			t.offsets[filename] = t.offsets[currentAuditLog]
+			continue
+		}
+		t.offsets[filename] = 0
+	}
+	if len(newlyRotated) > 0 {
+		t.offsets[currentAuditLog] = 0
+	}
+	listed := map[string]bool{}
+	for _, filename := range rotated {
+		listed[filename] = true
+	}
+	for filename := range t.offsets {
+		if filename != currentAuditLog && !listed[filename] {
+			// rotated away by the apiserver, whatever was not read is lost and shows as a gap
+			delete(t.offsets, filename)
+		}
+	}
+
+	toRead := append([]string{}, rotated...)
+	if current {
+		toRead = append(toRead, currentAuditLog)
+	}
+	for _, filename := range toRead {
+		if t.offsets[filename] < 0 {
+			continue
+		}
+		if err := t.readFile(ctx, filename, end); err != nil {
+			return fmt.Errorf("%s: %w", filename, err)
+		}
+	}
+	return nil
+}
+
+func (t *nodeAuditLogTail) readFile(ctx context.Context, filename string, end time.Time) error {
+	in, err := t.streamer.files.ReadFrom(ctx, t.nodeName, filename, t.offsets[filename])
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	reader := bufio.NewReaderSize(in, 64*1024)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// a partial line is still being written, it is read again from its start on the next poll
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		t.offsets[filename] += int64(len(line))
+		t.handleLine(line, end)
+	}
+}
+
+func (t *nodeAuditLogTail) handleLine(line []byte, end time.Time) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return
+	}
+	auditEvent := &auditv1.Event{}
+	if err := json.Unmarshal(line, auditEvent); err != nil {
+		t.lineReadFailureCount++
+		return
+	}
+
+	received := auditEvent.RequestReceivedTimestamp.Time
+	if received.Before(t.streamer.beginning) || (!end.IsZero() && received.After(end)) {
+		return
+	}
+	// a busy apiserver logs constantly, so a gap after a failure to read is a range that was rotated away
+	if t.recovering {
+		t.recovering = false
+		previous := t.lastEvent
+		if previous.IsZero() {
+			previous = t.streamer.beginning
+		}
+		if received.Sub(previous) > auditLogPollInterval {
+			t.lost = append(t.lost, LostAuditLogRange{
+				NodeName: t.nodeName,
+				From:     previous,
+				To:       received,
+				Reason:   "the audit log was rotated away while the node could not be read",
+			})
+		}
+	}
+	if received.After(t.lastEvent) {
+		t.lastEvent = received
+	}
+	t.streamer.handle(auditEvent)
+}
+
+// finish records everything since the last read as lost when the node could not be read at the end.
+func (t *nodeAuditLogTail) finish(end time.Time) {
+	if t.lineReadFailureCount > 0 {
+		logrus.Warningf("unable to decode %d audit log lines of %s", t.lineReadFailureCount, t.nodeName)
+	}
+	if len(t.failure) == 0 {
+		return
+	}
+	from := t.lastRead
+	if t.lastEvent.After(from) {
+		from = t.lastEvent
+	}
+	t.lost = append(t.lost, LostAuditLogRange{
+		NodeName: t.nodeName,
+		From:     from,
+		To:       end,
+		Reason:   fmt.Sprintf("the node could not be read at the end of the run: %s", t.failure),
+	})
+}
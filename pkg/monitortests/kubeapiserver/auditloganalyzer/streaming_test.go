@@ -0,0 +1,139 @@
+package auditloganalyzer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// fakeAuditLogFiles is the audit log directory of a single node.
+type fakeAuditLogFiles struct {
+	files       map[string][]byte
+	unreachable bool
+}
+
+func (f *fakeAuditLogFiles) List(ctx context.Context, nodeName string) ([]string, error) {
+	if f.unreachable {
+		return nil, fmt.Errorf("node %s is unreachable", nodeName)
+	}
+	ret := []string{}
+	for filename := range f.files {
+		ret = append(ret, filename)
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+func (f *fakeAuditLogFiles) ReadFrom(ctx context.Context, nodeName, filename string, offset int64) (io.ReadCloser, error) {
+	if f.unreachable {
+		return nil, fmt.Errorf("node %s is unreachable", nodeName)
+	}
+	data := f.files[filename]
+	if offset >= int64(len(data)) {
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+	return io.NopCloser(bytes.NewReader(data[offset:])), nil
+}
+
+func (f *fakeAuditLogFiles) write(t *testing.T, auditID string, at time.Time) {
+	line, err := json.Marshal(&auditv1.Event{
+		AuditID:                  types.UID(auditID),
+		Stage:                    auditv1.StageResponseComplete,
+		RequestReceivedTimestamp: metav1.NewMicroTime(at),
+		StageTimestamp:           metav1.NewMicroTime(at),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.files[currentAuditLog] = append(f.files[currentAuditLog], append(line, '\n')...)
+}
+
+func (f *fakeAuditLogFiles) rotate(rotatedName string) {
+	f.files[rotatedName] = f.files[currentAuditLog]
+	f.files[currentAuditLog] = nil
+}
+
+type recordingHandler struct {
+	auditIDs []string
+}
+
+func (h *recordingHandler) HandleAuditEvent(auditEvent *auditv1.Event) {
+	h.auditIDs = append(h.auditIDs, string(auditEvent.AuditID))
+}
+
+func TestAuditLogStreamer(t *testing.T) {
+	beginning := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return beginning.Add(time.Duration(seconds) * time.Second) }
+
+	files := &fakeAuditLogFiles{files: map[string][]byte{
+		"audit-2023-12-31T00-00-00.000.log": []byte("not read, rotated before the run\n"),
+	}}
+	files.write(t, "before-the-run", at(-10))
+	files.write(t, "a", at(1))
+
+	handler := &recordingHandler{}
+	streamer := newAuditLogStreamer(files, beginning, handler)
+	tail := &nodeAuditLogTail{streamer: streamer, nodeName: "master-0", lastRead: beginning}
+	streamer.tails = []*nodeAuditLogTail{tail}
+	ctx := context.Background()
+
+	tail.poll(ctx, time.Time{})
+
+	// a partially written line is read once it is complete
+	files.write(t, "b", at(2))
+	complete := files.files[currentAuditLog]
+	files.files[currentAuditLog] = complete[:len(complete)-10]
+	tail.poll(ctx, time.Time{})
+	files.files[currentAuditLog] = complete
+	files.write(t, "c", at(3))
+	tail.poll(ctx, time.Time{})
+
+	// the rest of a rotated file is read on from where audit.log was read to
+	files.write(t, "d", at(4))
+	files.rotate("audit-2024-01-01T00-00-05.000.log")
+	files.write(t, "e", at(5))
+	tail.poll(ctx, time.Time{})
+
+	// while the node is unreachable the log is rotated twice, and the oldest rotation is gone by the time it is back
+	files.unreachable = true
+	files.write(t, "f", at(6))
+	tail.poll(ctx, time.Time{})
+	files.rotate("audit-2024-01-01T00-01-00.000.log")
+	files.write(t, "lost", at(70))
+	files.rotate("audit-2024-01-01T00-02-00.000.log")
+	delete(files.files, "audit-2024-01-01T00-01-00.000.log")
+	files.write(t, "g", at(130))
+	files.unreachable = false
+	tail.poll(ctx, time.Time{})
+
+	// the node is unreachable again at the end
+	files.write(t, "after-the-end", at(300))
+	files.unreachable = true
+	lost := streamer.Stop(ctx, at(200))
+
+	expected := []string{"a", "b", "c", "d", "e", "g"}
+	if fmt.Sprint(handler.auditIDs) != fmt.Sprint(expected) {
+		t.Errorf("expected events %v, got %v", expected, handler.auditIDs)
+	}
+	if len(lost) != 2 {
+		t.Fatalf("expected 2 lost ranges, got %v", lost)
+	}
+	if !lost[0].From.Equal(at(5)) || !lost[0].To.Equal(at(130)) {
+		t.Errorf("expected the rotated away range to be lost from 5s to 130s, got %v to %v", lost[0].From, lost[0].To)
+	}
+	if lost[1].From.Before(at(130)) || !lost[1].To.Equal(at(200)) {
+		t.Errorf("expected the end of the run to be lost, got %v to %v", lost[1].From, lost[1].To)
+	}
+	if lost[0].NodeName != "master-0" || lost[0].ToInterval().Message.Reason != "AuditLogLost" {
+		t.Errorf("unexpected lost range %v", lost[0])
+	}
+}
@@ -0,0 +1,170 @@
+package legacykubeapiservermonitortests
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	// defaultAllowedResourceGrowth is the multiplier we allow resources without historical data before failing the
+	// test. (currently 40%)  It is a guess at what would have caught the Secret leak that took down clusters, which
+	// grew them by about 60% during upgrade.
+	defaultAllowedResourceGrowth = 1.4
+
+	// minimumResourceGrowth keeps resources with few objects from failing on a handful of new ones.
+	minimumResourceGrowth = 100
+
+	topResourceGrowers  = 10
+	topGrowerNamespaces = 3
+
+	resourceGrowthTestName = "[sig-trt] object counts should not have grown significantly during the run"
+)
+
+// resourceCounts is the number of objects of every resource by namespace, cluster scoped objects are counted under the
+// empty namespace.  Resources are plural and qualified with their group, like secrets or replicasets.apps.  Every
+// resource that was listed has an entry, even without objects.
+type resourceCounts map[string]map[string]int
+
+func (c resourceCounts) total(resource string) int {
+	total := 0
+	for _, count := range c[resource] {
+		total += count
+	}
+	return total
+}
+
+type ResourceGrowth struct {
+	Resource string
+	Before   int
+	After    int
+
+	// AllowedGrowth is the ratio of After to Before the resource may grow to, AllowedGrowthSource where it comes from.
+	AllowedGrowth       float64
+	AllowedGrowthSource string
+
+	// Namespaces are the namespaces the count changed in, the ones that grew the most first.
+	Namespaces []NamespaceGrowth
+}
+
+type NamespaceGrowth struct {
+	Namespace string
+	Before    int
+	After     int
+}
+
+func (g ResourceGrowth) exceeded() bool {
+	return g.After-g.Before > minimumResourceGrowth && float64(g.After) > float64(g.Before)*g.AllowedGrowth
+}
+
+func (g ResourceGrowth) String() string {
+	namespaces := []string{}
+	for i, namespace := range g.Namespaces {
+		if i == topGrowerNamespaces {
+			break
+		}
+		if namespace.After <= namespace.Before {
+			break
+		}
+		name := namespace.Namespace
+		if len(name) == 0 {
+			name = "<cluster>"
+		}
+		namespaces = append(namespaces, fmt.Sprintf("%s +%d", name, namespace.After-namespace.Before))
+	}
+	return fmt.Sprintf("%s count grew from %d to %d (max allowed=%d %s), grew most in: %s",
+		g.Resource, g.Before, g.After, int(float64(g.Before)*g.AllowedGrowth), g.AllowedGrowthSource, strings.Join(namespaces, ", "))
+}
+
+// computeResourceGrowth compares the counts of every resource listed both before and after, ordered by how many
+// objects they grew by.  Resources that could not be listed before, including those of CRDs created during the run,
+// have no baseline and are left out.
+func computeResourceGrowth(before, after resourceCounts, allowedGrowth func(resource string) (float64, string)) []ResourceGrowth {
+	ret := []ResourceGrowth{}
+	for resource, afterNamespaces := range after {
+		beforeNamespaces, ok := before[resource]
+		if !ok {
+			continue
+		}
+		growth := ResourceGrowth{
+			Resource: resource,
+			Before:   before.total(resource),
+			After:    after.total(resource),
+		}
+		growth.AllowedGrowth, growth.AllowedGrowthSource = allowedGrowth(resource)
+
+		for namespace := range unionKeys(beforeNamespaces, afterNamespaces) {
+			if beforeNamespaces[namespace] == afterNamespaces[namespace] {
+				continue
+			}
+			growth.Namespaces = append(growth.Namespaces, NamespaceGrowth{
+				Namespace: namespace,
+				Before:    beforeNamespaces[namespace],
+				After:     afterNamespaces[namespace],
+			})
+		}
+		sort.Slice(growth.Namespaces, func(i, j int) bool {
+			lhs, rhs := growth.Namespaces[i], growth.Namespaces[j]
+			if lhs.After-lhs.Before != rhs.After-rhs.Before {
+				return lhs.After-lhs.Before > rhs.After-rhs.Before
+			}
+			return lhs.Namespace < rhs.Namespace
+		})
+		ret = append(ret, growth)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].After-ret[i].Before != ret[j].After-ret[j].Before {
+			return ret[i].After-ret[i].Before > ret[j].After-ret[j].Before
+		}
+		return ret[i].Resource < ret[j].Resource
+	})
+	return ret
+}
+
+func unionKeys(lhs, rhs map[string]int) map[string]struct{} {
+	ret := map[string]struct{}{}
+	for key := range lhs {
+		ret[key] = struct{}{}
+	}
+	for key := range rhs {
+		ret[key] = struct{}{}
+	}
+	return ret
+}
+
+// evaluateResourceGrowth fails when any resource grew beyond what it is allowed, and reports the top growers either
+// way so a leak can be found before it is large enough to fail.
+func evaluateResourceGrowth(growths []ResourceGrowth) []*junitapi.JUnitTestCase {
+	topGrowers := []string{}
+	for i, growth := range growths {
+		if i == topResourceGrowers || growth.After <= growth.Before {
+			break
+		}
+		topGrowers = append(topGrowers, growth.String())
+	}
+	report := fmt.Sprintf("top growers:\n\n%s", strings.Join(topGrowers, "\n"))
+
+	exceeded := []string{}
+	for _, growth := range growths {
+		if growth.exceeded() {
+			exceeded = append(exceeded, growth.String())
+		}
+	}
+	if len(exceeded) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: resourceGrowthTestName, SystemOut: report}}
+	}
+	return []*junitapi.JUnitTestCase{
+		{
+			Name: resourceGrowthTestName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("%d resources grew significantly during the run:\n\n%s\n\n%s", len(exceeded), strings.Join(exceeded, "\n"), report),
+			},
+			SystemOut: report,
+		},
+		// Most resources have no historical growth yet and are held to the default allowance, so exceeding it only flakes.
+		{Name: resourceGrowthTestName, SystemOut: report},
+	}
+}
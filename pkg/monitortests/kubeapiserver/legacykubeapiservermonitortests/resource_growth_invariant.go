@@ -2,74 +2,99 @@ package legacykubeapiservermonitortests
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
 	"time"
 
-	"github.com/openshift/origin/pkg/monitortestframework"
-
-	routeclient "github.com/openshift/client-go/route/clientset/versioned"
-
-	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
-
-	utilerrors "k8s.io/apimachinery/pkg/util/errors"
-
-	v1 "github.com/openshift/api/config/v1"
-	configclient "github.com/openshift/client-go/config/clientset/versioned"
-	"github.com/openshift/library-go/test/library/metrics"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/allowedresourcegrowth"
+	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
-	helper "github.com/openshift/origin/test/extended/util/prometheus"
+	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
-	e2e "k8s.io/kubernetes/test/e2e/framework"
 )
 
 const (
-	// allowedResourceGrowth is the multiplier we'll allow before failing the test. (currently 40%)
-	allowedResourceGrowth = 1.4
+	// partialObjectMetadataListAccept asks for the metadata of the objects only, we never need the content of
+	// secrets or configmaps to count them.
+	partialObjectMetadataListAccept = "application/json;as=PartialObjectMetadataList;v=v1;g=meta.k8s.io"
+	resourceCountPageSize           = 500
+)
 
-	secretTestName    = "[sig-trt] Secret count should not have grown significantly during upgrade"
-	configMapTestName = "[sig-trt] ConfigMap count should not have grown significantly during upgrade"
+// uncountedGroups and uncountedResources can be listed but are computed, not stored, so they cannot leak.
+var (
+	uncountedGroups    = sets.New[string]("metrics.k8s.io", "packages.operators.coreos.com")
+	uncountedResources = sets.New[string](
+		"componentstatuses",
+		"imagestreamtags.image.openshift.io",
+		"imagetags.image.openshift.io",
+		"projects.project.openshift.io",
+	)
 )
 
 type resourceGrowthTests struct {
 	adminRESTConfig *rest.Config
-	// PreUpgradeResourceCounts stores a map of resource type to a count of the number of
-	// resources of that type in the entire cluster, gathered prior to launching the upgrade.
-	preUpgradeResourceCounts map[string]int
+	jobType         *platformidentification.JobType
+
+	// beforeCounts are the object counts of every resource, listed before the run starts.
+	beforeCounts resourceCounts
+	growths      []ResourceGrowth
 }
 
+// NewResourceGrowthTests counts the objects of every resource before and after the run and fails when any of them grew
+// more than it does historically, to catch controllers and operators leaking objects.  This is in response to a bug
+// discovered where operators were leaking Secrets and ultimately taking down clusters.
 func NewResourceGrowthTests() monitortestframework.MonitorTest {
-	return &resourceGrowthTests{
-		preUpgradeResourceCounts: map[string]int{},
-	}
+	return &resourceGrowthTests{}
 }
 
 func (w *resourceGrowthTests) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
 	w.adminRESTConfig = adminRESTConfig
 
-	kubeClient, err := kubernetes.NewForConfig(w.adminRESTConfig)
+	jobType, err := platformidentification.GetJobType(ctx, adminRESTConfig)
 	if err != nil {
-		return err
+		// without a job type every resource is held to the default growth
+		logrus.WithError(err).Warning("unable to determine the job type, resource growth will not use historical data")
 	}
-	// Store resource counts we're interested in monitoring from before upgrade to after.
-	// Used to test for excessive resource growth during upgrade in the invariants.
-	secrets, err := kubeClient.CoreV1().Secrets("").List(ctx, metav1.ListOptions{})
+	w.jobType = jobType
+
+	kubeClient, err := kubernetes.NewForConfig(w.adminRESTConfig)
 	if err != nil {
 		return err
 	}
-	w.preUpgradeResourceCounts["secrets"] = len(secrets.Items)
-
-	configMaps, err := kubeClient.CoreV1().ConfigMaps("").List(ctx, metav1.ListOptions{})
+	// Resources that could not be listed have no baseline and are not evaluated, that is no reason to fail the run.
+	w.beforeCounts, err = listResourceCounts(ctx, kubeClient)
 	if err != nil {
-		return err
+		logrus.WithError(err).Warning("unable to count some resources before the run")
 	}
-	w.preUpgradeResourceCounts["configmaps"] = len(configMaps.Items)
 	return nil
 }
 
 func (w *resourceGrowthTests) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.beforeCounts == nil {
+		return nil, nil, nil
+	}
+	kubeClient, err := kubernetes.NewForConfig(w.adminRESTConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterCounts, err := listResourceCounts(ctx, kubeClient)
+	if err != nil {
+		logrus.WithError(err).Warning("unable to count some resources after the run")
+	}
+	w.growths = computeResourceGrowth(w.beforeCounts, afterCounts, w.allowedGrowth)
 	return nil, nil, nil
 }
 
@@ -78,145 +103,130 @@ func (*resourceGrowthTests) ConstructComputedIntervals(ctx context.Context, star
 }
 
 func (w *resourceGrowthTests) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	junits := []*junitapi.JUnitTestCase{}
-	errs := []error{}
-
-	localJunits, err := w.comparePostUpgradeResourceCountFromMetrics(ctx, secretTestName, "secrets")
-	errs = append(errs, err)
-	junits = append(junits, localJunits...)
-
-	localJunits, err = w.comparePostUpgradeResourceCountFromMetrics(ctx, configMapTestName, "configmaps")
-	errs = append(errs, err)
-	junits = append(junits, localJunits...)
-
-	return junits, utilerrors.NewAggregate(errs)
+	if w.growths == nil {
+		return nil, nil
+	}
+	return evaluateResourceGrowth(w.growths), nil
 }
 
-func (*resourceGrowthTests) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return nil
+func (w *resourceGrowthTests) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	if w.growths == nil {
+		return nil
+	}
+	jsonContent, err := json.MarshalIndent(w.growths, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(storageDir, fmt.Sprintf("resource-growth%s.json", timeSuffix)), jsonContent, 0644)
 }
 
 func (*resourceGrowthTests) Cleanup(ctx context.Context) error {
 	return nil
 }
 
-// comparePostUpgradeResourceCountFromMetrics tests that some counts for certain resources we're most interested
-// in potentially leaking do not increase substantially during upgrade.
-// This is in response to a bug discovered where operators were leaking Secrets and ultimately taking down clusters.
-// The counts have to be recorded before upgrade which is done in test/e2e/upgrade/upgrade.go, stored in a package
-// variable, and then read here in invariant. This is to work around the problems where our normal ginko tests are
-// all run in separate processes by themselves.
-//
-// Values for comparison are a guess at what would have caught the leak we saw. (growth of about 60% during upgrade)
-//
-// resource should be all lowercase and plural such as "secrets".
-func (w *resourceGrowthTests) comparePostUpgradeResourceCountFromMetrics(ctx context.Context, testName, resource string) ([]*junitapi.JUnitTestCase, error) {
-	preUpgradeCount := w.preUpgradeResourceCounts[resource]
-
-	// if the check on clusterversion returns a junit, we are done. most likely there was a problem
-	// getting the cv or possibly the upgrade completion time was null because the rollback is still in
-	// progress and the test has given up.
-	cv, err := w.getAndCheckClusterVersion()
-	if err != nil {
-		return nil, err
+// allowedGrowth is the P99 growth of the resource on this job type, or the default when there is no historical data.
+func (w *resourceGrowthTests) allowedGrowth(resource string) (float64, string) {
+	if w.jobType == nil {
+		return defaultAllowedResourceGrowth, "(default, unknown job type)"
 	}
-	upgradeCompletion := cv.Status.History[0].CompletionTime
-
-	prometheusClient, err := w.newPrometheusClient(ctx)
-	if err != nil {
-		return nil, err
+	p99, details, err := allowedresourcegrowth.GetHistoricalData().BestMatchP99(historicaldata.ResourceGrowthDataKey{
+		Resource: resource,
+		JobType:  *w.jobType,
+	})
+	if err != nil || p99 == nil {
+		return defaultAllowedResourceGrowth, fmt.Sprintf("(default, %s)", details)
 	}
-
-	// Use prometheus to get the resource count at the moment we recorded upgrade complete. We can't do this
-	// for the starting count as prometheus metrics seem to get wiped during the upgrade. We also don't want to
-	// just list the resources right now, as we don't know what other tests might have created since.
-	e2e.Logf("querying metrics at: %s", upgradeCompletion.Time.UTC().Format(time.RFC3339))
-	resourceCountPromQuery := fmt.Sprintf(`cluster:usage:resources:sum{resource="%s"}`, resource)
-	promResultsCompletion, err := helper.RunQueryAtTime(ctx, prometheusClient,
-		resourceCountPromQuery, upgradeCompletion.Time)
-	if err != nil {
-		return []*junitapi.JUnitTestCase{
-			{
-				Name: testName,
-				FailureOutput: &junitapi.FailureOutput{
-					Output: "Error getting resource count from Prometheus at upgrade completion time: " + err.Error(),
-				},
-			},
-		}, nil
-
+	// historical data for a resource that only ever shrank must still allow it to stay level
+	if *p99 < 1 {
+		return 1, fmt.Sprintf("(P99 %s)", details)
 	}
-
-	if len(promResultsCompletion.Data.Result) == 0 {
-		return []*junitapi.JUnitTestCase{
-			{
-				Name: testName,
-				FailureOutput: &junitapi.FailureOutput{
-					Output: "Post-upgrade resource count metric data has no Result",
-				},
-			},
-		}, nil
-	}
-	completedCount := int(promResultsCompletion.Data.Result[0].Value)
-
-	// Ensure that a resource count did not grow more than allowed:
-	maxAllowedCount := int(float64(preUpgradeCount) * allowedResourceGrowth)
-	output := fmt.Sprintf("%s count grew from %d to %d during upgrade (max allowed=%d). This test is experimental and may need adjusting in some cases.",
-		resource, preUpgradeCount, completedCount, maxAllowedCount)
-
-	if completedCount > maxAllowedCount {
-		return []*junitapi.JUnitTestCase{
-			{
-				Name: testName,
-				FailureOutput: &junitapi.FailureOutput{
-					Output: output,
-				},
-			},
-		}, nil
-	}
-	return []*junitapi.JUnitTestCase{
-		{Name: testName},
-	}, nil
-
+	return *p99, fmt.Sprintf("(P99 %s)", details)
 }
 
-func (w *resourceGrowthTests) newPrometheusClient(ctx context.Context) (prometheusv1.API, error) {
-	kubeClient, err := kubernetes.NewForConfig(w.adminRESTConfig)
-	if err != nil {
-		return nil, err
-	}
-	routeClient, err := routeclient.NewForConfig(w.adminRESTConfig)
-	if err != nil {
-		return nil, err
-	}
-	prometheusClient, err := metrics.NewPrometheusClient(ctx, kubeClient, routeClient)
+// listResourceCounts counts the objects of every listable resource by namespace, paging through their metadata.  The
+// objects of e2e test namespaces, and the namespaces themselves, are left out since tests create and delete those by
+// design.  Resources that fail to list are left out of the counts and returned in the error.
+func listResourceCounts(ctx context.Context, kubeClient kubernetes.Interface) (resourceCounts, error) {
+	errs := []error{}
+	resourceLists, err := kubeClient.Discovery().ServerPreferredResources()
 	if err != nil {
-		return nil, err
-	}
-	return prometheusClient, nil
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			return nil, err
+		}
+		// the groups of unavailable aggregated apiservers are missing, count the rest
+		errs = append(errs, err)
+	}
+
+	counts := resourceCounts{}
+	for _, resourceList := range resourceLists {
+		groupVersion, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if uncountedGroups.Has(groupVersion.Group) {
+			continue
+		}
+		for _, apiResource := range resourceList.APIResources {
+			if strings.Contains(apiResource.Name, "/") || !sets.New[string](apiResource.Verbs...).Has("list") {
+				continue
+			}
+			resource := apiResource.Name
+			resourcePath := path.Join("/api", groupVersion.Version, apiResource.Name)
+			if len(groupVersion.Group) > 0 {
+				resource = resource + "." + groupVersion.Group
+				resourcePath = path.Join("/apis", groupVersion.Group, groupVersion.Version, apiResource.Name)
+			}
+			if uncountedResources.Has(resource) {
+				continue
+			}
+			if err := countResource(ctx, kubeClient.Discovery().RESTClient(), resource, resourcePath, counts); err != nil {
+				errs = append(errs, fmt.Errorf("unable to count %s: %w", resource, err))
+			}
+		}
+	}
+	return counts, utilerrors.NewAggregate(errs)
 }
 
-func (w *resourceGrowthTests) getAndCheckClusterVersion() (*v1.ClusterVersion, error) {
-	configClient, err := configclient.NewForConfig(w.adminRESTConfig)
-	if err != nil {
-		return nil, err
-	}
-
-	cv, err := configClient.ConfigV1().ClusterVersions().Get(context.Background(), "version", metav1.GetOptions{})
-
-	if err != nil {
-		return nil, fmt.Errorf("error getting ClusterVersion: %w", err)
-	}
-	if len(cv.Status.History) == 0 {
-		return nil, fmt.Errorf("clusterVersion.Status has no History")
-	}
+func countResource(ctx context.Context, restClient rest.Interface, resource, resourcePath string, counts resourceCounts) error {
+	namespaceCounts := map[string]int{}
+	continueToken := ""
+	for {
+		request := restClient.Get().AbsPath(resourcePath).
+			SetHeader("Accept", partialObjectMetadataListAccept).
+			Param("limit", fmt.Sprint(resourceCountPageSize))
+		if len(continueToken) > 0 {
+			request = request.Param("continue", continueToken)
+		}
+		raw, err := request.Do(ctx).Raw()
+		if err != nil {
+			return err
+		}
+		list := &metav1.PartialObjectMetadataList{}
+		if err := json.Unmarshal(raw, list); err != nil {
+			return err
+		}
+		for _, item := range list.Items {
+			if isE2ENamespace(item.Namespace) || (len(item.Namespace) == 0 && isNamespaceResource(resource) && isE2ENamespace(item.Name)) {
+				continue
+			}
+			namespaceCounts[item.Namespace]++
+		}
+		continueToken = list.Continue
+		if len(continueToken) == 0 {
+			break
+		}
+	}
+
+	// only record complete counts, a partial one would look like growth or shrinkage
+	counts[resource] = namespaceCounts
+	return nil
+}
 
-	// In the case that cv completionTime is nil meaning the version change (upgrade or
-	// rollback is still in progress), flake this case. The problem is likely a bigger
-	// problem and the job will fail for that. Returning a failure and fake success, so it
-	// will be marked as a flake.
-	if cv.Status.History[0].CompletionTime == nil {
-		return nil, fmt.Errorf("clusterVersion.completionTime is nil")
-	}
+func isE2ENamespace(namespace string) bool {
+	return strings.HasPrefix(namespace, "e2e-")
+}
 
-	return cv, nil
+func isNamespaceResource(resource string) bool {
+	return resource == "namespaces" || resource == "netnamespaces.network.openshift.io"
 }
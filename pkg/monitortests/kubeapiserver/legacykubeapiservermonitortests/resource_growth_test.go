@@ -0,0 +1,68 @@
+package legacykubeapiservermonitortests
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResourceGrowth(t *testing.T) {
+	before := resourceCounts{
+		"secrets":              {"openshift-leaky": 1000, "openshift-etcd": 100},
+		"events":               {"openshift-etcd": 500},
+		"replicasets.apps":     {"openshift-etcd": 10},
+		"configmaps":           {"openshift-etcd": 100},
+		"nodes":                {"": 6},
+		"unlisted.example.com": {"": 1},
+	}
+	after := resourceCounts{
+		// 1100 to 1700 is beyond the 1.4 default
+		"secrets": {"openshift-leaky": 1600, "openshift-etcd": 100},
+		// 500 to 1200 is within the historical allowance
+		"events": {"openshift-etcd": 900, "openshift-kube-apiserver": 300},
+		// five times as many, but by too few to matter
+		"replicasets.apps": {"openshift-etcd": 50},
+		"configmaps":       {"openshift-etcd": 90},
+		"nodes":            {"": 6},
+		// created during the run, no baseline
+		"widgets.example.com": {"": 10000},
+	}
+	allowedGrowth := func(resource string) (float64, string) {
+		if resource == "events" {
+			return 3, "(P99)"
+		}
+		return defaultAllowedResourceGrowth, "(default)"
+	}
+
+	growths := computeResourceGrowth(before, after, allowedGrowth)
+	resources := []string{}
+	for _, growth := range growths {
+		resources = append(resources, growth.Resource)
+	}
+	if expected := "events secrets replicasets.apps nodes configmaps"; strings.Join(resources, " ") != expected {
+		t.Fatalf("expected resources ordered by growth %q, got %q", expected, strings.Join(resources, " "))
+	}
+	events := growths[0]
+	if events.Before != 500 || events.After != 1200 || len(events.Namespaces) != 2 || events.Namespaces[0].Namespace != "openshift-etcd" {
+		t.Errorf("unexpected events growth %+v", events)
+	}
+	if len(growths[3].Namespaces) != 0 {
+		t.Errorf("expected no changed namespaces for nodes, got %+v", growths[3].Namespaces)
+	}
+
+	junits := evaluateResourceGrowth(growths)
+	if len(junits) != 2 || junits[0].FailureOutput == nil || junits[1].FailureOutput != nil {
+		t.Fatalf("expected a flake, got %+v", junits)
+	}
+	output := junits[0].FailureOutput.Output
+	if !strings.HasPrefix(output, "1 resources grew significantly") || !strings.Contains(output, "secrets count grew from 1100 to 1700 (max allowed=1540 (default)), grew most in: openshift-leaky +600") {
+		t.Errorf("unexpected failure output:\n%s", output)
+	}
+	if strings.Contains(junits[0].SystemOut, "configmaps") || !strings.Contains(junits[0].SystemOut, "openshift-etcd +400, openshift-kube-apiserver +300") {
+		t.Errorf("expected the growers with their namespaces in the report, got:\n%s", junits[0].SystemOut)
+	}
+
+	delete(after, "secrets")
+	if junits := evaluateResourceGrowth(computeResourceGrowth(before, after, allowedGrowth)); len(junits) != 1 || junits[0].FailureOutput != nil {
+		t.Errorf("expected a pass, got %+v", junits)
+	}
+}
@@ -0,0 +1,211 @@
+package watchcertificates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/library-go/pkg/certs/cert-inspection/certgraphanalysis"
+	"github.com/openshift/library-go/pkg/certs/cert-inspection/certgraphapi"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	informercorev1 "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const testName = "[sig-arch] serving certificates should not be rotated before their signer is in the CA bundles that trust them"
+
+type certificateWatcher struct {
+	kubeClient kubernetes.Interface
+	tracker    *tracker
+	cancelFn   context.CancelFunc
+
+	// watchedNamespaceLock guards watchedNamespaces, the platform namespaces secrets and configmaps are watched in.
+	watchedNamespaceLock sync.Mutex
+	watchedNamespaces    sets.Set[string]
+
+	startPKIList *certgraphapi.PKIList
+	endPKIList   *certgraphapi.PKIList
+}
+
+// NewCertificateWatcher records the TLS artifacts in platform namespaces at the start and end of the run and follows
+// certificate rotation and CA bundle distribution in between.  Certificates within info.CertificateExpiryWarningDays
+// of expiring are reported.
+func NewCertificateWatcher(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTest {
+	expiryWarningDays := info.CertificateExpiryWarningDays
+	if expiryWarningDays <= 0 {
+		expiryWarningDays = defaultExpiryWarningDays
+	}
+	return &certificateWatcher{
+		tracker:           newTracker(time.Duration(expiryWarningDays) * 24 * time.Hour),
+		watchedNamespaces: sets.New[string](),
+	}
+}
+
+func (w *certificateWatcher) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	kubeClient, err := kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	w.kubeClient = kubeClient
+
+	w.startPKIList, err = gatherPKIList(ctx, kubeClient)
+	if err != nil {
+		return err
+	}
+
+	ctx, w.cancelFn = context.WithCancel(ctx)
+	// Secrets and configmaps are watched per platform namespace, watching them in every namespace would cache all the
+	// secrets and configmaps e2e tests create.
+	namespaceInformer := informercorev1.NewNamespaceInformer(kubeClient, time.Hour, nil)
+	namespaceInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			w.watchNamespace(ctx, obj)
+		},
+	})
+	go namespaceInformer.Run(ctx.Done())
+
+	return nil
+}
+
+// watchNamespace starts watching the secrets and configmaps of a platform namespace the first time it is seen.
+func (w *certificateWatcher) watchNamespace(ctx context.Context, obj interface{}) {
+	namespace, ok := obj.(*corev1.Namespace)
+	if !ok || !isPlatformNamespace(namespace.Name) {
+		return
+	}
+	w.watchedNamespaceLock.Lock()
+	defer w.watchedNamespaceLock.Unlock()
+	if w.watchedNamespaces.Has(namespace.Name) {
+		return
+	}
+	w.watchedNamespaces.Insert(namespace.Name)
+
+	secretInformer := informercorev1.NewSecretInformer(w.kubeClient, namespace.Name, time.Hour, nil)
+	secretInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			w.observeSecret(obj)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			w.observeSecret(obj)
+		},
+	})
+	configMapInformer := informercorev1.NewConfigMapInformer(w.kubeClient, namespace.Name, time.Hour, nil)
+	configMapInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			w.observeConfigMap(obj)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			w.observeConfigMap(obj)
+		},
+	})
+	go secretInformer.Run(ctx.Done())
+	go configMapInformer.Run(ctx.Done())
+}
+
+func (w *certificateWatcher) observeSecret(obj interface{}) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return
+	}
+	if err := w.tracker.observeSecret(time.Now(), secret); err != nil {
+		logrus.WithError(err).Warning("unable to inspect secret")
+	}
+}
+
+func (w *certificateWatcher) observeConfigMap(obj interface{}) {
+	configMap, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		return
+	}
+	if err := w.tracker.observeConfigMap(time.Now(), configMap); err != nil {
+		logrus.WithError(err).Warning("unable to inspect configmap")
+	}
+}
+
+func (w *certificateWatcher) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.kubeClient == nil {
+		return nil, nil, nil
+	}
+
+	var err error
+	w.endPKIList, err = gatherPKIList(ctx, w.kubeClient)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return w.tracker.computeIntervals(beginning, end), nil, nil
+}
+
+func (*certificateWatcher) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, nil
+}
+
+func (*certificateWatcher) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	gaps := finalIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceCertificateMonitor &&
+			eventInterval.Message.Reason == monitorapi.CABundleMissingSignerReason &&
+			eventInterval.Level == monitorapi.Error
+	})
+	if len(gaps) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: testName}}, nil
+	}
+
+	messages := []string{}
+	for _, gap := range gaps {
+		messages = append(messages, gap.String())
+	}
+	return []*junitapi.JUnitTestCase{
+		{
+			Name: testName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("%d CA bundles were missing the signer of a rotated serving certificate:\n\n%s", len(gaps), strings.Join(messages, "\n")),
+			},
+		},
+	}, nil
+}
+
+func (w *certificateWatcher) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	if err := writePKIList(filepath.Join(storageDir, fmt.Sprintf("tls-artifacts-start%s.json", timeSuffix)), w.startPKIList); err != nil {
+		return err
+	}
+	return writePKIList(filepath.Join(storageDir, fmt.Sprintf("tls-artifacts-end%s.json", timeSuffix)), w.endPKIList)
+}
+
+func (w *certificateWatcher) Cleanup(ctx context.Context) error {
+	if w.cancelFn != nil {
+		w.cancelFn()
+	}
+	return nil
+}
+
+func gatherPKIList(ctx context.Context, kubeClient kubernetes.Interface) (*certgraphapi.PKIList, error) {
+	return certgraphanalysis.GatherCertsFromPlatformNamespaces(ctx, kubeClient,
+		certgraphanalysis.SkipRevisioned,
+		certgraphanalysis.SkipHashed,
+		certgraphanalysis.ElideProxyCADetails,
+	)
+}
+
+func writePKIList(filename string, pkiList *certgraphapi.PKIList) error {
+	if pkiList == nil {
+		return nil
+	}
+	jsonContent, err := json.MarshalIndent(pkiList, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, jsonContent, 0644)
+}
@@ -0,0 +1,383 @@
+package watchcertificates
+
+import (
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/library-go/pkg/certs/cert-inspection/certgraphapi"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/cert"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// defaultExpiryWarningDays is how close to expiry a certificate has to get during the run to be reported unless
+// configured otherwise.  Certificates are normally rotated well before this.
+const defaultExpiryWarningDays = 7
+
+var wellKnownPlatformNamespaces = sets.New[string](
+	"openshift",
+	"default",
+	"kube-system",
+	"kube-public",
+	"kubernetes",
+)
+
+// isPlatformNamespace matches the namespaces certgraphanalysis.GatherCertsFromPlatformNamespaces collects from.
+func isPlatformNamespace(nsName string) bool {
+	if strings.HasPrefix(nsName, "openshift-") || strings.HasPrefix(nsName, "kubernetes-") {
+		return true
+	}
+	return wellKnownPlatformNamespaces.Has(nsName)
+}
+
+// certificate holds the parts of an x509 certificate needed to follow rotation.  certgraphapi does not keep
+// validity dates or key identifiers, so we parse the PEM ourselves.
+type certificate struct {
+	Subject        string
+	Issuer         string
+	SerialNumber   string
+	SubjectKeyID   string
+	AuthorityKeyID string
+	NotBefore      time.Time
+	NotAfter       time.Time
+	IsCA           bool
+	IsServing      bool
+}
+
+func toCertificate(c *x509.Certificate) certificate {
+	ret := certificate{
+		Subject:        c.Subject.String(),
+		Issuer:         c.Issuer.String(),
+		SerialNumber:   c.SerialNumber.String(),
+		SubjectKeyID:   hex.EncodeToString(c.SubjectKeyId),
+		AuthorityKeyID: hex.EncodeToString(c.AuthorityKeyId),
+		NotBefore:      c.NotBefore,
+		NotAfter:       c.NotAfter,
+		IsCA:           c.IsCA,
+	}
+	for _, usage := range c.ExtKeyUsage {
+		if usage == x509.ExtKeyUsageServerAuth {
+			ret.IsServing = !c.IsCA
+		}
+	}
+	return ret
+}
+
+// signerKey identifies the signer that issued the certificate.  Key identifiers are preferred because
+// regenerated signers frequently keep their subject.
+func (c certificate) signerKey() string {
+	if len(c.AuthorityKeyID) > 0 {
+		return "keyid:" + c.AuthorityKeyID
+	}
+	return "subject:" + c.Issuer
+}
+
+// signerKeys are the keys a certificate issued by this CA would have as its signerKey.
+func (c certificate) signerKeys() []string {
+	ret := []string{"subject:" + c.Subject}
+	if len(c.SubjectKeyID) > 0 {
+		ret = append(ret, "keyid:"+c.SubjectKeyID)
+	}
+	return ret
+}
+
+// servingCertState is the leaf certificate in a secret from a point in time onwards.
+type servingCertState struct {
+	at   time.Time
+	leaf certificate
+}
+
+// caBundleState is the content of a CA bundle from a point in time onwards.
+type caBundleState struct {
+	at   time.Time
+	keys sets.Set[string]
+}
+
+func (s caBundleState) trusts(leaf certificate) bool {
+	return s.keys.Has(leaf.signerKey())
+}
+
+type tracker struct {
+	lock sync.Mutex
+
+	// expiryWarningThreshold is how close to expiry a certificate has to get during the run to be reported.
+	expiryWarningThreshold time.Duration
+
+	secrets   map[certgraphapi.InClusterSecretLocation][]servingCertState
+	caBundles map[certgraphapi.InClusterConfigMapLocation][]caBundleState
+}
+
+func newTracker(expiryWarningThreshold time.Duration) *tracker {
+	return &tracker{
+		expiryWarningThreshold: expiryWarningThreshold,
+		secrets:                map[certgraphapi.InClusterSecretLocation][]servingCertState{},
+		caBundles:              map[certgraphapi.InClusterConfigMapLocation][]caBundleState{},
+	}
+}
+
+// observeSecret records the leaf certificate of a tls.crt secret if it changed.  Deletions are not recorded,
+// a recreated secret shows up as a rotation.
+func (t *tracker) observeSecret(at time.Time, secret *corev1.Secret) error {
+	if !isPlatformNamespace(secret.Namespace) {
+		return nil
+	}
+	tlsCrt, ok := secret.Data["tls.crt"]
+	if !ok || len(tlsCrt) == 0 {
+		return nil
+	}
+	certificates, err := cert.ParseCertsPEM(tlsCrt)
+	if err != nil {
+		return fmt.Errorf("secrets/%s[%s]: %w", secret.Name, secret.Namespace, err)
+	}
+	leaf := toCertificate(certificates[0])
+
+	location := certgraphapi.InClusterSecretLocation{Namespace: secret.Namespace, Name: secret.Name}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	history := t.secrets[location]
+	if len(history) > 0 && history[len(history)-1].leaf.SerialNumber == leaf.SerialNumber {
+		return nil
+	}
+	t.secrets[location] = append(history, servingCertState{at: at, leaf: leaf})
+	return nil
+}
+
+// observeConfigMap records the signers in a ca-bundle.crt configmap if they changed.
+func (t *tracker) observeConfigMap(at time.Time, configMap *corev1.ConfigMap) error {
+	if !isPlatformNamespace(configMap.Namespace) {
+		return nil
+	}
+	caBundle, ok := configMap.Data["ca-bundle.crt"]
+	if !ok || len(caBundle) == 0 {
+		return nil
+	}
+	certificates, err := cert.ParseCertsPEM([]byte(caBundle))
+	if err != nil {
+		return fmt.Errorf("configmaps/%s[%s]: %w", configMap.Name, configMap.Namespace, err)
+	}
+	state := caBundleState{at: at, keys: sets.New[string]()}
+	for _, c := range certificates {
+		state.keys.Insert(toCertificate(c).signerKeys()...)
+	}
+
+	location := certgraphapi.InClusterConfigMapLocation{Namespace: configMap.Namespace, Name: configMap.Name}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	history := t.caBundles[location]
+	if len(history) > 0 && history[len(history)-1].keys.Equal(state.keys) {
+		return nil
+	}
+	t.caBundles[location] = append(history, state)
+	return nil
+}
+
+// trustGap is a period during which a CA bundle that trusted a serving certificate did not contain the signer
+// of its current certificate.
+type trustGap struct {
+	secret   certgraphapi.InClusterSecretLocation
+	caBundle certgraphapi.InClusterConfigMapLocation
+	from, to time.Time
+	// causedByRotation is true when the gap started because the serving certificate was rotated to a signer
+	// the bundle did not have yet, rather than the bundle dropping a signer.
+	causedByRotation bool
+	signer           string
+}
+
+func (t *tracker) computeIntervals(beginning, end time.Time) monitorapi.Intervals {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	ret := monitorapi.Intervals{}
+	ret = append(ret, t.rotationIntervals()...)
+	ret = append(ret, trustGapIntervals(t.trustGaps(end))...)
+	ret = append(ret, t.expiryIntervals(beginning, end)...)
+	sort.Sort(ret)
+	return ret
+}
+
+func (t *tracker) rotationIntervals() monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	for location, history := range t.secrets {
+		for i := 1; i < len(history); i++ {
+			previous, current := history[i-1].leaf, history[i].leaf
+			signerChange := "same signer"
+			if previous.signerKey() != current.signerKey() {
+				signerChange = fmt.Sprintf("signer changed from %q to %q", previous.Issuer, current.Issuer)
+			}
+			ret = append(ret, monitorapi.NewInterval(monitorapi.SourceCertificateMonitor, monitorapi.Info).
+				Locator(monitorapi.NewLocator().Secret(location.Namespace, location.Name)).
+				Message(monitorapi.NewMessage().
+					Reason(monitorapi.CertificateRotatedReason).
+					HumanMessagef("certificate %q rotated from serial %s to %s, %s", current.Subject, previous.SerialNumber, current.SerialNumber, signerChange)).
+				Display().
+				Build(history[i].at, history[i].at))
+		}
+	}
+	return ret
+}
+
+// trustGaps finds, for every serving certificate, the CA bundles that trusted it at some point during the run
+// and the periods those bundles did not contain the signer of the current certificate.
+func (t *tracker) trustGaps(end time.Time) []trustGap {
+	ret := []trustGap{}
+	for secretLocation, secretHistory := range t.secrets {
+		if !secretHistory[len(secretHistory)-1].leaf.IsServing {
+			continue
+		}
+		usedSigners := sets.New[string]()
+		for _, state := range secretHistory {
+			usedSigners.Insert(state.leaf.signerKey())
+		}
+
+		for caBundleLocation, caBundleHistory := range t.caBundles {
+			related := false
+			for _, state := range caBundleHistory {
+				if state.keys.HasAny(usedSigners.UnsortedList()...) {
+					related = true
+					break
+				}
+			}
+			if !related {
+				continue
+			}
+			ret = append(ret, findTrustGaps(secretLocation, secretHistory, caBundleLocation, caBundleHistory, end)...)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if !ret[i].from.Equal(ret[j].from) {
+			return ret[i].from.Before(ret[j].from)
+		}
+		return fmt.Sprintf("%v%v", ret[i].secret, ret[i].caBundle) < fmt.Sprintf("%v%v", ret[j].secret, ret[j].caBundle)
+	})
+	return ret
+}
+
+// findTrustGaps walks the changes to a secret and a CA bundle in time order.  Only the period during which both
+// were observed is considered.
+func findTrustGaps(
+	secretLocation certgraphapi.InClusterSecretLocation, secretHistory []servingCertState,
+	caBundleLocation certgraphapi.InClusterConfigMapLocation, caBundleHistory []caBundleState,
+	end time.Time,
+) []trustGap {
+	start := secretHistory[0].at
+	if caBundleHistory[0].at.After(start) {
+		start = caBundleHistory[0].at
+	}
+	changes := []time.Time{start}
+	rotations := sets.New[time.Time]()
+	for i, state := range secretHistory {
+		if state.at.After(start) {
+			changes = append(changes, state.at)
+		}
+		if i > 0 {
+			rotations.Insert(state.at)
+		}
+	}
+	for _, state := range caBundleHistory {
+		if state.at.After(start) {
+			changes = append(changes, state.at)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Before(changes[j]) })
+
+	ret := []trustGap{}
+	var current *trustGap
+	for _, at := range changes {
+		leaf := secretHistory[0].leaf
+		for _, state := range secretHistory {
+			if state.at.After(at) {
+				break
+			}
+			leaf = state.leaf
+		}
+		caBundle := caBundleHistory[0]
+		for _, state := range caBundleHistory {
+			if state.at.After(at) {
+				break
+			}
+			caBundle = state
+		}
+
+		trusted := caBundle.trusts(leaf)
+		switch {
+		case !trusted && current == nil:
+			current = &trustGap{
+				secret:           secretLocation,
+				caBundle:         caBundleLocation,
+				from:             at,
+				causedByRotation: rotations.Has(at),
+				signer:           leaf.Issuer,
+			}
+		case trusted && current != nil:
+			current.to = at
+			ret = append(ret, *current)
+			current = nil
+		}
+	}
+	if current != nil {
+		current.to = end
+		ret = append(ret, *current)
+	}
+	return ret
+}
+
+func trustGapIntervals(gaps []trustGap) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	for _, gap := range gaps {
+		level := monitorapi.Warning
+		cause := "the CA bundle dropped the signer"
+		if gap.causedByRotation {
+			level = monitorapi.Error
+			cause = "the serving certificate was rotated before its signer was distributed"
+		}
+		ret = append(ret, monitorapi.NewInterval(monitorapi.SourceCertificateMonitor, level).
+			Locator(monitorapi.NewLocator().ConfigMap(gap.caBundle.Namespace, gap.caBundle.Name)).
+			Message(monitorapi.NewMessage().
+				Reason(monitorapi.CABundleMissingSignerReason).
+				HumanMessagef("CA bundle does not contain signer %q of the serving certificate in secrets/%s[%s]: %s", gap.signer, gap.secret.Name, gap.secret.Namespace, cause)).
+			Display().
+			Build(gap.from, gap.to))
+	}
+	return ret
+}
+
+// expiryIntervals covers the part of the run each certificate spent within expiryWarningThreshold of expiring.
+// CA bundles are skipped, they legitimately keep old signers until they expire.
+func (t *tracker) expiryIntervals(beginning, end time.Time) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	nearExpiry := func(locator monitorapi.Locator, c certificate) {
+		from := c.NotAfter.Add(-t.expiryWarningThreshold)
+		if from.After(end) {
+			return
+		}
+		if from.Before(beginning) {
+			from = beginning
+		}
+		to := c.NotAfter
+		if to.After(end) {
+			to = end
+		}
+		if to.Before(from) {
+			to = from
+		}
+		ret = append(ret, monitorapi.NewInterval(monitorapi.SourceCertificateMonitor, monitorapi.Warning).
+			Locator(locator).
+			Message(monitorapi.NewMessage().
+				Reason(monitorapi.CertificateNearExpiryReason).
+				HumanMessagef("certificate %q expires at %s", c.Subject, c.NotAfter.UTC().Format(time.RFC3339))).
+			Display().
+			Build(from, to))
+	}
+
+	for location, history := range t.secrets {
+		nearExpiry(monitorapi.NewLocator().Secret(location.Namespace, location.Name), history[len(history)-1].leaf)
+	}
+	return ret
+}
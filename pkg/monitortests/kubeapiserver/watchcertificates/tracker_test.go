@@ -0,0 +1,209 @@
+package watchcertificates
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+var serial int64
+
+func newTestCert(t *testing.T, commonName string, signer *testCert, isCA bool, notAfter time.Time) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serial++
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+	}
+	if isCA {
+		template.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	}
+	parent, parentKey := template, key
+	if signer != nil {
+		parent, parentKey = signer.cert, signer.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	c, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCert{cert: c, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+func secretWith(namespace, name string, c *testCert) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Data:       map[string][]byte{"tls.crt": c.pem},
+	}
+}
+
+func configMapWith(namespace, name string, signers ...*testCert) *corev1.ConfigMap {
+	bundle := ""
+	for _, signer := range signers {
+		bundle += string(signer.pem)
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Data:       map[string]string{"ca-bundle.crt": bundle},
+	}
+}
+
+func intervalsWithReason(intervals monitorapi.Intervals, reason monitorapi.IntervalReason) monitorapi.Intervals {
+	return intervals.Filter(func(interval monitorapi.Interval) bool {
+		return interval.Message.Reason == reason
+	})
+}
+
+func TestTracker(t *testing.T) {
+	beginning := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := beginning.Add(time.Hour)
+	farFuture := beginning.Add(365 * 24 * time.Hour)
+
+	oldSigner := newTestCert(t, "old-signer", nil, true, farFuture)
+	newSigner := newTestCert(t, "new-signer", nil, true, farFuture)
+	oldServing := newTestCert(t, "serving", oldSigner, false, farFuture)
+	newServing := newTestCert(t, "serving", newSigner, false, farFuture)
+
+	tests := []struct {
+		name             string
+		observe          func(tr *tracker)
+		expectRotations  int
+		expectGapLevels  []monitorapi.IntervalLevel
+		expectNearExpiry int
+	}{
+		{
+			name: "signer distributed before rotation",
+			observe: func(tr *tracker) {
+				require.NoError(t, tr.observeSecret(beginning, secretWith("openshift-foo", "serving-cert", oldServing)))
+				require.NoError(t, tr.observeConfigMap(beginning, configMapWith("openshift-foo", "ca", oldSigner)))
+				require.NoError(t, tr.observeConfigMap(beginning.Add(time.Minute), configMapWith("openshift-foo", "ca", oldSigner, newSigner)))
+				require.NoError(t, tr.observeSecret(beginning.Add(2*time.Minute), secretWith("openshift-foo", "serving-cert", newServing)))
+			},
+			expectRotations: 1,
+		},
+		{
+			name: "rotated before signer distributed",
+			observe: func(tr *tracker) {
+				require.NoError(t, tr.observeSecret(beginning, secretWith("openshift-foo", "serving-cert", oldServing)))
+				require.NoError(t, tr.observeConfigMap(beginning, configMapWith("openshift-foo", "ca", oldSigner)))
+				require.NoError(t, tr.observeSecret(beginning.Add(time.Minute), secretWith("openshift-foo", "serving-cert", newServing)))
+				require.NoError(t, tr.observeConfigMap(beginning.Add(2*time.Minute), configMapWith("openshift-foo", "ca", oldSigner, newSigner)))
+			},
+			expectRotations: 1,
+			expectGapLevels: []monitorapi.IntervalLevel{monitorapi.Error},
+		},
+		{
+			name: "bundle drops the signer",
+			observe: func(tr *tracker) {
+				require.NoError(t, tr.observeSecret(beginning, secretWith("openshift-foo", "serving-cert", oldServing)))
+				require.NoError(t, tr.observeConfigMap(beginning, configMapWith("openshift-foo", "ca", oldSigner)))
+				require.NoError(t, tr.observeConfigMap(beginning.Add(time.Minute), configMapWith("openshift-foo", "ca", newSigner)))
+			},
+			expectGapLevels: []monitorapi.IntervalLevel{monitorapi.Warning},
+		},
+		{
+			name: "unrelated bundle is ignored",
+			observe: func(tr *tracker) {
+				require.NoError(t, tr.observeSecret(beginning, secretWith("openshift-foo", "serving-cert", oldServing)))
+				require.NoError(t, tr.observeConfigMap(beginning, configMapWith("openshift-foo", "ca", newSigner)))
+			},
+		},
+		{
+			name: "non-platform namespaces are ignored",
+			observe: func(tr *tracker) {
+				require.NoError(t, tr.observeSecret(beginning, secretWith("e2e-test", "serving-cert", oldServing)))
+				require.NoError(t, tr.observeSecret(beginning.Add(time.Minute), secretWith("e2e-test", "serving-cert", newServing)))
+			},
+		},
+		{
+			name: "certificate near expiry",
+			observe: func(tr *tracker) {
+				expiring := newTestCert(t, "expiring", oldSigner, false, end.Add(24*time.Hour))
+				require.NoError(t, tr.observeSecret(beginning, secretWith("openshift-foo", "serving-cert", expiring)))
+			},
+			expectNearExpiry: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := newTracker(defaultExpiryWarningDays * 24 * time.Hour)
+			tt.observe(tr)
+			intervals := tr.computeIntervals(beginning, end)
+
+			assert.Len(t, intervalsWithReason(intervals, monitorapi.CertificateRotatedReason), tt.expectRotations)
+			assert.Len(t, intervalsWithReason(intervals, monitorapi.CertificateNearExpiryReason), tt.expectNearExpiry)
+			gapLevels := []monitorapi.IntervalLevel{}
+			for _, gap := range intervalsWithReason(intervals, monitorapi.CABundleMissingSignerReason) {
+				gapLevels = append(gapLevels, gap.Level)
+			}
+			if len(tt.expectGapLevels) == 0 {
+				assert.Empty(t, gapLevels)
+				return
+			}
+			assert.Equal(t, tt.expectGapLevels, gapLevels)
+		})
+	}
+}
+
+func TestFindTrustGapEndsWhenSignerArrives(t *testing.T) {
+	beginning := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	farFuture := beginning.Add(365 * 24 * time.Hour)
+	oldSigner := newTestCert(t, "old-signer", nil, true, farFuture)
+	newSigner := newTestCert(t, "new-signer", nil, true, farFuture)
+
+	tr := newTracker(defaultExpiryWarningDays * 24 * time.Hour)
+	require.NoError(t, tr.observeSecret(beginning, secretWith("openshift-foo", "serving-cert", newTestCert(t, "serving", oldSigner, false, farFuture))))
+	require.NoError(t, tr.observeConfigMap(beginning, configMapWith("openshift-foo", "ca", oldSigner)))
+	require.NoError(t, tr.observeSecret(beginning.Add(time.Minute), secretWith("openshift-foo", "serving-cert", newTestCert(t, "serving", newSigner, false, farFuture))))
+	require.NoError(t, tr.observeConfigMap(beginning.Add(3*time.Minute), configMapWith("openshift-foo", "ca", newSigner)))
+
+	gaps := tr.trustGaps(beginning.Add(time.Hour))
+	require.Len(t, gaps, 1)
+	assert.True(t, gaps[0].causedByRotation)
+	assert.Equal(t, beginning.Add(time.Minute), gaps[0].from)
+	assert.Equal(t, beginning.Add(3*time.Minute), gaps[0].to)
+}
+
+func TestExpiryWarningThreshold(t *testing.T) {
+	beginning := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := beginning.Add(3 * time.Hour)
+	signer := newTestCert(t, "signer", nil, true, beginning.Add(365*24*time.Hour))
+	expiring := newTestCert(t, "expiring", signer, false, end.Add(2*24*time.Hour))
+
+	tr := newTracker(24 * time.Hour)
+	require.NoError(t, tr.observeSecret(beginning, secretWith("openshift-foo", "serving-cert", expiring)))
+	assert.Empty(t, tr.expiryIntervals(beginning, end))
+
+	tr = newTracker(3 * 24 * time.Hour)
+	require.NoError(t, tr.observeSecret(beginning, secretWith("openshift-foo", "serving-cert", expiring)))
+	intervals := tr.expiryIntervals(beginning, end)
+	require.Len(t, intervals, 1)
+	assert.Equal(t, beginning, intervals[0].From)
+	assert.Equal(t, end, intervals[0].To)
+}
@@ -78,18 +78,41 @@ func init() {
 
 type podNetworkAvalibility struct {
 	payloadImagePullSpec string
-	notSupportedReason   error
-	namespaceName        string
-	targetService        *corev1.Service
-	kubeClient           kubernetes.Interface
+	// nodeMatrix runs the pollers and targets as daemonsets and records the disruption between each pair of nodes.
+	nodeMatrix         bool
+	notSupportedReason error
+	namespaceName      string
+	targetService      *corev1.Service
+	kubeClient         kubernetes.Interface
+	// nodeZones is keyed by node name.
+	nodeZones          map[string]string
+	nodePairDisruption *NodePairDisruption
 }
 
 func NewPodNetworkAvalibilityInvariant(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTest {
 	return &podNetworkAvalibility{
 		payloadImagePullSpec: info.UpgradeTargetPayloadImagePullSpec,
+		nodeMatrix:           info.PodNetworkNodeMatrix,
 	}
 }
 
+// createPollerOrTarget creates the deployment, or a daemonset with its pods in node matrix mode.  Node matrix pollers
+// sample the targets on a subset of the other nodes.
+func (pna *podNetworkAvalibility) createPollerOrTarget(ctx context.Context, deployment *appsv1.Deployment, poller bool) error {
+	if !pna.nodeMatrix {
+		_, err := pna.kubeClient.AppsV1().Deployments(pna.namespaceName).Create(ctx, deployment, metav1.CreateOptions{})
+		return err
+	}
+
+	daemonSet := daemonSetForDeployment(deployment)
+	if poller {
+		container := &daemonSet.Spec.Template.Spec.Containers[0]
+		container.Command = append(container.Command, "--node-matrix", fmt.Sprintf("--max-target-nodes=%d", maxNodeMatrixTargets))
+	}
+	_, err := pna.kubeClient.AppsV1().DaemonSets(pna.namespaceName).Create(ctx, daemonSet, metav1.CreateOptions{})
+	return err
+}
+
 func updateDeploymentENVs(deployment *appsv1.Deployment, deploymentID, serviceClusterIP string) *appsv1.Deployment {
 	for i, env := range deployment.Spec.Template.Spec.Containers[0].Env {
 		if env.Name == "DEPLOYMENT_ID" {
@@ -136,25 +159,25 @@ func (pna *podNetworkAvalibility) StartCollection(ctx context.Context, adminREST
 	podNetworkToPodNetworkPollerDeployment.Spec.Replicas = &numNodes
 	podNetworkToPodNetworkPollerDeployment.Spec.Template.Spec.Containers[0].Image = openshiftTestsImagePullSpec
 	podNetworkToPodNetworkPollerDeployment = updateDeploymentENVs(podNetworkToPodNetworkPollerDeployment, deploymentID, "")
-	if _, err = pna.kubeClient.AppsV1().Deployments(pna.namespaceName).Create(context.Background(), podNetworkToPodNetworkPollerDeployment, metav1.CreateOptions{}); err != nil {
+	if err := pna.createPollerOrTarget(context.Background(), podNetworkToPodNetworkPollerDeployment, true); err != nil {
 		return err
 	}
 	podNetworkToHostNetworkPollerDeployment.Spec.Replicas = &numNodes
 	podNetworkToHostNetworkPollerDeployment.Spec.Template.Spec.Containers[0].Image = openshiftTestsImagePullSpec
 	podNetworkToHostNetworkPollerDeployment = updateDeploymentENVs(podNetworkToHostNetworkPollerDeployment, deploymentID, "")
-	if _, err = pna.kubeClient.AppsV1().Deployments(pna.namespaceName).Create(context.Background(), podNetworkToHostNetworkPollerDeployment, metav1.CreateOptions{}); err != nil {
+	if err := pna.createPollerOrTarget(context.Background(), podNetworkToHostNetworkPollerDeployment, true); err != nil {
 		return err
 	}
 	hostNetworkToPodNetworkPollerDeployment.Spec.Replicas = &numNodes
 	hostNetworkToPodNetworkPollerDeployment.Spec.Template.Spec.Containers[0].Image = openshiftTestsImagePullSpec
 	hostNetworkToPodNetworkPollerDeployment = updateDeploymentENVs(hostNetworkToPodNetworkPollerDeployment, deploymentID, "")
-	if _, err = pna.kubeClient.AppsV1().Deployments(pna.namespaceName).Create(context.Background(), hostNetworkToPodNetworkPollerDeployment, metav1.CreateOptions{}); err != nil {
+	if err := pna.createPollerOrTarget(context.Background(), hostNetworkToPodNetworkPollerDeployment, true); err != nil {
 		return err
 	}
 	hostNetworkToHostNetworkPollerDeployment.Spec.Replicas = &numNodes
 	hostNetworkToHostNetworkPollerDeployment.Spec.Template.Spec.Containers[0].Image = openshiftTestsImagePullSpec
 	hostNetworkToHostNetworkPollerDeployment = updateDeploymentENVs(hostNetworkToHostNetworkPollerDeployment, deploymentID, "")
-	if _, err = pna.kubeClient.AppsV1().Deployments(pna.namespaceName).Create(context.Background(), hostNetworkToHostNetworkPollerDeployment, metav1.CreateOptions{}); err != nil {
+	if err := pna.createPollerOrTarget(context.Background(), hostNetworkToHostNetworkPollerDeployment, true); err != nil {
 		return err
 	}
 
@@ -162,7 +185,7 @@ func (pna *podNetworkAvalibility) StartCollection(ctx context.Context, adminREST
 	originalAgnhost := k8simage.GetOriginalImageConfigs()[k8simage.Agnhost]
 	podNetworkTargetDeployment.Spec.Replicas = &numNodes
 	podNetworkTargetDeployment.Spec.Template.Spec.Containers[0].Image = image.LocationFor(originalAgnhost.GetE2EImage())
-	if _, err := pna.kubeClient.AppsV1().Deployments(pna.namespaceName).Create(context.Background(), podNetworkTargetDeployment, metav1.CreateOptions{}); err != nil {
+	if err := pna.createPollerOrTarget(context.Background(), podNetworkTargetDeployment, false); err != nil {
 		return err
 	}
 	service, err := pna.kubeClient.CoreV1().Services(pna.namespaceName).Create(context.Background(), podNetworkTargetService, metav1.CreateOptions{})
@@ -173,7 +196,7 @@ func (pna *podNetworkAvalibility) StartCollection(ctx context.Context, adminREST
 
 	hostNetworkTargetDeployment.Spec.Replicas = &numNodes
 	hostNetworkTargetDeployment.Spec.Template.Spec.Containers[0].Image = image.LimitedShellImage()
-	if _, err := pna.kubeClient.AppsV1().Deployments(pna.namespaceName).Create(context.Background(), hostNetworkTargetDeployment, metav1.CreateOptions{}); err != nil {
+	if err := pna.createPollerOrTarget(context.Background(), hostNetworkTargetDeployment, false); err != nil {
 		return err
 	}
 	if _, err := pna.kubeClient.CoreV1().Services(pna.namespaceName).Create(context.Background(), hostNetworkTargetService, metav1.CreateOptions{}); err != nil {
@@ -242,6 +265,17 @@ func (pna *podNetworkAvalibility) CollectData(ctx context.Context, storageDir st
 		return nil, nil, ctx.Err()
 	}
 
+	if pna.nodeMatrix {
+		nodes, err := pna.kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		pna.nodeZones = map[string]string{}
+		for _, node := range nodes.Items {
+			pna.nodeZones[node.Name] = node.Labels[corev1.LabelTopologyZone]
+		}
+	}
+
 	retIntervals := monitorapi.Intervals{}
 	junits := []*junitapi.JUnitTestCase{}
 	errs := []error{}
@@ -338,11 +372,23 @@ func (pna *podNetworkAvalibility) ConstructComputedIntervals(ctx context.Context
 }
 
 func (pna *podNetworkAvalibility) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, pna.notSupportedReason
+	if pna.notSupportedReason != nil || !pna.nodeMatrix {
+		return nil, pna.notSupportedReason
+	}
+
+	pna.nodePairDisruption = computeNodePairDisruption(finalIntervals, pna.nodeZones)
+	return pna.nodePairDisruption.junits(), nil
 }
 
 func (pna *podNetworkAvalibility) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return pna.notSupportedReason
+	if pna.notSupportedReason != nil || !pna.nodeMatrix {
+		return pna.notSupportedReason
+	}
+
+	if pna.nodePairDisruption == nil {
+		pna.nodePairDisruption = computeNodePairDisruption(finalIntervals, pna.nodeZones)
+	}
+	return writeNodePairDisruption(storageDir, timeSuffix, pna.nodePairDisruption)
 }
 
 func (pna *podNetworkAvalibility) namespaceDeleted(ctx context.Context) (bool, error) {
@@ -0,0 +1,377 @@
+package disruptionpodnetwork
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	// maxNodeMatrixTargets is the number of other nodes each poller samples in node matrix mode.
+	maxNodeMatrixTargets = 10
+
+	// nodePairDisruptedThreshold is how long a pair must be disrupted for to count towards a partition.
+	nodePairDisruptedThreshold = 5 * time.Second
+	// partitionedShare is the share of a node's pairs, in both directions, that must be disrupted for it to be
+	// partitioned, while at most 1-partitionedShare of the pairs without it are.
+	partitionedShare = 0.75
+	// asymmetricRatio is how many times longer one direction of a pair must be disrupted than the other to be
+	// asymmetric.
+	asymmetricRatio = 10
+
+	singleNodePartitionTestName = "[sig-network] pod network disruption should not partition a single node"
+	asymmetricPartitionTestName = "[sig-network] pod network disruption should be symmetric between nodes"
+)
+
+//go:embed node-pair-disruption.html
+var nodePairDisruptionHTMLTemplate string
+
+// daemonSetForDeployment runs the pods of the deployment on every node.
+func daemonSetForDeployment(deployment *appsv1.Deployment) *appsv1.DaemonSet {
+	template := deployment.Spec.Template.DeepCopy()
+	// the anti-affinity spreading the deployment is implied
+	template.Spec.Affinity = nil
+	maxUnavailable := intstr.FromString("34%")
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   deployment.Name,
+			Labels: deployment.Labels,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: deployment.Spec.Selector.DeepCopy(),
+			Template: *template,
+			UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
+				Type: appsv1.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDaemonSet{
+					MaxUnavailable: &maxUnavailable,
+				},
+			},
+		},
+	}
+}
+
+// NodePairDisruption is the disruption between every pair of nodes sampled by the node matrix pollers, over all
+// types of connections between them.
+type NodePairDisruption struct {
+	// Nodes are ordered by zone, then name.
+	Nodes []string          `json:"nodes"`
+	Zones map[string]string `json:"zones,omitempty"`
+	// Seconds is keyed by source, then target node.  Pairs that were sampled without disruption are zero, pairs that
+	// were not sampled are missing.
+	Seconds map[string]map[string]float64 `json:"seconds"`
+}
+
+// unavailableNodes returns when each node was not ready or rebooting, from the node state intervals.
+func unavailableNodes(intervals monitorapi.Intervals) map[string]monitorapi.Intervals {
+	ret := map[string]monitorapi.Intervals{}
+	for _, interval := range intervals {
+		if interval.Source != monitorapi.SourceNodeState {
+			continue
+		}
+		notReady := interval.Message.Reason == monitorapi.NodeNotReadyReason
+		rebooting := interval.Message.Reason == monitorapi.NodeUpdateReason && interval.Message.Annotations[monitorapi.AnnotationPhase] == "Reboot"
+		if !notReady && !rebooting {
+			continue
+		}
+		node := interval.Locator.Keys[monitorapi.LocatorNodeKey]
+		ret[node] = append(ret[node], interval)
+	}
+	return ret
+}
+
+func overlapsAny(interval monitorapi.Interval, others monitorapi.Intervals) bool {
+	for _, other := range others {
+		if interval.From.Before(other.To) && other.From.Before(interval.To) {
+			return true
+		}
+	}
+	return false
+}
+
+// computeNodePairDisruption sums the disruption of each pair of nodes.  Overlapping disruption of different types of
+// connections is only counted once.  Zones come from the nodes when known, and from the locators otherwise.
+// Disruption while either node was not ready or rebooting is dropped: the targets are daemonset pods that a drain does
+// not evict, so the other nodes keep reaching for a rebooting node while its own poller is down, and every reboot would
+// look like a one way partition.
+func computeNodePairDisruption(intervals monitorapi.Intervals, nodeZones map[string]string) *NodePairDisruption {
+	unavailable := unavailableNodes(intervals)
+	zones := map[string]string{}
+	disruptionByPair := map[string]map[string]monitorapi.Intervals{}
+	for _, interval := range intervals {
+		if interval.Source != monitorapi.SourceDisruption {
+			continue
+		}
+		source := interval.Locator.Keys[monitorapi.LocatorSourceNodeKey]
+		target := interval.Locator.Keys[monitorapi.LocatorTargetNodeKey]
+		if len(source) == 0 || len(target) == 0 {
+			continue
+		}
+		if zone := interval.Locator.Keys[monitorapi.LocatorSourceZoneKey]; len(zone) > 0 {
+			zones[source] = zone
+		}
+		if zone := interval.Locator.Keys[monitorapi.LocatorTargetZoneKey]; len(zone) > 0 {
+			zones[target] = zone
+		}
+		if _, ok := disruptionByPair[source]; !ok {
+			disruptionByPair[source] = map[string]monitorapi.Intervals{}
+		}
+		disruption := disruptionByPair[source][target]
+		if interval.Level == monitorapi.Error && !overlapsAny(interval, unavailable[source]) && !overlapsAny(interval, unavailable[target]) {
+			disruption = append(disruption, interval)
+		}
+		disruptionByPair[source][target] = disruption
+	}
+	for node, zone := range nodeZones {
+		if len(zone) > 0 {
+			zones[node] = zone
+		}
+	}
+
+	ret := &NodePairDisruption{
+		Zones:   map[string]string{},
+		Seconds: map[string]map[string]float64{},
+	}
+	nodes := sets.New[string]()
+	for source, targets := range disruptionByPair {
+		ret.Seconds[source] = map[string]float64{}
+		nodes.Insert(source)
+		for target, disruption := range targets {
+			ret.Seconds[source][target] = mergedDuration(disruption).Seconds()
+			nodes.Insert(target)
+		}
+	}
+	for node := range nodes {
+		if zone, ok := zones[node]; ok {
+			ret.Zones[node] = zone
+		}
+	}
+	ret.Nodes = sets.List(nodes)
+	sort.SliceStable(ret.Nodes, func(i, j int) bool { return ret.Zones[ret.Nodes[i]] < ret.Zones[ret.Nodes[j]] })
+	return ret
+}
+
+// mergedDuration is the time covered by at least one of the intervals.
+func mergedDuration(intervals monitorapi.Intervals) time.Duration {
+	sorted := append(monitorapi.Intervals{}, intervals...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].From.Before(sorted[j].From) })
+
+	var total time.Duration
+	var currFrom, currTo time.Time
+	for _, interval := range sorted {
+		switch {
+		case !interval.To.After(interval.From):
+			continue
+		case currTo.IsZero() || interval.From.After(currTo):
+			total += currTo.Sub(currFrom)
+			currFrom, currTo = interval.From, interval.To
+		case interval.To.After(currTo):
+			currTo = interval.To
+		}
+	}
+	return total + currTo.Sub(currFrom)
+}
+
+func (d *NodePairDisruption) sampled(source, target string) (float64, bool) {
+	seconds, ok := d.Seconds[source][target]
+	return seconds, ok
+}
+
+func (d *NodePairDisruption) disrupted(source, target string) bool {
+	seconds, _ := d.sampled(source, target)
+	return seconds >= nodePairDisruptedThreshold.Seconds()
+}
+
+// singleNodePartitions returns the nodes that most of their peers could not reach, and that could not reach most of
+// their peers, while the rest of the cluster could reach each other.
+func (d *NodePairDisruption) singleNodePartitions() []string {
+	if len(d.Nodes) < 3 {
+		return nil
+	}
+
+	var partitioned []string
+	for _, node := range d.Nodes {
+		var incoming, incomingDisrupted, outgoing, outgoingDisrupted, others, othersDisrupted int
+		for source, targets := range d.Seconds {
+			for target := range targets {
+				switch {
+				case target == node:
+					incoming++
+					if d.disrupted(source, target) {
+						incomingDisrupted++
+					}
+				case source == node:
+					outgoing++
+					if d.disrupted(source, target) {
+						outgoingDisrupted++
+					}
+				default:
+					others++
+					if d.disrupted(source, target) {
+						othersDisrupted++
+					}
+				}
+			}
+		}
+		if incoming < 2 || outgoing < 2 {
+			continue
+		}
+		if float64(incomingDisrupted) >= partitionedShare*float64(incoming) &&
+			float64(outgoingDisrupted) >= partitionedShare*float64(outgoing) &&
+			float64(othersDisrupted) <= (1-partitionedShare)*float64(others) {
+			partitioned = append(partitioned, node)
+		}
+	}
+	return partitioned
+}
+
+// asymmetricPairs returns the pairs that were disrupted in one direction, but not in the other.
+func (d *NodePairDisruption) asymmetricPairs() []string {
+	var pairs []string
+	for _, source := range d.Nodes {
+		for _, target := range d.Nodes {
+			forward, ok := d.sampled(source, target)
+			if !ok || !d.disrupted(source, target) {
+				continue
+			}
+			reverse, ok := d.sampled(target, source)
+			if !ok || reverse*asymmetricRatio > forward {
+				continue
+			}
+			pairs = append(pairs, fmt.Sprintf("node/%s (%s) could not reach node/%s (%s) for %.0fs, but the reverse was disrupted for %.0fs",
+				source, d.zone(source), target, d.zone(target), forward, reverse))
+		}
+	}
+	return pairs
+}
+
+func (d *NodePairDisruption) zone(node string) string {
+	if zone, ok := d.Zones[node]; ok {
+		return "zone/" + zone
+	}
+	return "unknown zone"
+}
+
+func (d *NodePairDisruption) junits() []*junitapi.JUnitTestCase {
+	partitionJunit := &junitapi.JUnitTestCase{Name: singleNodePartitionTestName}
+	if partitioned := d.singleNodePartitions(); len(partitioned) > 0 {
+		var lines []string
+		for _, node := range partitioned {
+			lines = append(lines, fmt.Sprintf("node/%s (%s) was partitioned from most other nodes in both directions", node, d.zone(node)))
+		}
+		partitionJunit.FailureOutput = &junitapi.FailureOutput{
+			Output: fmt.Sprintf("%d nodes were partitioned while the rest of the cluster could reach each other, see node-pair-disruption*.html:\n%s",
+				len(partitioned), strings.Join(lines, "\n")),
+		}
+	}
+	junits := []*junitapi.JUnitTestCase{partitionJunit}
+	if partitionJunit.FailureOutput != nil {
+		// The share of disrupted pairs that makes a partition is a guess that has not been checked against real runs,
+		// so a partition only flakes until it is.
+		junits = append(junits, &junitapi.JUnitTestCase{Name: singleNodePartitionTestName})
+	}
+
+	asymmetricJunit := &junitapi.JUnitTestCase{Name: asymmetricPartitionTestName}
+	if pairs := d.asymmetricPairs(); len(pairs) > 0 {
+		asymmetricJunit.FailureOutput = &junitapi.FailureOutput{
+			Output: fmt.Sprintf("%d pairs of nodes were only disrupted in one direction, see node-pair-disruption*.html:\n%s",
+				len(pairs), strings.Join(pairs, "\n")),
+		}
+	}
+	junits = append(junits, asymmetricJunit)
+	if asymmetricJunit.FailureOutput != nil {
+		// A poller that stops while its peers keep sampling it, like on a node that is shut down without being marked
+		// not ready first, is asymmetric without a network problem, so this only flakes.
+		junits = append(junits, &junitapi.JUnitTestCase{Name: asymmetricPartitionTestName})
+	}
+	return junits
+}
+
+type heatmapCell struct {
+	Text  string
+	Title string
+	Style template.CSS
+}
+
+type heatmapRow struct {
+	Node  string
+	Zone  string
+	Cells []heatmapCell
+}
+
+// writeNodePairDisruption writes the pairs as json and as a heatmap of disruption seconds.
+func writeNodePairDisruption(storageDir, timeSuffix string, d *NodePairDisruption) error {
+	jsonContent, err := json.MarshalIndent(d, "", "    ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(storageDir, fmt.Sprintf("node-pair-disruption%s.json", timeSuffix)), jsonContent, 0644); err != nil {
+		return err
+	}
+
+	maxSeconds := 0.0
+	for _, targets := range d.Seconds {
+		for _, seconds := range targets {
+			maxSeconds = math.Max(maxSeconds, seconds)
+		}
+	}
+	var rows []heatmapRow
+	for _, source := range d.Nodes {
+		row := heatmapRow{Node: source, Zone: d.Zones[source]}
+		for _, target := range d.Nodes {
+			seconds, ok := d.sampled(source, target)
+			switch {
+			case source == target:
+				row.Cells = append(row.Cells, heatmapCell{Text: "-", Style: "background-color: #eee"})
+			case !ok:
+				row.Cells = append(row.Cells, heatmapCell{Title: "not sampled", Style: "background-color: #ccc"})
+			default:
+				alpha := 0.0
+				if maxSeconds > 0 {
+					alpha = seconds / maxSeconds
+				}
+				row.Cells = append(row.Cells, heatmapCell{
+					Text:  fmt.Sprintf("%.0f", seconds),
+					Title: fmt.Sprintf("%s to %s: %.0fs", source, target, seconds),
+					Style: template.CSS(fmt.Sprintf("background-color: rgba(220, 0, 0, %.2f)", alpha)),
+				})
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	tmpl, err := template.New("node-pair-disruption").Parse(nodePairDisruptionHTMLTemplate)
+	if err != nil {
+		return err
+	}
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, struct {
+		Title string
+		Nodes []string
+		Zones map[string]string
+		Rows  []heatmapRow
+	}{
+		Title: fmt.Sprintf("Node pair disruption%s", timeSuffix),
+		Nodes: d.Nodes,
+		Zones: d.Zones,
+		Rows:  rows,
+	}); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(storageDir, fmt.Sprintf("node-pair-disruption%s.html", timeSuffix)), buf.Bytes(), 0644)
+}
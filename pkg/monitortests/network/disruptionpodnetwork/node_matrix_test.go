@@ -0,0 +1,184 @@
+package disruptionpodnetwork
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func nodePairInterval(source, target string, level monitorapi.IntervalLevel, from time.Time, duration time.Duration) monitorapi.Interval {
+	return monitorapi.Interval{
+		Condition: monitorapi.Condition{
+			Level: level,
+			Locator: monitorapi.NewLocator().LocateNodePairDisruptionCheck("pod-to-pod-new-connections", "pod-to-pod-from-node-"+source,
+				source, "", target, "zone-"+target, monitorapi.NewConnectionType),
+		},
+		Source: monitorapi.SourceDisruption,
+		From:   from,
+		To:     from.Add(duration),
+	}
+}
+
+// matrixIntervals samples every pair of nodes, disrupting the pairs in disrupted for a minute.
+func matrixIntervals(nodes []string, disrupted map[[2]string]bool) monitorapi.Intervals {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var intervals monitorapi.Intervals
+	for _, source := range nodes {
+		for _, target := range nodes {
+			if source == target {
+				continue
+			}
+			intervals = append(intervals, nodePairInterval(source, target, monitorapi.Info, start, time.Hour))
+			if disrupted[[2]string{source, target}] {
+				intervals = append(intervals, nodePairInterval(source, target, monitorapi.Error, start.Add(time.Minute), time.Minute))
+			}
+		}
+	}
+	return intervals
+}
+
+func Test_computeNodePairDisruption(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	intervals := monitorapi.Intervals{
+		nodePairInterval("a", "b", monitorapi.Info, start, time.Hour),
+		// overlapping disruption of different connections is counted once
+		nodePairInterval("a", "b", monitorapi.Error, start, 10*time.Second),
+		nodePairInterval("a", "b", monitorapi.Error, start.Add(5*time.Second), 10*time.Second),
+		nodePairInterval("a", "b", monitorapi.Error, start.Add(time.Minute), 5*time.Second),
+		nodePairInterval("b", "a", monitorapi.Info, start, time.Hour),
+		{Source: monitorapi.SourceDisruption, Condition: monitorapi.Condition{Level: monitorapi.Error}, From: start, To: start.Add(time.Hour)},
+	}
+
+	got := computeNodePairDisruption(intervals, map[string]string{"b": "zone-0"})
+	want := &NodePairDisruption{
+		Nodes:   []string{"b", "a"},
+		Zones:   map[string]string{"a": "zone-a", "b": "zone-0"},
+		Seconds: map[string]map[string]float64{"a": {"b": 20}, "b": {"a": 0}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+}
+
+func TestNodePairDisruption_junits(t *testing.T) {
+	nodes := []string{"a", "b", "c", "d", "e"}
+	tests := []struct {
+		name            string
+		disrupted       map[[2]string]bool
+		wantPartitioned []string
+		wantAsymmetric  int
+	}{
+		{
+			name: "no disruption",
+		},
+		{
+			name: "single node partition",
+			disrupted: map[[2]string]bool{
+				{"a", "c"}: true, {"b", "c"}: true, {"d", "c"}: true, {"e", "c"}: true,
+				{"c", "a"}: true, {"c", "b"}: true, {"c", "d"}: true, {"c", "e"}: true,
+			},
+			wantPartitioned: []string{"c"},
+		},
+		{
+			name:           "asymmetric",
+			disrupted:      map[[2]string]bool{{"a", "b"}: true},
+			wantAsymmetric: 1,
+		},
+		{
+			name: "everything disrupted is not a single node partition",
+			disrupted: func() map[[2]string]bool {
+				ret := map[[2]string]bool{}
+				for _, source := range nodes {
+					for _, target := range nodes {
+						ret[[2]string{source, target}] = true
+					}
+				}
+				return ret
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := computeNodePairDisruption(matrixIntervals(nodes, tt.disrupted), nil)
+			if got := d.singleNodePartitions(); !reflect.DeepEqual(got, tt.wantPartitioned) {
+				t.Errorf("got partitioned nodes %v, want %v", got, tt.wantPartitioned)
+			}
+			if got := d.asymmetricPairs(); len(got) != tt.wantAsymmetric {
+				t.Errorf("got asymmetric pairs %v, want %d", got, tt.wantAsymmetric)
+			}
+
+			// failures only flake, every failing junit has a passing one with the same name
+			results := map[string][]bool{}
+			for _, junit := range d.junits() {
+				results[junit.Name] = append(results[junit.Name], junit.FailureOutput != nil)
+			}
+			wantResults := map[string][]bool{
+				singleNodePartitionTestName: {false},
+				asymmetricPartitionTestName: {false},
+			}
+			if len(tt.wantPartitioned) > 0 {
+				wantResults[singleNodePartitionTestName] = []bool{true, false}
+			}
+			if tt.wantAsymmetric > 0 {
+				wantResults[asymmetricPartitionTestName] = []bool{true, false}
+			}
+			if !reflect.DeepEqual(results, wantResults) {
+				t.Errorf("got junit failures %v, want %v", results, wantResults)
+			}
+		})
+	}
+}
+
+func Test_computeNodePairDisruptionDropsUnavailableNodes(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	nodes := []string{"a", "b", "c"}
+	// c reboots while a and b keep sampling it, c samples nothing while it is down
+	intervals := matrixIntervals(nodes, map[[2]string]bool{{"a", "c"}: true, {"b", "c"}: true})
+	intervals = append(intervals,
+		monitorapi.NewInterval(monitorapi.SourceNodeState, monitorapi.Info).
+			Locator(monitorapi.NewLocator().NodeFromName("c")).
+			Message(monitorapi.NewMessage().Reason(monitorapi.NodeUpdateReason).WithAnnotation(monitorapi.AnnotationPhase, "Reboot")).
+			Build(start.Add(50*time.Second), start.Add(90*time.Second)),
+		// a was not ready long after its disruption ended
+		monitorapi.NewInterval(monitorapi.SourceNodeState, monitorapi.Warning).
+			Locator(monitorapi.NewLocator().NodeFromName("a")).
+			Message(monitorapi.NewMessage().Reason(monitorapi.NodeNotReadyReason)).
+			Build(start.Add(time.Hour), start.Add(2*time.Hour)),
+	)
+
+	d := computeNodePairDisruption(intervals, nil)
+	if seconds, _ := d.sampled("a", "c"); seconds != 0 {
+		t.Errorf("expected the disruption during the reboot to be dropped, got %.0fs", seconds)
+	}
+	if pairs := d.asymmetricPairs(); len(pairs) != 0 {
+		t.Errorf("expected no asymmetric pairs, got %v", pairs)
+	}
+
+	d = computeNodePairDisruption(matrixIntervals(nodes, map[[2]string]bool{{"a", "c"}: true}), nil)
+	if seconds, _ := d.sampled("a", "c"); seconds != 60 {
+		t.Errorf("expected the disruption of available nodes to be kept, got %.0fs", seconds)
+	}
+}
+
+func Test_writeNodePairDisruption(t *testing.T) {
+	dir := t.TempDir()
+	d := computeNodePairDisruption(matrixIntervals([]string{"a", "b"}, map[[2]string]bool{{"a", "b"}: true}), nil)
+	if err := writeNodePairDisruption(dir, "_20240101-000000", d); err != nil {
+		t.Fatal(err)
+	}
+	html, err := os.ReadFile(filepath.Join(dir, "node-pair-disruption_20240101-000000.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(html), `title="a to b: 60s"`) {
+		t.Errorf("expected the heatmap to contain the disrupted pair:\n%s", html)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "node-pair-disruption_20240101-000000.json")); err != nil {
+		t.Error(err)
+	}
+}
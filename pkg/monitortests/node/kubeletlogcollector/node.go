@@ -4,8 +4,8 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	_ "embed"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"regexp"
 	"strconv"
@@ -17,9 +17,17 @@ import (
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/logrules"
 	"k8s.io/client-go/kubernetes"
 )
 
+// node_log_rules.yaml holds the rules for the journals of the systemd units that do not need custom parsing.
+//
+//go:embed node_log_rules.yaml
+var nodeLogRulesYAML []byte
+
+var nodeLogRules = logrules.MustRulesFromYAML(nodeLogRulesYAML)
+
 func intervalsFromNodeLogs(ctx context.Context, kubeClient kubernetes.Interface, beginning, end time.Time) (monitorapi.Intervals, error) {
 	ret := monitorapi.Intervals{}
 
@@ -38,7 +46,7 @@ func intervalsFromNodeLogs(ctx context.Context, kubeClient kubernetes.Interface,
 			defer wg.Done()
 
 			// TODO limit by begin/end here instead of post-processing
-			nodeLogs, err := getNodeLog(ctx, kubeClient, nodeName, "kubelet")
+			nodeLogs, err := logrules.GetNodeLog(ctx, kubeClient, nodeName, "kubelet")
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error getting node logs from %s: %s", nodeName, err.Error())
 				errCh <- err
@@ -46,7 +54,7 @@ func intervalsFromNodeLogs(ctx context.Context, kubeClient kubernetes.Interface,
 			}
 			newEvents := eventsFromKubeletLogs(nodeName, nodeLogs)
 
-			ovsVswitchdLogs, err := getNodeLog(ctx, kubeClient, nodeName, "ovs-vswitchd")
+			ovsVswitchdLogs, err := logrules.GetNodeLog(ctx, kubeClient, nodeName, "ovs-vswitchd")
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error getting node ovs-vswitchd logs from %s: %s", nodeName, err.Error())
 				errCh <- err
@@ -54,7 +62,7 @@ func intervalsFromNodeLogs(ctx context.Context, kubeClient kubernetes.Interface,
 			}
 			newOVSEvents := eventsFromOVSVswitchdLogs(nodeName, ovsVswitchdLogs)
 
-			networkManagerLogs, err := getNodeLog(ctx, kubeClient, nodeName, "NetworkManager")
+			networkManagerLogs, err := logrules.GetNodeLog(ctx, kubeClient, nodeName, "NetworkManager")
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error getting node NetworkManager logs from %s: %s", nodeName, err.Error())
 				errCh <- err
@@ -130,7 +138,7 @@ func unreasonablyLongPollInterval(logLine string, nodeLocator monitorapi.Locator
 		return nil
 	}
 
-	toTime := systemdJournalLogTime(logLine)
+	toTime := logrules.SystemdJournalLogTime(logLine)
 
 	// Extract the number of millis and use it for the interval, starting from the point we logged
 	// and looking backwards.
@@ -158,37 +166,8 @@ var unreasonablyLongPollIntervalRE = regexp.MustCompile(`Unreasonably long (\d+)
 
 // intervalsFromNetworkManagerLogs returns the produced intervals.  Any errors during this creation are logged, but
 // not returned because this is a best effort step
-func intervalsFromNetworkManagerLogs(nodeName string, ovsLogs []byte) monitorapi.Intervals {
-	locator := monitorapi.NewLocator().NodeFromName(nodeName)
-	ret := monitorapi.Intervals{}
-
-	scanner := bufio.NewScanner(bytes.NewBuffer(ovsLogs))
-	for scanner.Scan() {
-		currLine := scanner.Text()
-		ret = append(ret, tooManyNetlinkEvents(currLine, locator)...)
-	}
-
-	return ret
-}
-
-// tooManyNetlinkEvents searches for a failure associated with https://issues.redhat.com/browse/OCPBUGS-11591
-//
-// Apr 12 11:49:49.188086 ci-op-xs3rnrtc-2d4c7-4mhm7-worker-b-dwc7w NetworkManager[1155]:
-// <info> [1681300187.8326] platform-linux: netlink[rtnl]: read: too many netlink events.
-// Need to resynchronize platform cache
-func tooManyNetlinkEvents(logLine string, nodeLocator monitorapi.Locator) monitorapi.Intervals {
-	if !strings.Contains(logLine, "too many netlink events. Need to resynchronize platform cache") {
-		return nil
-	}
-
-	logTime := systemdJournalLogTime(logLine)
-
-	message := logLine[strings.Index(logLine, "NetworkManager"):]
-	return monitorapi.Intervals{
-		monitorapi.NewInterval(monitorapi.SourceNetworkManagerLog, monitorapi.Warning).Locator(
-			nodeLocator).Message(monitorapi.NewMessage().HumanMessage(message)).
-			Display().Build(logTime, logTime.Add(1*time.Second)),
-	}
+func intervalsFromNetworkManagerLogs(nodeName string, networkManagerLogs []byte) monitorapi.Intervals {
+	return logrules.IntervalsFromJournal(nodeLogRules, "NetworkManager", nodeName, networkManagerLogs)
 }
 
 type kubeletLogLineEventCreator func(logLine string) monitorapi.Intervals
@@ -216,7 +195,7 @@ func readinessFailure(nodeName, logLine string) monitorapi.Intervals {
 	}
 
 	containerRef := probeProblemToContainerReference(logLine)
-	failureTime := systemdJournalLogTime(logLine)
+	failureTime := logrules.SystemdJournalLogTime(logLine)
 	return monitorapi.Intervals{
 		monitorapi.NewInterval(monitorapi.SourceKubeletLog, monitorapi.Info).
 			Locator(containerRef).
@@ -242,7 +221,7 @@ func readinessError(nodeName, logLine string) monitorapi.Intervals {
 	message, _ = strconv.Unquote(`"` + message + `"`)
 
 	containerRef := probeProblemToContainerReference(logLine)
-	failureTime := systemdJournalLogTime(logLine)
+	failureTime := logrules.SystemdJournalLogTime(logLine)
 	return monitorapi.Intervals{
 		monitorapi.NewInterval(monitorapi.SourceKubeletLog, monitorapi.Info).
 			Locator(containerRef).
@@ -268,7 +247,7 @@ func errParsingSignature(nodeName, logLine string) monitorapi.Intervals {
 	}
 
 	containerRef := errImagePullToContainerReference(logLine)
-	failureTime := systemdJournalLogTime(logLine)
+	failureTime := logrules.SystemdJournalLogTime(logLine)
 	return monitorapi.Intervals{
 		monitorapi.NewInterval(monitorapi.SourceKubeletLog, monitorapi.Info).
 			Locator(containerRef).
@@ -313,7 +292,7 @@ func startupProbeError(nodeName, logLine string) monitorapi.Intervals {
 	}
 
 	containerRef := probeProblemToContainerReference(logLine)
-	failureTime := systemdJournalLogTime(logLine)
+	failureTime := logrules.SystemdJournalLogTime(logLine)
 	return monitorapi.Intervals{
 		monitorapi.NewInterval(monitorapi.SourceKubeletLog, monitorapi.Info).
 			Locator(containerRef).
@@ -417,7 +396,7 @@ func failedToDeleteCGroupsPath(nodeLocator monitorapi.Locator, logLine string) m
 		return nil
 	}
 
-	failureTime := systemdJournalLogTime(logLine)
+	failureTime := logrules.SystemdJournalLogTime(logLine)
 
 	return monitorapi.Intervals{
 		monitorapi.NewInterval(monitorapi.SourceKubeletLog, monitorapi.Error).
@@ -432,7 +411,7 @@ func anonymousCertConnectionError(nodeLocator monitorapi.Locator, logLine string
 		return nil
 	}
 
-	failureTime := systemdJournalLogTime(logLine)
+	failureTime := logrules.SystemdJournalLogTime(logLine)
 
 	return monitorapi.Intervals{
 		monitorapi.NewInterval(monitorapi.SourceKubeletLog, monitorapi.Error).
@@ -456,7 +435,7 @@ func leaseUpdateError(nodeLocator monitorapi.Locator, logLine string) monitorapi
 		return nil
 	}
 
-	failureTime := systemdJournalLogTime(logLine)
+	failureTime := logrules.SystemdJournalLogTime(logLine)
 	url := ""
 	msg := ""
 
@@ -526,7 +505,7 @@ func commonErrorInterval(nodeName, logLine string, messageExp *regexp.Regexp, re
 		message = unquotedMessage
 	}
 
-	failureTime := systemdJournalLogTime(logLine)
+	failureTime := logrules.SystemdJournalLogTime(logLine)
 	return monitorapi.Intervals{
 		monitorapi.NewInterval(monitorapi.SourceKubeletLog, monitorapi.Info).
 			Locator(locator()).
@@ -536,61 +515,3 @@ func commonErrorInterval(nodeName, logLine string, messageExp *regexp.Regexp, re
 			Build(failureTime, failureTime),
 	}
 }
-
-var kubeletTimeRegex = regexp.MustCompile(`^(?P<MONTH>\S+)\s(?P<DAY>\S+)\s(?P<TIME>\S+)`)
-
-// systemdJournalLogTime returns Now if there is trouble reading the time.  This will stack the event intervals without
-// parsable times at the end of the run, which will be more clearly visible as a problem than not reporting them.
-func systemdJournalLogTime(logLine string) time.Time {
-	kubeletTimeRegex.MatchString(logLine)
-	if !kubeletTimeRegex.MatchString(logLine) {
-		return time.Now()
-	}
-
-	month := ""
-	day := ""
-	year := fmt.Sprintf("%d", time.Now().Year())
-	timeOfDay := ""
-	subMatches := kubeletTimeRegex.FindStringSubmatch(logLine)
-	subNames := kubeletTimeRegex.SubexpNames()
-	for i, name := range subNames {
-		switch name {
-		case "MONTH":
-			month = subMatches[i]
-		case "DAY":
-			day = subMatches[i]
-		case "TIME":
-			timeOfDay = subMatches[i]
-		}
-	}
-
-	timeString := fmt.Sprintf("%s %s %s %s UTC", day, month, year, timeOfDay)
-	ret, err := time.Parse("02 Jan 2006 15:04:05.999999999 MST", timeString)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failure parsing time format: %v for %q\n", err, timeString)
-		return time.Now()
-	}
-
-	return ret
-}
-
-// getNodeLog returns logs for a particular systemd service on a given node.
-// We're count on these logs to fit into some reasonable memory size.
-func getNodeLog(ctx context.Context, client kubernetes.Interface, nodeName, systemdServiceName string) ([]byte, error) {
-	path := client.CoreV1().RESTClient().Get().
-		Namespace("").Name(nodeName).
-		Resource("nodes").SubResource("proxy", "logs").Suffix("journal").URL().Path
-
-	req := client.CoreV1().RESTClient().Get().RequestURI(path).
-		SetHeader("Accept", "text/plain, */*")
-	req.Param("since", "-1d")
-	req.Param("unit", systemdServiceName)
-
-	in, err := req.Stream(ctx)
-	if err != nil {
-		return nil, err
-	}
-	defer in.Close()
-
-	return ioutil.ReadAll(in)
-}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/logrules"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -44,8 +45,8 @@ func TestMonitorApiIntervals(t *testing.T) {
 						},
 					},
 				},
-				From: systemdJournalLogTime("Sep 27 08:59:59.857303"),
-				To:   systemdJournalLogTime("Sep 27 08:59:59.857303"),
+				From: logrules.SystemdJournalLogTime("Sep 27 08:59:59.857303"),
+				To:   logrules.SystemdJournalLogTime("Sep 27 08:59:59.857303"),
 			},
 		},
 		{
@@ -72,8 +73,8 @@ func TestMonitorApiIntervals(t *testing.T) {
 						},
 					},
 				},
-				From: systemdJournalLogTime("Sep 27 08:59:59.853216"),
-				To:   systemdJournalLogTime("Sep 27 08:59:59.853216"),
+				From: logrules.SystemdJournalLogTime("Sep 27 08:59:59.853216"),
+				To:   logrules.SystemdJournalLogTime("Sep 27 08:59:59.853216"),
 			},
 		},
 		{
@@ -98,8 +99,8 @@ func TestMonitorApiIntervals(t *testing.T) {
 						},
 					},
 				},
-				From: systemdJournalLogTime("Sep 27 08:59:59.853216"),
-				To:   systemdJournalLogTime("Sep 27 08:59:59.853216"),
+				From: logrules.SystemdJournalLogTime("Sep 27 08:59:59.853216"),
+				To:   logrules.SystemdJournalLogTime("Sep 27 08:59:59.853216"),
 			},
 		},
 		{
@@ -123,8 +124,8 @@ func TestMonitorApiIntervals(t *testing.T) {
 						},
 					},
 				},
-				From: systemdJournalLogTime("May 19 19:10:03.753983"),
-				To:   systemdJournalLogTime("May 19 19:10:04.753983"),
+				From: logrules.SystemdJournalLogTime("May 19 19:10:03.753983"),
+				To:   logrules.SystemdJournalLogTime("May 19 19:10:04.753983"),
 			},
 		},
 		{
@@ -148,8 +149,8 @@ func TestMonitorApiIntervals(t *testing.T) {
 						},
 					},
 				},
-				From: systemdJournalLogTime("Jun 29 05:16:54.197389"),
-				To:   systemdJournalLogTime("Jun 29 05:16:55.197389"),
+				From: logrules.SystemdJournalLogTime("Jun 29 05:16:54.197389"),
+				To:   logrules.SystemdJournalLogTime("Jun 29 05:16:55.197389"),
 			},
 		},
 		{
@@ -177,8 +178,8 @@ func TestMonitorApiIntervals(t *testing.T) {
 						},
 					},
 				},
-				From: systemdJournalLogTime("Jul 05 17:47:52.807876"),
-				To:   systemdJournalLogTime("Jul 05 17:47:52.807876"),
+				From: logrules.SystemdJournalLogTime("Jul 05 17:47:52.807876"),
+				To:   logrules.SystemdJournalLogTime("Jul 05 17:47:52.807876"),
 			},
 		},
 		{
@@ -206,8 +207,8 @@ func TestMonitorApiIntervals(t *testing.T) {
 						},
 					},
 				},
-				From: systemdJournalLogTime("Jul 05 17:43:12.908344"),
-				To:   systemdJournalLogTime("Jul 05 17:43:12.908344"),
+				From: logrules.SystemdJournalLogTime("Jul 05 17:43:12.908344"),
+				To:   logrules.SystemdJournalLogTime("Jul 05 17:43:12.908344"),
 			},
 		},
 		{
@@ -237,8 +238,8 @@ func TestMonitorApiIntervals(t *testing.T) {
 						},
 					},
 				},
-				From: systemdJournalLogTime("Feb 01 05:37:45.731611"),
-				To:   systemdJournalLogTime("Feb 01 05:37:45.731611"),
+				From: logrules.SystemdJournalLogTime("Feb 01 05:37:45.731611"),
+				To:   logrules.SystemdJournalLogTime("Feb 01 05:37:45.731611"),
 			},
 		},
 		{
@@ -261,8 +262,8 @@ func TestMonitorApiIntervals(t *testing.T) {
 						Annotations:  map[monitorapi.AnnotationKey]string{},
 					},
 				},
-				From: systemdJournalLogTime("Apr 12 11:49:49.188086"),
-				To:   systemdJournalLogTime("Apr 12 11:49:50.188086"),
+				From: logrules.SystemdJournalLogTime("Apr 12 11:49:49.188086"),
+				To:   logrules.SystemdJournalLogTime("Apr 12 11:49:50.188086"),
 			},
 		},
 	}
@@ -474,8 +475,8 @@ func Test_messageTime(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := systemdJournalLogTime(tt.args.logLine); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("systemdJournalLogTime() = %v, want %v", got, tt.want)
+			if got := logrules.SystemdJournalLogTime(tt.args.logLine); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("logrules.SystemdJournalLogTime() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -0,0 +1,226 @@
+package disruptioncustombackends
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+// TargetKind is what a backend samples.
+type TargetKind string
+
+const (
+	// RouteTarget samples https://<route host><path>.
+	RouteTarget TargetKind = "Route"
+	// ServiceTarget samples the load balancer ingress of a service of type LoadBalancer, and the cluster IP of other
+	// services, which is only reachable when openshift-tests runs in the cluster.
+	ServiceTarget TargetKind = "Service"
+	// URLTarget samples a fixed URL.
+	URLTarget TargetKind = "URL"
+)
+
+// Mode is what is done with the disruption of a backend.
+type Mode string
+
+const (
+	// FailMode fails a junit test when the backend is disrupted for longer than its allowedDisruption.
+	FailMode Mode = "Fail"
+	// RecordOnlyMode only records the disruption in the intervals and backend-disruption*.json.
+	RecordOnlyMode Mode = "RecordOnly"
+)
+
+// BackendsFile is the serialized form of --disruption-backends.
+type BackendsFile struct {
+	Backends []BackendDefinition `json:"backends"`
+}
+
+// BackendDefinition is a backend that is sampled for disruption over each of its connection types.
+type BackendDefinition struct {
+	// Name is the backend disruption name, -<connection type>-connections is appended to it for each connection type.
+	Name   string `json:"name"`
+	Target Target `json:"target"`
+	// Path is appended to the route, service or URL.
+	Path string `json:"path,omitempty"`
+	// ExpectedStatusCode is an additional status code, other than 200-399, the backend is available with.
+	ExpectedStatusCode int `json:"expectedStatusCode,omitempty"`
+	// ExpectedBodyRegex must match the body of the responses with a status of 200-399.
+	ExpectedBodyRegex string `json:"expectedBodyRegex,omitempty"`
+	// ConnectionTypes are new, reused or both, which is the default.
+	ConnectionTypes []monitorapi.BackendConnectionType `json:"connectionTypes,omitempty"`
+	Auth            *Auth                              `json:"auth,omitempty"`
+	// Mode is Fail, the default, or RecordOnly.
+	Mode Mode `json:"mode,omitempty"`
+	// AllowedDisruption is how long the backend may be disrupted for in Fail mode, it defaults to none.
+	AllowedDisruption string `json:"allowedDisruption,omitempty"`
+}
+
+type Target struct {
+	Kind TargetKind `json:"kind"`
+	// Namespace and Name locate the route or service.
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	// Port is the name or number of the service port, it defaults to the first port.
+	Port intstr.IntOrString `json:"port,omitempty"`
+	// Scheme is http, the default, or https for services.
+	Scheme string `json:"scheme,omitempty"`
+	// URL is the scheme://host[:port] of URL targets.
+	URL string `json:"url,omitempty"`
+}
+
+// Auth is used to authenticate requests to backends that are not public.
+type Auth struct {
+	// BearerTokenFile contains a token sent in the Authorization header.  It is read again when it changes.
+	BearerTokenFile string `json:"bearerTokenFile,omitempty"`
+	// CAFile is the CA bundle the server is verified with.  The server is not verified when it is empty.
+	CAFile string `json:"caFile,omitempty"`
+}
+
+// Backend is a validated BackendDefinition with its defaults set.
+type Backend struct {
+	BackendDefinition
+	allowedDisruption time.Duration
+}
+
+// BackendsFromFile reads and validates a BackendsFile.
+func BackendsFromFile(filename string) ([]Backend, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	backends, err := BackendsFromYAML(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return backends, nil
+}
+
+// BackendsFromYAML parses a BackendsFile.  Unknown fields are rejected so a typo does not silently change what is
+// sampled.
+func BackendsFromYAML(data []byte) ([]Backend, error) {
+	file := &BackendsFile{}
+	if err := yaml.UnmarshalStrict(data, file); err != nil {
+		return nil, err
+	}
+
+	names := sets.New[string]()
+	var backends []Backend
+	for _, definition := range file.Backends {
+		if names.Has(definition.Name) {
+			return nil, fmt.Errorf("%q is defined more than once", definition.Name)
+		}
+		names.Insert(definition.Name)
+
+		backend, err := definition.toBackend()
+		if err != nil {
+			return nil, err
+		}
+		backends = append(backends, backend)
+	}
+	return backends, nil
+}
+
+var backendNameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+func (d BackendDefinition) toBackend() (Backend, error) {
+	if !backendNameRegex.MatchString(d.Name) {
+		return Backend{}, fmt.Errorf("backend name %q must consist of lower case alphanumeric characters and '-'", d.Name)
+	}
+	if err := d.Target.validate(); err != nil {
+		return Backend{}, fmt.Errorf("%q: %w", d.Name, err)
+	}
+	if len(d.ExpectedBodyRegex) > 0 {
+		if _, err := regexp.Compile(d.ExpectedBodyRegex); err != nil {
+			return Backend{}, fmt.Errorf("%q: invalid expectedBodyRegex: %w", d.Name, err)
+		}
+	}
+	if d.ExpectedStatusCode != 0 && (d.ExpectedStatusCode < 100 || d.ExpectedStatusCode > 599) {
+		return Backend{}, fmt.Errorf("%q: invalid expectedStatusCode %d", d.Name, d.ExpectedStatusCode)
+	}
+	if d.Auth != nil && len(d.Auth.BearerTokenFile) == 0 && len(d.Auth.CAFile) == 0 {
+		return Backend{}, fmt.Errorf("%q: auth must specify a bearerTokenFile or caFile", d.Name)
+	}
+
+	if len(d.Path) > 0 && !strings.HasPrefix(d.Path, "/") {
+		return Backend{}, fmt.Errorf("%q: path must start with /", d.Name)
+	}
+
+	backend := Backend{BackendDefinition: d}
+	backend.ConnectionTypes = nil
+	connectionTypes := sets.New[monitorapi.BackendConnectionType]()
+	for _, connectionType := range d.ConnectionTypes {
+		switch connectionType {
+		case monitorapi.NewConnectionType, monitorapi.ReusedConnectionType:
+		default:
+			return Backend{}, fmt.Errorf("%q: unknown connection type %q, expected %s or %s", d.Name, connectionType, monitorapi.NewConnectionType, monitorapi.ReusedConnectionType)
+		}
+		if !connectionTypes.Has(connectionType) {
+			connectionTypes.Insert(connectionType)
+			backend.ConnectionTypes = append(backend.ConnectionTypes, connectionType)
+		}
+	}
+	if len(backend.ConnectionTypes) == 0 {
+		backend.ConnectionTypes = []monitorapi.BackendConnectionType{monitorapi.NewConnectionType, monitorapi.ReusedConnectionType}
+	}
+
+	switch d.Mode {
+	case "":
+		backend.Mode = FailMode
+	case FailMode, RecordOnlyMode:
+	default:
+		return Backend{}, fmt.Errorf("%q: unknown mode %q, expected %s or %s", d.Name, d.Mode, FailMode, RecordOnlyMode)
+	}
+	if len(d.AllowedDisruption) > 0 {
+		allowed, err := time.ParseDuration(d.AllowedDisruption)
+		if err != nil {
+			return Backend{}, fmt.Errorf("%q: invalid allowedDisruption: %w", d.Name, err)
+		}
+		if allowed < 0 {
+			return Backend{}, fmt.Errorf("%q: allowedDisruption must not be negative", d.Name)
+		}
+		backend.allowedDisruption = allowed
+	}
+	return backend, nil
+}
+
+func (t Target) validate() error {
+	switch t.Kind {
+	case RouteTarget, ServiceTarget:
+		if len(t.Namespace) == 0 || len(t.Name) == 0 {
+			return fmt.Errorf("%s targets must specify a namespace and name", t.Kind)
+		}
+		if len(t.URL) > 0 {
+			return fmt.Errorf("url is only valid for %s targets", URLTarget)
+		}
+	case URLTarget:
+		if len(t.Namespace) > 0 || len(t.Name) > 0 {
+			return fmt.Errorf("namespace and name are not valid for %s targets", URLTarget)
+		}
+		u, err := url.Parse(t.URL)
+		if err != nil {
+			return fmt.Errorf("invalid url: %w", err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 || (len(u.Path) > 0 && u.Path != "/") || len(u.RawQuery) > 0 {
+			return fmt.Errorf("url %q must be http(s)://host[:port], use path for the rest", t.URL)
+		}
+	default:
+		return fmt.Errorf("unknown target kind %q, expected %s, %s or %s", t.Kind, RouteTarget, ServiceTarget, URLTarget)
+	}
+
+	if t.Kind != ServiceTarget && (t.Port != (intstr.IntOrString{}) || len(t.Scheme) > 0) {
+		return fmt.Errorf("port and scheme are only valid for %s targets", ServiceTarget)
+	}
+	switch t.Scheme {
+	case "", "http", "https":
+	default:
+		return fmt.Errorf("unknown scheme %q, expected http or https", t.Scheme)
+	}
+	return nil
+}
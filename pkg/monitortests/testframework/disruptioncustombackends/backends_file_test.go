@@ -0,0 +1,128 @@
+package disruptioncustombackends
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestBackendsFromYAML(t *testing.T) {
+	backends, err := BackendsFromYAML([]byte(`
+backends:
+- name: my-app-route
+  target:
+    kind: Route
+    namespace: my-app
+    name: frontend
+  path: /healthz
+  expectedBodyRegex: "^ok$"
+  allowedDisruption: 5s
+- name: my-app-service
+  target:
+    kind: Service
+    namespace: my-app
+    name: frontend
+    port: 8443
+    scheme: https
+  connectionTypes: [reused, reused]
+  mode: RecordOnly
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backends) != 2 {
+		t.Fatalf("expected 2 backends, got %d", len(backends))
+	}
+
+	route := backends[0]
+	if route.Mode != FailMode || route.allowedDisruption != 5*time.Second {
+		t.Errorf("unexpected mode %q or allowed disruption %s", route.Mode, route.allowedDisruption)
+	}
+	if want := []monitorapi.BackendConnectionType{monitorapi.NewConnectionType, monitorapi.ReusedConnectionType}; !reflect.DeepEqual(route.ConnectionTypes, want) {
+		t.Errorf("expected both connection types by default, got %v", route.ConnectionTypes)
+	}
+
+	service := backends[1]
+	if service.Mode != RecordOnlyMode || service.Target.Port != intstr.FromInt(8443) {
+		t.Errorf("unexpected mode %q or port %v", service.Mode, service.Target.Port)
+	}
+	if want := []monitorapi.BackendConnectionType{monitorapi.ReusedConnectionType}; !reflect.DeepEqual(service.ConnectionTypes, want) {
+		t.Errorf("expected duplicate connection types to be dropped, got %v", service.ConnectionTypes)
+	}
+}
+
+func TestBackendsFromYAML_invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name:    "unknown field",
+			yaml:    "backends:\n- name: a\n  target: {kind: URL, url: 'http://example.com'}\n  expectedBody: ok\n",
+			wantErr: `unknown field "expectedBody"`,
+		},
+		{
+			name:    "duplicate name",
+			yaml:    "backends:\n- name: a\n  target: {kind: URL, url: 'http://example.com'}\n- name: a\n  target: {kind: URL, url: 'http://example.com'}\n",
+			wantErr: `"a" is defined more than once`,
+		},
+		{
+			name:    "invalid name",
+			yaml:    "backends:\n- name: My_App\n  target: {kind: URL, url: 'http://example.com'}\n",
+			wantErr: `backend name "My_App" must consist of`,
+		},
+		{
+			name:    "route without name",
+			yaml:    "backends:\n- name: a\n  target: {kind: Route, namespace: ns}\n",
+			wantErr: `"a": Route targets must specify a namespace and name`,
+		},
+		{
+			name:    "url with path",
+			yaml:    "backends:\n- name: a\n  target: {kind: URL, url: 'http://example.com/healthz'}\n",
+			wantErr: "use path for the rest",
+		},
+		{
+			name:    "port on a route",
+			yaml:    "backends:\n- name: a\n  target: {kind: Route, namespace: ns, name: r, port: 80}\n",
+			wantErr: "port and scheme are only valid for Service targets",
+		},
+		{
+			name:    "unknown connection type",
+			yaml:    "backends:\n- name: a\n  target: {kind: URL, url: 'http://example.com'}\n  connectionTypes: [New]\n",
+			wantErr: `unknown connection type "New"`,
+		},
+		{
+			name:    "unknown mode",
+			yaml:    "backends:\n- name: a\n  target: {kind: URL, url: 'http://example.com'}\n  mode: Warn\n",
+			wantErr: `unknown mode "Warn"`,
+		},
+		{
+			name:    "relative path",
+			yaml:    "backends:\n- name: a\n  target: {kind: URL, url: 'http://example.com'}\n  path: healthz\n",
+			wantErr: "path must start with /",
+		},
+		{
+			name:    "invalid allowed disruption",
+			yaml:    "backends:\n- name: a\n  target: {kind: URL, url: 'http://example.com'}\n  allowedDisruption: 5\n",
+			wantErr: "invalid allowedDisruption",
+		},
+		{
+			name:    "empty auth",
+			yaml:    "backends:\n- name: a\n  target: {kind: URL, url: 'http://example.com'}\n  auth: {}\n",
+			wantErr: "auth must specify a bearerTokenFile or caFile",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BackendsFromYAML([]byte(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package disruptioncustombackends
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/backenddisruption"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/rest"
+)
+
+// sampledBackend is a sampler for one connection type of a backend.
+type sampledBackend struct {
+	backend Backend
+	sampler *backenddisruption.BackendSampler
+}
+
+type availability struct {
+	backendsFile string
+
+	samplers []sampledBackend
+}
+
+// NewAvailabilityInvariant samples the backends of a --disruption-backends file.  Their disruption is recorded like
+// that of the built-in backends, and backends in Fail mode fail a junit test when disrupted for too long.
+func NewAvailabilityInvariant(backendsFile string) monitortestframework.MonitorTest {
+	return &availability{
+		backendsFile: backendsFile,
+	}
+}
+
+func (w *availability) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	backends, err := BackendsFromFile(w.backendsFile)
+	if err != nil {
+		return err
+	}
+
+	for _, backend := range backends {
+		for _, connectionType := range backend.ConnectionTypes {
+			sampler, err := newBackendSampler(adminRESTConfig, backend, connectionType)
+			if err != nil {
+				return err
+			}
+			w.samplers = append(w.samplers, sampledBackend{backend: backend, sampler: sampler})
+		}
+	}
+
+	for _, sampled := range w.samplers {
+		if err := sampled.sampler.StartEndpointMonitoring(ctx, recorder, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newBackendSampler(adminRESTConfig *rest.Config, backend Backend, connectionType monitorapi.BackendConnectionType) (*backenddisruption.BackendSampler, error) {
+	var sampler *backenddisruption.BackendSampler
+	target := backend.Target
+	switch target.Kind {
+	case RouteTarget:
+		sampler = backenddisruption.NewRouteBackend(adminRESTConfig, target.Namespace, target.Name, backend.Name, backend.Path, connectionType)
+	case ServiceTarget:
+		scheme := target.Scheme
+		if len(scheme) == 0 {
+			scheme = "http"
+		}
+		sampler = backenddisruption.NewServiceBackend(adminRESTConfig, target.Namespace, target.Name, target.Port, scheme, backend.Name, backend.Path, connectionType)
+	case URLTarget:
+		sampler = backenddisruption.NewSimpleBackendWithLocator(
+			monitorapi.NewLocator().LocateDisruptionCheck(fmt.Sprintf("%s-%v-connections", backend.Name, connectionType), backenddisruption.OpenshiftTestsSource, connectionType),
+			strings.TrimSuffix(target.URL, "/"),
+			backend.Path,
+			connectionType)
+	default:
+		return nil, fmt.Errorf("%q: unknown target kind %q", backend.Name, target.Kind)
+	}
+
+	if backend.ExpectedStatusCode > 0 {
+		sampler = sampler.WithExpectedStatusCode(backend.ExpectedStatusCode)
+	}
+	if len(backend.ExpectedBodyRegex) > 0 {
+		sampler = sampler.WithExpectedBodyRegex(backend.ExpectedBodyRegex)
+	}
+	if backend.Auth != nil {
+		tlsConfig := &tls.Config{InsecureSkipVerify: true}
+		if len(backend.Auth.CAFile) > 0 {
+			caBundle, err := os.ReadFile(backend.Auth.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("%q: %w", backend.Name, err)
+			}
+			roots := x509.NewCertPool()
+			if !roots.AppendCertsFromPEM(caBundle) {
+				return nil, fmt.Errorf("%q: no certificates found in %s", backend.Name, backend.Auth.CAFile)
+			}
+			tlsConfig = &tls.Config{RootCAs: roots}
+		}
+		sampler = sampler.WithTLSConfig(tlsConfig)
+		if len(backend.Auth.BearerTokenFile) > 0 {
+			sampler = sampler.WithBearerTokenAuth("", backend.Auth.BearerTokenFile)
+		}
+	}
+	return sampler, nil
+}
+
+func (w *availability) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	// when it is time to collect data, we need to stop the samplers.  they all have to drain, so stop in parallel
+	wg := sync.WaitGroup{}
+	errs := make([]error, len(w.samplers))
+	for i := range w.samplers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					errs[i] = fmt.Errorf("panic in stop: %v", r)
+				}
+			}()
+			w.samplers[i].sampler.Stop()
+		}(i)
+	}
+	wg.Wait()
+
+	return nil, nil, utilerrors.NewAggregate(errs)
+}
+
+func (*availability) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, nil
+}
+
+func (w *availability) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	var junits []*junitapi.JUnitTestCase
+	for _, sampled := range w.samplers {
+		if sampled.backend.Mode != FailMode {
+			continue
+		}
+		junits = append(junits, createDisruptionJunit(sampled.backend, sampled.sampler, finalIntervals))
+	}
+	return junits, nil
+}
+
+func createDisruptionJunit(backend Backend, sampler *backenddisruption.BackendSampler, finalIntervals monitorapi.Intervals) *junitapi.JUnitTestCase {
+	testName := fmt.Sprintf("[sig-trt] disruption/%s connection/%s should be available throughout the test", backend.Name, sampler.GetConnectionType())
+	disruptedIntervals := finalIntervals.Filter(
+		monitorapi.And(
+			monitorapi.IsEventForLocator(sampler.GetLocator()),
+			monitorapi.IsErrorEvent,
+		),
+	)
+	disruptionDuration := disruptedIntervals.Duration(1 * time.Second).Round(time.Second)
+	if disruptionDuration <= backend.allowedDisruption {
+		return &junitapi.JUnitTestCase{
+			Name: testName,
+		}
+	}
+
+	failureMessage := fmt.Sprintf("%v was unreachable for at least %s (maxAllowed=%s from --disruption-backends):\n\n%s",
+		sampler.GetLocator().OldLocator(), disruptionDuration, backend.allowedDisruption,
+		strings.Join(disruptedIntervals.Strings(), "\n"))
+	return &junitapi.JUnitTestCase{
+		Name: testName,
+		FailureOutput: &junitapi.FailureOutput{
+			Output: failureMessage,
+		},
+		SystemOut: failureMessage,
+	}
+}
+
+func (*availability) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func (*availability) Cleanup(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,69 @@
+package disruptionrootcause
+
+import (
+	"context"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortests/kubeapiserver/apiservergracefulrestart"
+	"github.com/openshift/origin/pkg/monitortests/node/nodestateanalyzer"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/rest"
+)
+
+const constructionOwner monitorapi.ConstructionOwner = "disruption-root-cause-analyzer"
+
+type disruptionRootCauseAnalyzer struct {
+	// candidateConstructors build the computed intervals that are candidate causes.  Monitor tests only see the
+	// intervals that were recorded when they construct theirs, so the shutdown and node state intervals are
+	// constructed again here rather than read from the other monitor tests.
+	candidateConstructors []monitortestframework.MonitorTest
+}
+
+// NewAnalyzer attributes every disruption interval to the apiserver shutdowns, node outages, router and ovnkube
+// restarts, load balancer changes and DNS errors that overlapped or immediately preceded it.  The ranked causes are
+// recorded on a DisruptionRootCause interval alongside each disruption interval.
+func NewAnalyzer() monitortestframework.MonitorTest {
+	return &disruptionRootCauseAnalyzer{
+		candidateConstructors: []monitortestframework.MonitorTest{
+			apiservergracefulrestart.NewGracefulShutdownAnalyzer(),
+			nodestateanalyzer.NewAnalyzer(),
+		},
+	}
+}
+
+func (w *disruptionRootCauseAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (w *disruptionRootCauseAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	return nil, nil, nil
+}
+
+func (w *disruptionRootCauseAnalyzer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	intervals := append(monitorapi.Intervals{}, startingIntervals...)
+	errs := []error{}
+	for _, constructor := range w.candidateConstructors {
+		computed, err := constructor.ConstructComputedIntervals(ctx, startingIntervals, recordedResources, beginning, end)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		intervals = append(intervals, computed...)
+	}
+	return attributeDisruption(intervals, end), utilerrors.NewAggregate(errs)
+}
+
+func (*disruptionRootCauseAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return nil, nil
+}
+
+func (*disruptionRootCauseAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func (*disruptionRootCauseAnalyzer) Cleanup(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,252 @@
+package disruptionrootcause
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// precedingWindow is how long before a disruption a candidate may have ended to still be a cause.  Disruption is
+// usually noticed a few samples after whatever caused it, and instant intervals such as restarts end as they begin.
+const precedingWindow = 30 * time.Second
+
+// CauseKind is a type of event that can explain disruption.
+type CauseKind string
+
+const (
+	DNSError                    CauseKind = "DNSError"
+	IncompleteAPIServerShutdown CauseKind = "IncompleteAPIServerShutdown"
+	NodeReboot                  CauseKind = "NodeReboot"
+	NodeNotReady                CauseKind = "NodeNotReady"
+	OVNKubeRestart              CauseKind = "OVNKubeRestart"
+	RouterRestart               CauseKind = "RouterRestart"
+	GracefulAPIServerShutdown   CauseKind = "GracefulAPIServerShutdown"
+	LoadBalancerChange          CauseKind = "LoadBalancerChange"
+)
+
+// causeWeights rank the kinds by how directly they explain disruption.  A DNS error is the sampler failing itself,
+// while a graceful shutdown should not disrupt anything and a load balancer change often happens without disruption.
+var causeWeights = map[CauseKind]int{
+	DNSError:                    100,
+	IncompleteAPIServerShutdown: 90,
+	NodeReboot:                  80,
+	NodeNotReady:                70,
+	OVNKubeRestart:              60,
+	RouterRestart:               60,
+	GracefulAPIServerShutdown:   50,
+	LoadBalancerChange:          40,
+}
+
+const (
+	// sameNodeBonus is added when the candidate happened on a node the disruption involves.
+	sameNodeBonus = 20
+	// precedingPenalty is subtracted when the candidate ended before the disruption began.
+	precedingPenalty = 10
+)
+
+// dnsErrorRegex matches the errors samplers get when they cannot resolve their backend.  It is looser than the
+// regex used to downgrade DNS timeouts to warnings so that failed lookups which stay errors are attributed too.
+var dnsErrorRegex = regexp.MustCompile(`dial tcp: lookup `)
+
+var loadBalancerEventReasons = sets.New[monitorapi.IntervalReason](
+	"EnsuringLoadBalancer",
+	"EnsuredLoadBalancer",
+	"UpdatedLoadBalancer",
+	"DeletingLoadBalancer",
+	"DeletedLoadBalancer",
+	"SyncLoadBalancerFailed",
+	"UnAvailableLoadBalancer",
+)
+
+// candidate is an interval that may have caused disruption.
+type candidate struct {
+	kind     CauseKind
+	interval monitorapi.Interval
+	// node is where the candidate happened, if it happened on a node.
+	node string
+	// relevant reports whether the candidate can cause disruption of the backend.
+	relevant func(disruption monitorapi.Interval) bool
+}
+
+// rankedCause is a candidate that overlapped or immediately preceded a disruption.
+type rankedCause struct {
+	candidate
+	score int
+}
+
+func (c rankedCause) String() string {
+	locator := c.interval.Locator.OldLocator()
+	if len(c.node) > 0 {
+		return fmt.Sprintf("%s on node/%s (%s)", c.kind, c.node, locator)
+	}
+	return fmt.Sprintf("%s (%s)", c.kind, locator)
+}
+
+func anyBackend(monitorapi.Interval) bool {
+	return true
+}
+
+// findCandidates returns every interval that may explain disruption, along with which backends it can disrupt.
+func findCandidates(intervals monitorapi.Intervals) []candidate {
+	var candidates []candidate
+	for _, interval := range intervals {
+		node := interval.Locator.Keys[monitorapi.LocatorNodeKey]
+		switch {
+		case interval.Source == monitorapi.APIServerGracefulShutdown:
+			kind := GracefulAPIServerShutdown
+			if interval.Message.Reason == monitorapi.IncompleteAPIServerShutdown {
+				kind = IncompleteAPIServerShutdown
+			}
+			candidates = append(candidates, candidate{
+				kind:     kind,
+				interval: interval,
+				node:     node,
+				relevant: isBackendOfServer(interval.Locator.Keys[monitorapi.LocatorServerKey]),
+			})
+
+		case interval.Source == monitorapi.SourceNodeState && interval.Message.Reason == monitorapi.NodeNotReadyReason:
+			candidates = append(candidates, candidate{kind: NodeNotReady, interval: interval, node: node, relevant: anyBackend})
+
+		case interval.Source == monitorapi.SourceNodeState && interval.Message.Reason == monitorapi.NodeUpdateReason &&
+			interval.Message.Annotations[monitorapi.AnnotationPhase] == "Reboot":
+			candidates = append(candidates, candidate{kind: NodeReboot, interval: interval, node: node, relevant: anyBackend})
+
+		case interval.Source == monitorapi.SourcePodMonitor && interval.Message.Reason == monitorapi.ContainerReasonRestarted:
+			switch interval.Locator.Keys[monitorapi.LocatorNamespaceKey] {
+			case "openshift-ovn-kubernetes":
+				candidates = append(candidates, candidate{kind: OVNKubeRestart, interval: interval, node: node, relevant: anyBackend})
+			case "openshift-ingress":
+				candidates = append(candidates, candidate{kind: RouterRestart, interval: interval, node: node, relevant: isIngressBackend})
+			}
+
+		case interval.Source == monitorapi.SourceKubeEvent && loadBalancerEventReasons.Has(interval.Message.Reason):
+			candidates = append(candidates, candidate{kind: LoadBalancerChange, interval: interval, relevant: isLoadBalancerBackend})
+
+		case interval.Source == monitorapi.SourceDisruption && dnsErrorRegex.MatchString(interval.Message.HumanMessage) &&
+			(interval.Message.Reason == monitorapi.DisruptionSamplerOutageBeganEventReason || interval.Message.Reason == monitorapi.DisruptionBeganEventReason):
+			candidates = append(candidates, candidate{
+				kind:     DNSError,
+				interval: interval,
+				relevant: monitorapi.IsEventForBackendDisruptionName(monitorapi.BackendDisruptionNameFromLocator(interval.Locator)),
+			})
+		}
+	}
+	return candidates
+}
+
+// isBackendOfServer matches the backends served by an apiserver, kube-apiserver serves the kube-api and
+// cache-kube-api backends for instance.
+func isBackendOfServer(server string) func(monitorapi.Interval) bool {
+	api := strings.TrimSuffix(server, "server")
+	return func(disruption monitorapi.Interval) bool {
+		return len(api) > 0 && strings.Contains(monitorapi.BackendDisruptionNameFromLocator(disruption.Locator), api)
+	}
+}
+
+// isIngressBackend matches the backends sampled through the routers.
+func isIngressBackend(disruption monitorapi.Interval) bool {
+	return len(disruption.Locator.Keys[monitorapi.LocatorRouteKey]) > 0 ||
+		strings.HasPrefix(monitorapi.BackendDisruptionNameFromLocator(disruption.Locator), "ingress-")
+}
+
+// isLoadBalancerBackend matches the backends sampled through a cloud load balancer.
+func isLoadBalancerBackend(disruption monitorapi.Interval) bool {
+	return len(disruption.Locator.Keys[monitorapi.LocatorLoadBalancerKey]) > 0 ||
+		len(disruption.Locator.Keys[monitorapi.LocatorServiceKey]) > 0 ||
+		strings.Contains(monitorapi.BackendDisruptionNameFromLocator(disruption.Locator), "load-balancer")
+}
+
+// disruptionNodes are the nodes a disruption involves, for the pod network backends that sample from and to nodes.
+func disruptionNodes(disruption monitorapi.Interval) sets.Set[string] {
+	nodes := sets.New[string]()
+	for _, key := range []monitorapi.LocatorKey{monitorapi.LocatorNodeKey, monitorapi.LocatorSourceNodeKey, monitorapi.LocatorTargetNodeKey} {
+		if node := disruption.Locator.Keys[key]; len(node) > 0 {
+			nodes.Insert(node)
+		}
+	}
+	return nodes
+}
+
+// rankCauses returns the relevant candidates that overlapped the disruption, or ended at most precedingWindow before
+// it began, with the most likely cause first.
+func rankCauses(disruption monitorapi.Interval, candidates []candidate, end time.Time) []rankedCause {
+	nodes := disruptionNodes(disruption)
+	var causes []rankedCause
+	for _, c := range candidates {
+		if !c.relevant(disruption) {
+			continue
+		}
+
+		candidateTo := c.interval.To
+		if candidateTo.IsZero() || candidateTo.Before(c.interval.From) {
+			// incomplete shutdowns are never closed
+			candidateTo = end
+		}
+		if c.interval.From.After(disruption.To) || candidateTo.Before(disruption.From.Add(-precedingWindow)) {
+			continue
+		}
+
+		score := causeWeights[c.kind]
+		if len(c.node) > 0 && nodes.Has(c.node) {
+			score += sameNodeBonus
+		}
+		if candidateTo.Before(disruption.From) {
+			score -= precedingPenalty
+		}
+		causes = append(causes, rankedCause{candidate: c, score: score})
+	}
+
+	sort.SliceStable(causes, func(i, j int) bool {
+		if causes[i].score != causes[j].score {
+			return causes[i].score > causes[j].score
+		}
+		return causes[i].interval.From.Before(causes[j].interval.From)
+	})
+	return causes
+}
+
+// attributeDisruption returns an interval for every disruption, spanning the same time with the same locator.  Its
+// cause is the comma separated kinds of the ranked causes, and its message lists where each cause happened.
+func attributeDisruption(intervals monitorapi.Intervals, end time.Time) monitorapi.Intervals {
+	candidates := findCandidates(intervals)
+
+	ret := monitorapi.Intervals{}
+	for _, disruption := range intervals {
+		if disruption.Source != monitorapi.SourceDisruption || disruption.Level != monitorapi.Error {
+			continue
+		}
+
+		causes := rankCauses(disruption, candidates, end)
+		message := monitorapi.NewMessage().Constructed(constructionOwner)
+		if len(causes) == 0 {
+			message = message.Reason(monitorapi.DisruptionUnattributedReason).
+				HumanMessage("no candidate cause overlapped or immediately preceded the disruption")
+		} else {
+			kinds := []string{}
+			seen := sets.New[CauseKind]()
+			descriptions := []string{}
+			for i, cause := range causes {
+				if !seen.Has(cause.kind) {
+					seen.Insert(cause.kind)
+					kinds = append(kinds, string(cause.kind))
+				}
+				descriptions = append(descriptions, fmt.Sprintf("%d. %s", i+1, cause))
+			}
+			message = message.Reason(monitorapi.DisruptionAttributedReason).
+				Cause(strings.Join(kinds, ",")).
+				HumanMessagef("likely causes: %s", strings.Join(descriptions, "; "))
+		}
+
+		ret = append(ret, monitorapi.NewInterval(monitorapi.SourceDisruptionRootCause, monitorapi.Info).
+			Locator(disruption.Locator).
+			Message(message).
+			Build(disruption.From, disruption.To),
+		)
+	}
+	return ret
+}
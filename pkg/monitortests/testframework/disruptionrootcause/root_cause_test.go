@@ -0,0 +1,108 @@
+package disruptionrootcause
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func disruptionInterval(backendName string, level monitorapi.IntervalLevel, message string, from, to time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceDisruption, level).
+		Locator(monitorapi.NewLocator().LocateDisruptionCheck(backendName, "openshift-tests", monitorapi.NewConnectionType)).
+		Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionBeganEventReason).HumanMessage(message)).
+		Build(from, to)
+}
+
+func TestAttributeDisruption(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	kubeAPIShutdown := monitorapi.NewInterval(monitorapi.APIServerGracefulShutdown, monitorapi.Error).
+		Locator(monitorapi.NewLocator().LocateServer("kube-apiserver", "master-0", "openshift-kube-apiserver", "kube-apiserver-master-0")).
+		Message(monitorapi.NewMessage().Reason(monitorapi.IncompleteAPIServerShutdown)).
+		Build(at(100), time.Time{})
+	nodeNotReady := monitorapi.NewInterval(monitorapi.SourceNodeState, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().NodeFromName("worker-0")).
+		Message(monitorapi.NewMessage().Reason(monitorapi.NodeNotReadyReason)).
+		Build(at(190), at(205))
+	routerRestart := monitorapi.NewInterval(monitorapi.SourcePodMonitor, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().ContainerFromNames("openshift-ingress", "router-default-1", "uid", "router")).
+		Message(monitorapi.NewMessage().Reason(monitorapi.ContainerReasonRestarted)).
+		Build(at(180), at(180))
+
+	intervals := monitorapi.Intervals{
+		kubeAPIShutdown,
+		nodeNotReady,
+		routerRestart,
+		// overlaps the shutdown and the node outage
+		disruptionInterval("kube-api-new-connections", monitorapi.Error, "kube-api is down", at(200), at(210)),
+		// the shutdown is of a different apiserver, and the node outage ended too long before
+		disruptionInterval("openshift-api-new-connections", monitorapi.Error, "openshift-api is down", at(300), at(305)),
+		// immediately follows the router restart
+		disruptionInterval("ingress-to-console-new-connections", monitorapi.Error, "console is down", at(200), at(201)),
+		// the sampler could not resolve the backend
+		disruptionInterval("image-registry-new-connections", monitorapi.Error, `Get "https://registry": dial tcp: lookup registry: no such host`, at(500), at(501)),
+		// not disrupted
+		disruptionInterval("oauth-api-new-connections", monitorapi.Info, "oauth-api is available", at(0), at(3600)),
+	}
+
+	attributed := attributeDisruption(intervals, end)
+	if len(attributed) != 4 {
+		t.Fatalf("expected an interval for each of the 4 disruption intervals, got %d:\n%s", len(attributed), strings.Join(attributed.Strings(), "\n"))
+	}
+
+	expected := map[string]struct {
+		reason monitorapi.IntervalReason
+		cause  string
+	}{
+		"kube-api-new-connections":           {reason: monitorapi.DisruptionAttributedReason, cause: "IncompleteAPIServerShutdown,NodeNotReady"},
+		"openshift-api-new-connections":      {reason: monitorapi.DisruptionUnattributedReason},
+		"ingress-to-console-new-connections": {reason: monitorapi.DisruptionAttributedReason, cause: "NodeNotReady,RouterRestart"},
+		"image-registry-new-connections":     {reason: monitorapi.DisruptionAttributedReason, cause: "DNSError"},
+	}
+	for _, interval := range attributed {
+		backendName := monitorapi.BackendDisruptionNameFromLocator(interval.Locator)
+		want, ok := expected[backendName]
+		if !ok {
+			t.Errorf("unexpected interval %s", interval)
+			continue
+		}
+		if interval.Source != monitorapi.SourceDisruptionRootCause || interval.Level != monitorapi.Info {
+			t.Errorf("%s: unexpected source %s or level %s", backendName, interval.Source, interval.Level)
+		}
+		if interval.Message.Reason != want.reason || interval.Message.Cause != want.cause {
+			t.Errorf("%s: expected reason %q and cause %q, got %q and %q", backendName, want.reason, want.cause, interval.Message.Reason, interval.Message.Cause)
+		}
+	}
+}
+
+func TestRankCauses_sameNodeFirst(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	ovnkubeRestart := func(node string) monitorapi.Interval {
+		locator := monitorapi.NewLocator().ContainerFromNames("openshift-ovn-kubernetes", "ovnkube-node-"+node, "uid", "ovnkube-controller")
+		locator.Keys[monitorapi.LocatorNodeKey] = node
+		return monitorapi.NewInterval(monitorapi.SourcePodMonitor, monitorapi.Warning).
+			Locator(locator).
+			Message(monitorapi.NewMessage().Reason(monitorapi.ContainerReasonRestarted)).
+			Build(at(10), at(10))
+	}
+	candidates := findCandidates(monitorapi.Intervals{ovnkubeRestart("worker-0"), ovnkubeRestart("worker-1")})
+
+	disruption := monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+		Locator(monitorapi.NewLocator().LocateNodePairDisruptionCheck("pod-to-pod-new-connections", "worker-2-to-worker-1", "worker-2", "", "worker-1", "", monitorapi.NewConnectionType)).
+		Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionBeganEventReason)).
+		Build(at(5), at(15))
+
+	causes := rankCauses(disruption, candidates, at(60))
+	if len(causes) != 2 {
+		t.Fatalf("expected both restarts to be causes, got %d", len(causes))
+	}
+	if causes[0].node != "worker-1" {
+		t.Errorf("expected the restart on the target node to rank first, got %s", causes[0])
+	}
+}
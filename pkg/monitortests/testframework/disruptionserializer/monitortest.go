@@ -65,6 +65,12 @@ type BackendDisruption struct {
 	DisruptedDuration  metav1.Duration
 	DisruptionMessages []string
 
+	// AttributedDuration is the part of DisruptedDuration that the disruption-root-cause-analyzer found a likely cause
+	// for, and UnattributedDuration is the rest.  AttributedDurationByCause is keyed by the most likely cause.
+	AttributedDuration        metav1.Duration
+	UnattributedDuration      metav1.Duration
+	AttributedDurationByCause map[string]metav1.Duration `json:",omitempty"`
+
 	// New disruption test framework is introducing these fields, for
 	// previous version of the test, these fields will default:
 	//   LoadBalancerType will default to "external-lb"
@@ -121,8 +127,43 @@ func computeDisruptionData(eventIntervals monitorapi.Intervals) *BackendDisrupti
 			// part closely resembles the api being tested.
 			TargetAPI: "",
 		}
+		setAttributedDuration(bs, eventIntervals)
 		ret.BackendDisruptions[backendDisruptionName] = bs
 	}
 
 	return ret
 }
+
+// setAttributedDuration sums the root cause intervals of the backend, which span the same time as its disruption.
+func setAttributedDuration(bs *BackendDisruption, eventIntervals monitorapi.Intervals) {
+	rootCauseIntervals := eventIntervals.Filter(
+		monitorapi.And(
+			func(eventInterval monitorapi.Interval) bool {
+				return eventInterval.Source == monitorapi.SourceDisruptionRootCause
+			},
+			monitorapi.IsEventForBackendDisruptionName(bs.BackendName),
+		),
+	)
+
+	attributedByCause := map[string]monitorapi.Intervals{}
+	var attributed, unattributed monitorapi.Intervals
+	for _, interval := range rootCauseIntervals {
+		switch interval.Message.Reason {
+		case monitorapi.DisruptionAttributedReason:
+			attributed = append(attributed, interval)
+			cause := strings.Split(interval.Message.Cause, ",")[0]
+			attributedByCause[cause] = append(attributedByCause[cause], interval)
+		case monitorapi.DisruptionUnattributedReason:
+			unattributed = append(unattributed, interval)
+		}
+	}
+
+	bs.AttributedDuration = metav1.Duration{Duration: attributed.Duration(1 * time.Second).Round(time.Second)}
+	bs.UnattributedDuration = metav1.Duration{Duration: unattributed.Duration(1 * time.Second).Round(time.Second)}
+	for cause, intervals := range attributedByCause {
+		if bs.AttributedDurationByCause == nil {
+			bs.AttributedDurationByCause = map[string]metav1.Duration{}
+		}
+		bs.AttributedDurationByCause[cause] = metav1.Duration{Duration: intervals.Duration(1 * time.Second).Round(time.Second)}
+	}
+}
@@ -454,3 +454,44 @@ func TestComputeDisruptionData(t *testing.T) {
 		})
 	}
 }
+
+func TestComputeDisruptionData_attribution(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	locator := monitorapi.NewLocator().LocateDisruptionCheck("kube-api-new-connections", "kube-api", monitorapi.NewConnectionType)
+	disruption := func(from, to time.Duration) monitorapi.Interval {
+		return monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+			Locator(locator).
+			Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionBeganEventReason)).
+			Build(start.Add(from), start.Add(to))
+	}
+	rootCause := func(from, to time.Duration, cause string) monitorapi.Interval {
+		message := monitorapi.NewMessage().Reason(monitorapi.DisruptionUnattributedReason)
+		if len(cause) > 0 {
+			message = monitorapi.NewMessage().Reason(monitorapi.DisruptionAttributedReason).Cause(cause)
+		}
+		return monitorapi.NewInterval(monitorapi.SourceDisruptionRootCause, monitorapi.Info).
+			Locator(locator).
+			Message(message).
+			Build(start.Add(from), start.Add(to))
+	}
+
+	disruptions := computeDisruptionData(monitorapi.Intervals{
+		disruption(0, 10*time.Second),
+		rootCause(0, 10*time.Second, "IncompleteAPIServerShutdown,NodeNotReady"),
+		disruption(time.Minute, time.Minute+5*time.Second),
+		rootCause(time.Minute, time.Minute+5*time.Second, "NodeNotReady"),
+		disruption(2*time.Minute, 2*time.Minute+3*time.Second),
+		rootCause(2*time.Minute, 2*time.Minute+3*time.Second, ""),
+	})
+	if !assert.Contains(t, disruptions.BackendDisruptions, "kube-api-new-connections") {
+		return
+	}
+	ad := disruptions.BackendDisruptions["kube-api-new-connections"]
+	assert.Equal(t, metav1.Duration{Duration: 18 * time.Second}, ad.DisruptedDuration)
+	assert.Equal(t, metav1.Duration{Duration: 15 * time.Second}, ad.AttributedDuration)
+	assert.Equal(t, metav1.Duration{Duration: 3 * time.Second}, ad.UnattributedDuration)
+	assert.Equal(t, map[string]metav1.Duration{
+		"IncompleteAPIServerShutdown": {Duration: 10 * time.Second},
+		"NodeNotReady":                {Duration: 5 * time.Second},
+	}, ad.AttributedDurationByCause)
+}
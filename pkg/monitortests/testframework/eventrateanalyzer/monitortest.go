@@ -0,0 +1,107 @@
+package eventrateanalyzer
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"k8s.io/client-go/rest"
+)
+
+// event_rate_baseline.json is built by aggregating the event-rates_*.json files written by previous runs.  It is
+// empty until enough runs have written them, so for now every series is compared to the quiet periods of its own run.
+//
+//go:embed event_rate_baseline.json
+var eventRateBaseline []byte
+
+const testName = "[sig-arch] events should not burst above their baseline rate"
+
+type eventRateAnalyzer struct {
+	historical map[eventRateKey]float64
+	rates      []EventRate
+}
+
+// NewAnalyzer models the rate of kube events per namespace, reason and involved object kind, and flags
+// statistically significant bursts. Unlike the pathological event tests this catches problems that never
+// push a single event over the repeat threshold.
+func NewAnalyzer() monitortestframework.MonitorTest {
+	return &eventRateAnalyzer{}
+}
+
+func (w *eventRateAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	historical, err := parseBaseline(eventRateBaseline)
+	if err != nil {
+		return err
+	}
+	w.historical = historical
+	return nil
+}
+
+func (w *eventRateAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	return nil, nil, nil
+}
+
+func (w *eventRateAnalyzer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	series := buildSeries(startingIntervals, beginning, end)
+	w.rates = seriesToRates(series, beginning, end)
+	return burstsToIntervals(findBursts(series, w.historical, beginning)), nil
+}
+
+func (*eventRateAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	bursts := finalIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceEventRateAnomaly
+	})
+	if len(bursts) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: testName}}, nil
+	}
+
+	messages := []string{}
+	for _, b := range bursts {
+		messages = append(messages, b.String())
+	}
+	return []*junitapi.JUnitTestCase{
+		{
+			Name: testName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("%d event bursts found:\n\n%s", len(bursts), strings.Join(messages, "\n")),
+			},
+		},
+		// Without baseline rates a burst is only compared to the rest of the run, so it flakes instead of failing.
+		{Name: testName},
+	}, nil
+}
+
+func (w *eventRateAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	if w.rates == nil {
+		return nil
+	}
+	jsonContent, err := json.MarshalIndent(w.rates, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(storageDir, fmt.Sprintf("event-rates%s.json", timeSuffix)), jsonContent, 0644)
+}
+
+func (*eventRateAnalyzer) Cleanup(ctx context.Context) error {
+	return nil
+}
+
+func parseBaseline(data []byte) (map[eventRateKey]float64, error) {
+	rates := []EventRate{}
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return nil, fmt.Errorf("unable to parse event rate baseline: %w", err)
+	}
+	ret := map[eventRateKey]float64{}
+	for _, rate := range rates {
+		ret[rate.key()] = rate.EventsPerMinute
+	}
+	return ret, nil
+}
@@ -0,0 +1,291 @@
+package eventrateanalyzer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/pathologicaleventlibrary"
+)
+
+const (
+	// windowSize is the width of the buckets events are counted in.
+	windowSize = 5 * time.Minute
+
+	// minimumBurstSize avoids flagging a handful of events in an otherwise silent series.
+	minimumBurstSize = 10
+
+	// minimumWindowRate is the floor for the expected number of events per window. Series that are silent
+	// most of the run would otherwise have an expected rate of zero and any event would look significant.
+	minimumWindowRate = 0.5
+
+	// significance is the probability, under the baseline rate, below which a window is considered a burst.
+	significance = 1e-4
+
+	// firstTimestampAnnotation is recorded by the event watcher on every kube event interval.
+	firstTimestampAnnotation monitorapi.AnnotationKey = "firstTimestamp"
+)
+
+// eventRateKey identifies a series of kube events. Individual events are too fine-grained to model (each
+// pod gets its own), so events are grouped by what emits them and why.
+type eventRateKey struct {
+	Namespace string
+	Kind      string
+	Reason    string
+}
+
+func (k eventRateKey) String() string {
+	return fmt.Sprintf("ns/%s kind/%s reason/%s", k.Namespace, k.Kind, k.Reason)
+}
+
+// EventRate is the observed rate of a series over a whole run. It is written to storage for every run, and
+// aggregated across runs it is the format of the historical baseline.
+type EventRate struct {
+	Namespace       string  `json:"namespace"`
+	Kind            string  `json:"kind"`
+	Reason          string  `json:"reason"`
+	Count           int     `json:"count"`
+	EventsPerMinute float64 `json:"eventsPerMinute"`
+}
+
+// key lowercases the kind so that baseline rates match the kinds returned by involvedObjectKind however they were
+// written.
+func (r EventRate) key() eventRateKey {
+	return eventRateKey{Namespace: r.Namespace, Kind: strings.ToLower(r.Kind), Reason: r.Reason}
+}
+
+// eventSeries holds the number of events seen in each window of the run.
+type eventSeries struct {
+	key     eventRateKey
+	windows []int
+}
+
+func (s *eventSeries) total() int {
+	total := 0
+	for _, c := range s.windows {
+		total += c
+	}
+	return total
+}
+
+// burst is a run of consecutive windows whose event counts were unlikely under the baseline rate.
+type burst struct {
+	key eventRateKey
+	// from and to bound the windows making up the burst.
+	from, to time.Time
+	count    int
+	expected float64
+	// probability is the smallest per-window probability of seeing at least that many events.
+	probability float64
+	// baselineSource describes where the expected rate came from.
+	baselineSource string
+}
+
+// involvedObjectKind recovers the kind of the object an event was about from the locator built by
+// LocatorBuilder.KubeEvent.  That locator keys the object name by its lowercased kind next to the namespace, node and
+// hmsg keys, so every kind is returned lowercased, including the node, pod and namespace kinds the locator does not
+// key by kind.  Should a locator ever carry more than one other key, the first in sorted order wins so the same event
+// is always counted under the same kind.
+func involvedObjectKind(locator monitorapi.Locator) string {
+	switch {
+	case locator.Type == monitorapi.LocatorTypeNode:
+		return "node"
+	case locator.Type == monitorapi.LocatorTypePod || locator.HasKey(monitorapi.LocatorPodKey):
+		return "pod"
+	}
+	kinds := []string{}
+	for k := range locator.Keys {
+		switch k {
+		case monitorapi.LocatorNamespaceKey, monitorapi.LocatorNodeKey, monitorapi.LocatorHmsgKey:
+			continue
+		}
+		kinds = append(kinds, string(k))
+	}
+	if len(kinds) == 0 {
+		return "namespace"
+	}
+	sort.Strings(kinds)
+	return kinds[0]
+}
+
+// buildSeries converts kube event intervals into per-key event counts per window. Kube events are
+// recorded each time their count changes, so the number of occurrences attributed to an interval is the
+// difference from the previous interval for the same event.
+func buildSeries(intervals monitorapi.Intervals, beginning, end time.Time) map[eventRateKey]*eventSeries {
+	numWindows := int(end.Sub(beginning)/windowSize) + 1
+
+	byEvent := map[string]monitorapi.Intervals{}
+	for _, interval := range intervals {
+		if interval.Source != monitorapi.SourceKubeEvent {
+			continue
+		}
+		if monitorapi.IsInE2ENamespace(interval) {
+			continue
+		}
+		id := fmt.Sprintf("%s %s %s", interval.Locator.OldLocator(), interval.Message.Reason, interval.Message.HumanMessage)
+		byEvent[id] = append(byEvent[id], interval)
+	}
+
+	series := map[eventRateKey]*eventSeries{}
+	for _, eventIntervals := range byEvent {
+		sort.SliceStable(eventIntervals, func(i, j int) bool {
+			return eventIntervals[i].From.Before(eventIntervals[j].From)
+		})
+		previousCount := 0
+		for _, interval := range eventIntervals {
+			count := pathologicaleventlibrary.GetTimesAnEventHappened(interval.Message)
+			occurrences := count - previousCount
+			switch {
+			case previousCount == 0 && count > 1 && startedBefore(interval, beginning):
+				// the earlier occurrences happened before we started watching.
+				occurrences = 1
+			case occurrences < 0:
+				// the event was deleted and recreated, start counting again.
+				occurrences = count
+			}
+			previousCount = count
+			if occurrences <= 0 || interval.From.Before(beginning) || interval.From.After(end) {
+				continue
+			}
+
+			key := eventRateKey{
+				Namespace: interval.Locator.Keys[monitorapi.LocatorNamespaceKey],
+				Kind:      involvedObjectKind(interval.Locator),
+				Reason:    string(interval.Message.Reason),
+			}
+			if _, ok := series[key]; !ok {
+				series[key] = &eventSeries{key: key, windows: make([]int, numWindows)}
+			}
+			series[key].windows[int(interval.From.Sub(beginning)/windowSize)] += occurrences
+		}
+	}
+	return series
+}
+
+func startedBefore(interval monitorapi.Interval, beginning time.Time) bool {
+	firstTimestamp, err := time.Parse(time.RFC3339, interval.Message.Annotations[firstTimestampAnnotation])
+	if err != nil {
+		return false
+	}
+	return firstTimestamp.Before(beginning)
+}
+
+// expectedWindowRate returns the number of events we expect per window for the series. A historical rate
+// is preferred, otherwise the median window of the run is used as the series' quiet rate.
+func expectedWindowRate(series *eventSeries, historical map[eventRateKey]float64) (float64, string) {
+	if perMinute, ok := historical[series.key]; ok {
+		return math.Max(perMinute*windowSize.Minutes(), minimumWindowRate), "historical"
+	}
+
+	sorted := append([]int{}, series.windows...)
+	sort.Ints(sorted)
+	median := float64(sorted[len(sorted)/2])
+	if len(sorted)%2 == 0 {
+		median = float64(sorted[len(sorted)/2-1]+sorted[len(sorted)/2]) / 2
+	}
+	return math.Max(median, minimumWindowRate), "quiet periods of this run"
+}
+
+// findBursts flags windows whose counts are statistically unlikely under a Poisson model of the expected
+// rate, merging consecutive flagged windows.
+func findBursts(allSeries map[eventRateKey]*eventSeries, historical map[eventRateKey]float64, beginning time.Time) []burst {
+	ret := []burst{}
+	for _, series := range allSeries {
+		expected, baselineSource := expectedWindowRate(series, historical)
+
+		var current *burst
+		for i, count := range series.windows {
+			probability := poissonTail(count, expected)
+			if count < minimumBurstSize || probability >= significance {
+				if current != nil {
+					ret = append(ret, *current)
+					current = nil
+				}
+				continue
+			}
+
+			windowStart := beginning.Add(time.Duration(i) * windowSize)
+			if current == nil {
+				current = &burst{
+					key:            series.key,
+					from:           windowStart,
+					probability:    probability,
+					baselineSource: baselineSource,
+				}
+			}
+			current.to = windowStart.Add(windowSize)
+			current.count += count
+			current.expected += expected
+			current.probability = math.Min(current.probability, probability)
+		}
+		if current != nil {
+			ret = append(ret, *current)
+		}
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		if !ret[i].from.Equal(ret[j].from) {
+			return ret[i].from.Before(ret[j].from)
+		}
+		return ret[i].key.String() < ret[j].key.String()
+	})
+	return ret
+}
+
+// poissonTail returns P(X >= k) for X ~ Poisson(lambda).
+func poissonTail(k int, lambda float64) float64 {
+	if float64(k) <= lambda {
+		return 1
+	}
+	total := 0.0
+	for i := k; ; i++ {
+		logFactorial, _ := math.Lgamma(float64(i + 1))
+		term := math.Exp(float64(i)*math.Log(lambda) - lambda - logFactorial)
+		total += term
+		// terms shrink geometrically past lambda, stop once they no longer matter.
+		if term <= total*1e-12 {
+			break
+		}
+	}
+	return math.Min(total, 1)
+}
+
+func burstsToIntervals(bursts []burst) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	for _, b := range bursts {
+		ret = append(ret, monitorapi.NewInterval(monitorapi.SourceEventRateAnomaly, monitorapi.Warning).
+			Locator(monitorapi.NewLocator().EventRate(b.key.Namespace, b.key.Kind, b.key.Reason)).
+			Message(monitorapi.NewMessage().
+				Reason(monitorapi.EventRateAnomalyReason).
+				WithAnnotation(monitorapi.AnnotationCount, fmt.Sprintf("%d", b.count)).
+				HumanMessagef("%d %s events for %s objects in %s, expected %.1f based on %s (p=%.2g)",
+					b.count, b.key.Reason, b.key.Kind, b.to.Sub(b.from), b.expected, b.baselineSource, b.probability)).
+			Display().
+			Build(b.from, b.to))
+	}
+	return ret
+}
+
+func seriesToRates(allSeries map[eventRateKey]*eventSeries, beginning, end time.Time) []EventRate {
+	minutes := end.Sub(beginning).Minutes()
+	ret := []EventRate{}
+	for _, series := range allSeries {
+		rate := EventRate{
+			Namespace: series.key.Namespace,
+			Kind:      series.key.Kind,
+			Reason:    series.key.Reason,
+			Count:     series.total(),
+		}
+		if minutes > 0 {
+			rate.EventsPerMinute = float64(rate.Count) / minutes
+		}
+		ret = append(ret, rate)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return strings.Compare(ret[i].key().String(), ret[j].key().String()) < 0
+	})
+	return ret
+}
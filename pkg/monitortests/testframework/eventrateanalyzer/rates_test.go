@@ -0,0 +1,146 @@
+package eventrateanalyzer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func kubeEvent(namespace, pod, reason string, count int, at time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceKubeEvent, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().PodFromNames(namespace, pod, "")).
+		Message(monitorapi.NewMessage().
+			Reason(monitorapi.IntervalReason(reason)).
+			HumanMessage("Back-off restarting failed container").
+			WithAnnotation(monitorapi.AnnotationCount, fmt.Sprintf("%d", count))).
+		Build(at, at.Add(time.Second))
+}
+
+func TestPoissonTail(t *testing.T) {
+	assert.Equal(t, 1.0, poissonTail(0, 2))
+	assert.Equal(t, 1.0, poissonTail(2, 2))
+	// P(X >= 3 | 1) = 1 - e^-1(1 + 1 + 1/2)
+	assert.InDelta(t, 0.0803, poissonTail(3, 1), 0.0001)
+	assert.Less(t, poissonTail(20, 0.5), 1e-20)
+}
+
+func TestInvolvedObjectKind(t *testing.T) {
+	deployment := monitorapi.Locator{
+		Type: monitorapi.LocatorTypeKind,
+		Keys: map[monitorapi.LocatorKey]string{
+			monitorapi.LocatorNamespaceKey: "openshift-foo",
+			monitorapi.LocatorHmsgKey:      "0123456789",
+			"deployment":                   "foo",
+		},
+	}
+	assert.Equal(t, "deployment", involvedObjectKind(deployment))
+
+	deployment.Keys["apiservice"] = "v1.foo"
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, "apiservice", involvedObjectKind(deployment))
+	}
+
+	namespace := monitorapi.Locator{Keys: map[monitorapi.LocatorKey]string{monitorapi.LocatorNamespaceKey: "openshift-foo"}}
+	assert.Equal(t, "namespace", involvedObjectKind(namespace))
+
+	pod := monitorapi.Locator{Type: monitorapi.LocatorTypePod, Keys: map[monitorapi.LocatorKey]string{monitorapi.LocatorPodKey: "foo"}}
+	baseline := EventRate{Namespace: "openshift-foo", Kind: "Pod", Reason: "BackOff"}
+	assert.Equal(t, involvedObjectKind(pod), baseline.key().Kind, "baseline kinds must match however they are capitalized")
+}
+
+func TestBuildSeries(t *testing.T) {
+	beginning := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := beginning.Add(30 * time.Minute)
+
+	intervals := monitorapi.Intervals{
+		kubeEvent("openshift-foo", "foo-1", "BackOff", 1, beginning.Add(time.Minute)),
+		kubeEvent("openshift-foo", "foo-1", "BackOff", 4, beginning.Add(6*time.Minute)),
+		// unchanged count, nothing new happened.
+		kubeEvent("openshift-foo", "foo-1", "BackOff", 4, beginning.Add(7*time.Minute)),
+		kubeEvent("openshift-foo", "foo-2", "BackOff", 2, beginning.Add(8*time.Minute)),
+		// e2e namespaces are ignored.
+		kubeEvent("e2e-test-foo", "foo-1", "BackOff", 100, beginning.Add(8*time.Minute)),
+		// outside the run.
+		kubeEvent("openshift-foo", "foo-3", "BackOff", 1, end.Add(time.Minute)),
+	}
+
+	series := buildSeries(intervals, beginning, end)
+	require.Len(t, series, 1)
+	s := series[eventRateKey{Namespace: "openshift-foo", Kind: "pod", Reason: "BackOff"}]
+	require.NotNil(t, s)
+	assert.Equal(t, []int{1, 5, 0, 0, 0, 0, 0}, s.windows)
+}
+
+func TestFindBursts(t *testing.T) {
+	beginning := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	key := eventRateKey{Namespace: "openshift-foo", Kind: "pod", Reason: "BackOff"}
+
+	tests := []struct {
+		name          string
+		windows       []int
+		historical    map[eventRateKey]float64
+		expectedCount []int
+	}{
+		{
+			name:    "steady noise is not a burst",
+			windows: []int{30, 28, 31, 29, 30, 32, 30},
+		},
+		{
+			name:          "burst over quiet periods",
+			windows:       []int{1, 0, 2, 40, 35, 1, 0},
+			expectedCount: []int{75},
+		},
+		{
+			name:    "small burst under minimum size",
+			windows: []int{0, 0, 0, 8, 0, 0, 0},
+		},
+		{
+			name:          "steady rate over historical baseline",
+			windows:       []int{30, 28, 31, 29, 30, 32, 30},
+			historical:    map[eventRateKey]float64{key: 0.2},
+			expectedCount: []int{210},
+		},
+		{
+			name:          "separate bursts",
+			windows:       []int{0, 20, 0, 0, 0, 25, 0},
+			expectedCount: []int{20, 25},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := map[eventRateKey]*eventSeries{key: {key: key, windows: tt.windows}}
+			bursts := findBursts(series, tt.historical, beginning)
+			counts := []int{}
+			for _, b := range bursts {
+				counts = append(counts, b.count)
+			}
+			if len(tt.expectedCount) == 0 {
+				assert.Empty(t, counts)
+				return
+			}
+			assert.Equal(t, tt.expectedCount, counts)
+		})
+	}
+}
+
+func TestBurstsToIntervals(t *testing.T) {
+	beginning := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	intervals := burstsToIntervals([]burst{{
+		key:            eventRateKey{Namespace: "openshift-foo", Kind: "pod", Reason: "BackOff"},
+		from:           beginning,
+		to:             beginning.Add(windowSize),
+		count:          40,
+		expected:       0.5,
+		probability:    1e-30,
+		baselineSource: "quiet periods of this run",
+	}})
+	require.Len(t, intervals, 1)
+	assert.Equal(t, monitorapi.Warning, intervals[0].Level)
+	assert.Equal(t, "openshift-foo", intervals[0].Locator.Keys[monitorapi.LocatorNamespaceKey])
+	assert.Equal(t, "BackOff", intervals[0].Locator.Keys[monitorapi.LocatorReasonKey])
+	assert.Equal(t, "40", intervals[0].Message.Annotations[monitorapi.AnnotationCount])
+}
@@ -0,0 +1,168 @@
+package logruleintervals
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/logrules"
+	"github.com/openshift/origin/pkg/monitortestlibrary/podaccess"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	exutil "github.com/openshift/origin/test/extended/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+type logRuleIntervals struct {
+	rulesFile string
+
+	rules              []logrules.Rule
+	kubeClient         kubernetes.Interface
+	stopCollection     context.CancelFunc
+	podLogHandlers     []*logrules.PodLogHandler
+	finishedCollecting []chan struct{}
+}
+
+// NewLogRuleIntervals turns the lines of node journals and pod logs that match the rules of a --log-rules file into
+// intervals.
+func NewLogRuleIntervals(rulesFile string) monitortestframework.MonitorTest {
+	return &logRuleIntervals{
+		rulesFile: rulesFile,
+	}
+}
+
+func (w *logRuleIntervals) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	rules, err := logrules.RulesFromFile(w.rulesFile)
+	if err != nil {
+		return err
+	}
+	w.rules = rules
+
+	w.kubeClient, err = kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+
+	ctx, w.stopCollection = context.WithCancel(ctx)
+	sources, rulesBySource := logrules.PodSources(rules)
+	for _, source := range sources {
+		selector, err := labels.Parse(source.LabelSelector)
+		if err != nil {
+			return err
+		}
+		kubeInformers := informers.NewSharedInformerFactory(w.kubeClient, 0)
+		namespaceScopedCoreInformers := coreinformers.New(kubeInformers, source.Namespace, nil)
+
+		podLogHandler := logrules.NewPodLogHandler(recorder, rulesBySource[source])
+		finishedCollecting := make(chan struct{})
+		podStreamer := podaccess.NewPodsStreamer(
+			w.kubeClient,
+			selector,
+			source.Namespace,
+			source.Container,
+			podLogHandler,
+			namespaceScopedCoreInformers.Pods(),
+		)
+		w.podLogHandlers = append(w.podLogHandlers, podLogHandler)
+		w.finishedCollecting = append(w.finishedCollecting, finishedCollecting)
+
+		go kubeInformers.Start(ctx.Done())
+		go podStreamer.Run(ctx, finishedCollecting)
+	}
+
+	return nil
+}
+
+func (w *logRuleIntervals) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.stopCollection != nil {
+		w.stopCollection()
+	}
+	// wait until we're drained, then end the pairs that never ended
+	for i, finishedCollecting := range w.finishedCollecting {
+		<-finishedCollecting
+		w.podLogHandlers[i].Close(end)
+	}
+
+	units := logrules.SystemdUnits(w.rules)
+	if len(units) == 0 || w.kubeClient == nil {
+		return nil, nil, nil
+	}
+	// MicroShift does not have a proper journal for the node logs api.
+	isMicroShift, err := exutil.IsMicroShiftCluster(w.kubeClient)
+	if err != nil {
+		return nil, nil, err
+	}
+	if isMicroShift {
+		return nil, nil, nil
+	}
+
+	intervals, err := w.intervalsFromNodeLogs(ctx, units)
+	return intervals, nil, err
+}
+
+func (w *logRuleIntervals) intervalsFromNodeLogs(ctx context.Context, units []string) (monitorapi.Intervals, error) {
+	ret := monitorapi.Intervals{}
+
+	allNodes, err := w.kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return ret, err
+	}
+
+	lock := sync.Mutex{}
+	errCh := make(chan error, len(allNodes.Items)*len(units))
+	wg := sync.WaitGroup{}
+	for _, node := range allNodes.Items {
+		for _, unit := range units {
+			wg.Add(1)
+			go func(ctx context.Context, nodeName, unit string) {
+				defer wg.Done()
+
+				journal, err := logrules.GetNodeLog(ctx, w.kubeClient, nodeName, unit)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error getting node %s logs from %s: %s", unit, nodeName, err.Error())
+					errCh <- err
+					return
+				}
+				intervals := logrules.IntervalsFromJournal(w.rules, unit, nodeName, journal)
+
+				lock.Lock()
+				defer lock.Unlock()
+				ret = append(ret, intervals...)
+			}(ctx, node.Name, unit)
+		}
+	}
+	wg.Wait()
+
+	errs := []error{}
+	for len(errCh) > 0 {
+		err := <-errCh
+		errs = append(errs, err)
+	}
+
+	return ret, utilerrors.NewAggregate(errs)
+}
+
+func (*logRuleIntervals) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, nil
+}
+
+func (*logRuleIntervals) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return nil, nil
+}
+
+func (*logRuleIntervals) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func (*logRuleIntervals) Cleanup(ctx context.Context) error {
+	return nil
+}
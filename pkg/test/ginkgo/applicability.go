@@ -0,0 +1,122 @@
+package ginkgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// TestApplicabilityFunc returns why a test does not apply to the cluster, or an empty string if it does.
+type TestApplicabilityFunc func(name string) string
+
+type testApplicabilityCheck struct {
+	name  string
+	check TestApplicabilityFunc
+}
+
+// AddApplicabilityCheck adds a check of whether tests apply to the cluster, for instance whether the apigroups a test
+// needs are served.  Unlike a TestMatchFunc, tests that do not apply are reported as skipped with the name of the check
+// and the reason, so that they can be told apart from tests that are not part of the suite.
+func (s *TestSuite) AddApplicabilityCheck(name string, check TestApplicabilityFunc) {
+	if check == nil {
+		return
+	}
+	s.applicabilityChecks = append(s.applicabilityChecks, testApplicabilityCheck{name: name, check: check})
+}
+
+// skipInapplicable returns the tests every check applies to and the others, marked as skipped with the first reason
+// found.
+func skipInapplicable(tests []*testCase, checks []testApplicabilityCheck) (applicable, inapplicable []*testCase) {
+	applicable = make([]*testCase, 0, len(tests))
+	for _, test := range tests {
+		skipped := false
+		for _, check := range checks {
+			if check.check == nil {
+				continue
+			}
+			if reason := check.check(test.name); len(reason) > 0 {
+				test.skipped = true
+				test.skipReason = &testSkipReason{Check: check.name, Reason: reason}
+				test.testOutputBytes = []byte(fmt.Sprintf("skip [%s]: %s", check.name, reason))
+				inapplicable = append(inapplicable, test)
+				skipped = true
+				break
+			}
+		}
+		if !skipped {
+			applicable = append(applicable, test)
+		}
+	}
+	return applicable, inapplicable
+}
+
+type testSkipReason struct {
+	Check  string `json:"check"`
+	Reason string `json:"reason"`
+}
+
+// skippedTestsLimitation is the part of a cluster the applicability checks cannot see.  Optional operators are only
+// known to be disabled through their ClusterVersion capability, there is no label tying a test to a ClusterOperator or
+// an OLM operator.
+const skippedTestsLimitation = "tests of optional operators are only skipped when their ClusterVersion capability is disabled, tests of operators turned off in other ways, like through ClusterOperator or OLM configuration, still run"
+
+// SkippedTestsSummary is written to skipped-tests_<timestamp>.json in the junit directory.
+type SkippedTestsSummary struct {
+	Suite string `json:"suite"`
+	// Limitation describes the tests that do not apply to the cluster but cannot be detected, and so are not skipped.
+	Limitation string `json:"limitation"`
+	// CountsByCheck is the number of tests each applicability check skipped.
+	CountsByCheck map[string]int `json:"countsByCheck"`
+	Tests         []SkippedTest  `json:"tests"`
+}
+
+type SkippedTest struct {
+	Name   string `json:"name"`
+	Check  string `json:"check"`
+	Reason string `json:"reason"`
+}
+
+func newSkippedTestsSummary(suiteName string, inapplicable []*testCase) *SkippedTestsSummary {
+	summary := &SkippedTestsSummary{
+		Suite:         suiteName,
+		Limitation:    skippedTestsLimitation,
+		CountsByCheck: map[string]int{},
+		Tests:         []SkippedTest{},
+	}
+	for _, test := range inapplicable {
+		summary.CountsByCheck[test.skipReason.Check]++
+		summary.Tests = append(summary.Tests, SkippedTest{
+			Name:   test.name,
+			Check:  test.skipReason.Check,
+			Reason: test.skipReason.Reason,
+		})
+	}
+	sort.Slice(summary.Tests, func(i, j int) bool { return summary.Tests[i].Name < summary.Tests[j].Name })
+	return summary
+}
+
+// printCounts writes the number of tests skipped by each check, and what the checks cannot detect.
+func (s *SkippedTestsSummary) printCounts(out io.Writer) {
+	var checks []string
+	for check := range s.CountsByCheck {
+		checks = append(checks, check)
+	}
+	sort.Strings(checks)
+	for _, check := range checks {
+		fmt.Fprintf(out, "skipped %d tests that do not apply to the cluster: %s\n", s.CountsByCheck[check], check)
+	}
+	if len(checks) > 0 {
+		fmt.Fprintf(out, "note: %s\n", s.Limitation)
+	}
+}
+
+func writeSkippedTestsSummary(summary *SkippedTestsSummary, junitDir, timeSuffix string) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(junitDir, fmt.Sprintf("skipped-tests%s.json", timeSuffix)), data, 0644)
+}
@@ -0,0 +1,58 @@
+package ginkgo
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_skipInapplicable(t *testing.T) {
+	checks := []testApplicabilityCheck{
+		{name: "apigroup", check: func(name string) string {
+			if strings.Contains(name, "[apigroup:route.openshift.io]") {
+				return "apigroups route.openshift.io are not served by the cluster"
+			}
+			return ""
+		}},
+		{name: "rebase"},
+		{name: "cluster", check: func(name string) string {
+			if strings.Contains(name, "[Skipped:aws]") {
+				return "platform is aws and the test is labeled [Skipped:aws]"
+			}
+			return ""
+		}},
+	}
+	tests := []*testCase{
+		{name: "applies"},
+		{name: "routes [apigroup:route.openshift.io] [Skipped:aws]"},
+		{name: "not on aws [Skipped:aws]"},
+	}
+
+	applicable, inapplicable := skipInapplicable(tests, checks)
+	if len(applicable) != 1 || applicable[0].name != "applies" || applicable[0].skipped {
+		t.Fatalf("unexpected applicable tests: %v", testNames(applicable))
+	}
+	if len(inapplicable) != 2 {
+		t.Fatalf("unexpected inapplicable tests: %v", testNames(inapplicable))
+	}
+	if got := inapplicable[0].skipReason.Check; got != "apigroup" {
+		t.Errorf("the first failing check should be reported, got %s", got)
+	}
+	if got, want := lastLinesUntil(string(inapplicable[1].testOutputBytes), 100, "skip ["), "skip [cluster]: platform is aws and the test is labeled [Skipped:aws]"; got != want {
+		t.Errorf("junit skip message = %q, want %q", got, want)
+	}
+
+	summary := newSkippedTestsSummary("suite", inapplicable)
+	if summary.CountsByCheck["apigroup"] != 1 || summary.CountsByCheck["cluster"] != 1 {
+		t.Errorf("unexpected counts: %v", summary.CountsByCheck)
+	}
+	if summary.Tests[0].Name != "not on aws [Skipped:aws]" || summary.Tests[0].Check != "cluster" {
+		t.Errorf("unexpected first skipped test: %#v", summary.Tests[0])
+	}
+
+	out := &bytes.Buffer{}
+	summary.printCounts(out)
+	if !strings.Contains(out.String(), "skipped 1 tests that do not apply to the cluster: apigroup\n") || !strings.Contains(out.String(), "note: "+skippedTestsLimitation) {
+		t.Errorf("unexpected counts output:\n%s", out.String())
+	}
+}
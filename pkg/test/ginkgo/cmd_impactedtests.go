@@ -0,0 +1,84 @@
+package ginkgo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// ImpactedTestsOptions selects the tests affected by a set of changed files or packages.
+type ImpactedTestsOptions struct {
+	// RepoRoot is the root of the repository the changed paths are relative to.
+	RepoRoot string
+	// Packages are the package patterns, relative to RepoRoot, whose import graph is used.
+	Packages []string
+	// RulesFile holds ImpactRules, DefaultImpactRules are used when it is empty.
+	RulesFile string
+
+	genericclioptions.IOStreams
+}
+
+func NewImpactedTestsOptions(streams genericclioptions.IOStreams) *ImpactedTestsOptions {
+	return &ImpactedTestsOptions{
+		RepoRoot:  ".",
+		Packages:  []string{"./test/extended/..."},
+		IOStreams: streams,
+	}
+}
+
+func (o *ImpactedTestsOptions) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&o.RepoRoot, "repo-root", o.RepoRoot, "The root of the repository changed paths are relative to.")
+	flags.StringSliceVar(&o.Packages, "packages", o.Packages, "Package patterns, relative to --repo-root, whose import graph maps changes to tests.")
+	flags.StringVar(&o.RulesFile, "rules", o.RulesFile, "A YAML file with runAll and ignore path lists. Defaults to treating changes to shared helpers like test/extended/util as affecting every test.")
+}
+
+// Run writes the names of the impacted tests to Out in the format accepted by run --file.  Changes are files,
+// directories or import paths, "-" reads them from in one per line.
+func (o *ImpactedTestsOptions) Run(changes []string, in io.Reader) error {
+	if len(changes) == 1 && changes[0] == "-" {
+		changes = nil
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); len(line) > 0 {
+				changes = append(changes, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+	if len(changes) == 0 {
+		return fmt.Errorf("at least one changed file or package is required")
+	}
+
+	rules := DefaultImpactRules()
+	if len(o.RulesFile) > 0 {
+		var err error
+		if rules, err = ImpactRulesFromFile(o.RulesFile); err != nil {
+			return err
+		}
+	}
+
+	graph, err := loadImportGraph(o.RepoRoot, o.Packages)
+	if err != nil {
+		return err
+	}
+
+	ginkgo.GetSuite().ClearBeforeAndAfterSuiteNodes()
+	tests, err := testsForSuite()
+	if err != nil {
+		return err
+	}
+
+	impacted := impactedTests(tests, graph, rules, changes, o.ErrOut)
+	fmt.Fprintf(o.ErrOut, "%d of %d tests are impacted\n", len(impacted), len(tests))
+	for _, test := range impacted {
+		fmt.Fprintf(o.Out, "%q\n", test.name)
+	}
+	return nil
+}
@@ -24,8 +24,11 @@ import (
 	"github.com/openshift/origin/pkg/monitor"
 	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
 	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/logrules"
+	"github.com/openshift/origin/pkg/monitortests/testframework/disruptioncustombackends"
 	"github.com/openshift/origin/pkg/riskanalysis"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"github.com/openshift/origin/pkg/version"
 	"github.com/spf13/pflag"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -59,6 +62,9 @@ type GinkgoRunSuiteOptions struct {
 
 	IncludeSuccessOutput bool
 
+	// MaxFailureArtifactTests is the number of failed tests whose namespaces are captured to the junit directory.
+	MaxFailureArtifactTests int
+
 	CommandEnv []string
 
 	DryRun        bool
@@ -67,15 +73,31 @@ type GinkgoRunSuiteOptions struct {
 
 	FromRepository string
 
+	// ReplayManifest, when set, runs the tests of a previous run in the same order and batches instead of selecting
+	// them from the suite.
+	ReplayManifest *RunManifest
+
 	StartTime time.Time
 
 	ExactMonitorTests   []string
 	DisableMonitorTests []string
+	// DisruptionBackendsFile defines additional backends the custom-backend-availability monitor test samples.
+	DisruptionBackendsFile string
+	// PodNetworkNodeMatrix runs the pod network disruption pollers on every node and reports disruption per node pair.
+	PodNetworkNodeMatrix bool
+	// LogRulesFile defines additional rules the log-rule-intervals monitor test turns log lines into intervals with.
+	LogRulesFile string
+	// StreamAuditLogs tails the audit logs during the run instead of downloading them at the end.
+	StreamAuditLogs bool
+	// CertificateExpiryWarningDays is how many days before expiry the certificate-rotation-monitor reports a certificate.
+	CertificateExpiryWarningDays int
 }
 
 func NewGinkgoRunSuiteOptions(streams genericclioptions.IOStreams) *GinkgoRunSuiteOptions {
 	return &GinkgoRunSuiteOptions{
-		IOStreams: streams,
+		IOStreams:                    streams,
+		MaxFailureArtifactTests:      50,
+		CertificateExpiryWarningDays: 7,
 	}
 }
 
@@ -92,9 +114,15 @@ func (o *GinkgoRunSuiteOptions) BindFlags(flags *pflag.FlagSet) {
 	flags.DurationVar(&o.Timeout, "timeout", o.Timeout, "Set the maximum time a test can run before being aborted. This is read from the suite by default, but will be 10 minutes otherwise.")
 	flags.BoolVar(&o.IncludeSuccessOutput, "include-success", o.IncludeSuccessOutput, "Print output from successful tests.")
 	flags.IntVar(&o.Parallelism, "max-parallel-tests", o.Parallelism, "Maximum number of tests running in parallel. 0 defaults to test suite recommended value, which is different in each suite.")
+	flags.IntVar(&o.MaxFailureArtifactTests, "max-failure-artifact-tests", o.MaxFailureArtifactTests, "Capture the pods, events, logs and other resources in the namespaces of at most this many failed tests to <junit-dir>/tests. 0 disables the capture.")
 	flags.StringSliceVar(&o.ExactMonitorTests, "monitor", o.ExactMonitorTests,
 		fmt.Sprintf("list of exactly which monitors to enable. All others will be disabled.  Current monitors are: [%s]", strings.Join(monitorNames, ", ")))
 	flags.StringSliceVar(&o.DisableMonitorTests, "disable-monitor", o.DisableMonitorTests, "list of monitors to disable.  Defaults for others will be honored.")
+	flags.StringVar(&o.DisruptionBackendsFile, "disruption-backends", o.DisruptionBackendsFile, "A YAML file of routes, services and URLs to sample for disruption alongside the built-in backends, see pkg/monitortests/testframework/disruptioncustombackends.")
+	flags.BoolVar(&o.PodNetworkNodeMatrix, "pod-network-node-matrix", o.PodNetworkNodeMatrix, "Run the pod network disruption pollers on every node, sampling up to ten other nodes each, and write a node-by-node heatmap of disruption.")
+	flags.BoolVar(&o.StreamAuditLogs, "stream-audit-logs", o.StreamAuditLogs, "Tail the audit logs of the control plane nodes during the run instead of downloading them at the end, reporting the time ranges that could not be read.")
+	flags.IntVar(&o.CertificateExpiryWarningDays, "certificate-expiry-warning-days", o.CertificateExpiryWarningDays, "Report platform certificates that are within this many days of expiring during the run.")
+	flags.StringVar(&o.LogRulesFile, "log-rules", o.LogRulesFile, "A YAML file of rules turning the lines of node journals and pod logs into intervals, see pkg/monitortestlibrary/logrules.")
 }
 
 func (o *GinkgoRunSuiteOptions) Validate() error {
@@ -103,16 +131,37 @@ func (o *GinkgoRunSuiteOptions) Validate() error {
 	default:
 		return fmt.Errorf("unknown --cluster-stability, %q, expected Stable or Disruptive", o.ClusterStabilityDuringTest)
 	}
+	if o.MaxFailureArtifactTests < 0 {
+		return fmt.Errorf("--max-failure-artifact-tests must not be negative")
+	}
+	if len(o.DisruptionBackendsFile) > 0 {
+		if _, err := disruptioncustombackends.BackendsFromFile(o.DisruptionBackendsFile); err != nil {
+			return fmt.Errorf("unable to load --disruption-backends: %w", err)
+		}
+	}
+	if len(o.LogRulesFile) > 0 {
+		if _, err := logrules.RulesFromFile(o.LogRulesFile); err != nil {
+			return fmt.Errorf("unable to load --log-rules: %w", err)
+		}
+	}
 	return nil
 }
 
 func (o *GinkgoRunSuiteOptions) AsEnv() []string {
 	var args []string
 	args = append(args, fmt.Sprintf("TEST_SUITE_START_TIME=%d", o.StartTime.Unix()))
+	if o.captureFailureArtifacts() {
+		// the namespaces of failed tests are captured and deleted by the run command
+		args = append(args, "DELETE_NAMESPACE_ON_FAILURE=false")
+	}
 	args = append(args, o.CommandEnv...)
 	return args
 }
 
+func (o *GinkgoRunSuiteOptions) captureFailureArtifacts() bool {
+	return len(o.JUnitDir) > 0 && o.MaxFailureArtifactTests > 0 && !o.DryRun && !o.PrintCommands
+}
+
 func (o *GinkgoRunSuiteOptions) SetIOStreams(streams genericclioptions.IOStreams) {
 	o.IOStreams = streams
 }
@@ -145,7 +194,7 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		strings.EqualFold(o.FromRepository, "quay.io/openshift/community-e2e-images") {
 		buf := &bytes.Buffer{}
 		fmt.Fprintf(buf, "Attempting to pull tests from external binary...\n")
-		externalTests, err := externalTestsForSuite(ctx)
+		externalTests, err := externalTestsForSuite(ctx, buf)
 		if err == nil {
 			filteredTests := []*testCase{}
 			for _, test := range tests {
@@ -187,20 +236,53 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	// this ensures the tests are always run in random order to avoid
 	// any intra-tests dependencies
 	suiteConfig, _ := ginkgo.GinkgoConfiguration()
-	r := rand.New(rand.NewSource(suiteConfig.RandomSeed))
+	seed := suiteConfig.RandomSeed
+	if o.ReplayManifest != nil {
+		seed = o.ReplayManifest.Seed
+	}
+	r := rand.New(rand.NewSource(seed))
 	r.Shuffle(len(tests), func(i, j int) { tests[i], tests[j] = tests[j], tests[i] })
 
-	tests = suite.Filter(tests)
+	var replayPlan *testPlan
+	var inapplicable []*testCase
+	if o.ReplayManifest != nil {
+		// the manifest holds the tests that were selected, so the suite filters are not applied again
+		if o.ReplayManifest.Version != version.Get().String() {
+			fmt.Fprintf(o.ErrOut, "warning: the manifest was written by version %s, this is version %s\n", o.ReplayManifest.Version, version.Get().String())
+		}
+		replayPlan, err = replayTestPlan(o.ReplayManifest, tests)
+		if err != nil {
+			return fmt.Errorf("unable to replay manifest: %w", err)
+		}
+		tests = nil
+		for _, batch := range replayPlan.batches() {
+			tests = append(tests, batch.tests...)
+		}
+		fmt.Fprintf(o.Out, "replaying %d tests in %d batches with seed %d\n", len(tests), len(o.ReplayManifest.Batches), seed)
+	} else {
+		if o.DryRun {
+			suite.ExplainFilter(tests, o.Out)
+		}
+		tests = suite.Filter(tests)
+		tests, inapplicable = skipInapplicable(tests, suite.applicabilityChecks)
+	}
 	if len(tests) == 0 {
 		return fmt.Errorf("suite %q does not contain any tests", suite.Name)
 	}
 
 	fmt.Fprintf(o.Out, "found %d filtered tests\n", len(tests))
+	newSkippedTestsSummary(suite.Name, inapplicable).printCounts(o.Out)
 
 	count := o.Count
 	if count == 0 {
 		count = suite.Count
 	}
+	if o.ReplayManifest != nil {
+		count, err = replayCount(o.ReplayManifest, o.Count)
+		if err != nil {
+			return err
+		}
+	}
 
 	start := time.Now()
 	if o.StartTime.IsZero() {
@@ -234,10 +316,12 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	}
 
 	// skip tests due to newer k8s
-	tests, err = o.filterOutRebaseTests(restConfig, tests)
+	rebaseCheck, err := o.rebaseApplicabilityCheck(restConfig)
 	if err != nil {
 		return err
 	}
+	tests, rebaseInapplicable := skipInapplicable(tests, []testApplicabilityCheck{{name: "rebase", check: rebaseCheck}})
+	inapplicable = append(inapplicable, rebaseInapplicable...)
 
 	if len(o.JUnitDir) > 0 {
 		if _, err := os.Stat(o.JUnitDir); err != nil {
@@ -250,6 +334,14 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		}
 	}
 
+	var failureArtifacts *failureArtifactCollector
+	if o.captureFailureArtifacts() {
+		failureArtifacts, err = newFailureArtifactCollector(restConfig, o.JUnitDir, o.MaxFailureArtifactTests)
+		if err != nil {
+			return err
+		}
+	}
+
 	parallelism := o.Parallelism
 	if parallelism == 0 {
 		parallelism = suite.Parallelism
@@ -307,44 +399,36 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		includeSuccess = true
 	}
 	testOutputLock := &sync.Mutex{}
-	testOutputConfig := newTestOutputConfig(testOutputLock, o.Out, monitorEventRecorder, includeSuccess)
+	testOutputConfig := newTestOutputConfig(testOutputLock, o.Out, monitorEventRecorder, failureArtifacts, includeSuccess)
 
-	early, notEarly := splitTests(tests, func(t *testCase) bool {
-		return strings.Contains(t.name, "[Early]")
-	})
-
-	late, primaryTests := splitTests(notEarly, func(t *testCase) bool {
-		return strings.Contains(t.name, "[Late]")
-	})
-
-	kubeTests, openshiftTests := splitTests(primaryTests, func(t *testCase) bool {
-		return strings.Contains(t.name, "[Suite:k8s]")
-	})
-
-	storageTests, kubeTests := splitTests(kubeTests, func(t *testCase) bool {
-		return strings.Contains(t.name, "[sig-storage]")
-	})
-
-	mustGatherTests, openshiftTests := splitTests(openshiftTests, func(t *testCase) bool {
-		return strings.Contains(t.name, "[sig-cli] oc adm must-gather")
-	})
-
-	// If user specifies a count, duplicate the kube and openshift tests that many times.
-	expectedTestCount := len(early) + len(late)
-	if count != -1 {
-		originalKube := kubeTests
-		originalOpenshift := openshiftTests
-		originalStorage := storageTests
-		originalMustGather := mustGatherTests
+	plan := replayPlan
+	if plan == nil {
+		plan = o.testPlan(tests, count, parallelism)
+	}
 
-		for i := 1; i < count; i++ {
-			kubeTests = append(kubeTests, copyTests(originalKube)...)
-			openshiftTests = append(openshiftTests, copyTests(originalOpenshift)...)
-			storageTests = append(storageTests, copyTests(originalStorage)...)
-			mustGatherTests = append(mustGatherTests, copyTests(originalMustGather)...)
+	if len(o.JUnitDir) > 0 {
+		manifest := &RunManifest{
+			Version:        version.Get().String(),
+			Suite:          suite.Name,
+			Seed:           seed,
+			CommandLine:    os.Args[1:],
+			SkippedByCheck: newSkippedTestsSummary(suite.Name, inapplicable).CountsByCheck,
+			Count:          count,
+			Batches:        plan.manifestBatches(),
+		}
+		if monitorTests != nil {
+			manifest.Monitors = monitorTests.ListMonitorTests().List()
+		}
+		if suite.LabelFilter != nil {
+			manifest.LabelFilter = suite.LabelFilter.String()
+		}
+		if o.ReplayManifest != nil {
+			manifest.SkippedByCheck = o.ReplayManifest.SkippedByCheck
+		}
+		if err := writeRunManifest(manifest, o.JUnitDir, fmt.Sprintf("_%s", start.UTC().Format("20060102-150405"))); err != nil {
+			fmt.Fprintf(o.ErrOut, "error: Unable to write run manifest: %v\n", err)
 		}
 	}
-	expectedTestCount += len(openshiftTests) + len(kubeTests) + len(storageTests) + len(mustGatherTests)
 
 	abortFn := neverAbort
 	testCtx := ctx
@@ -354,10 +438,17 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 
 	tests = nil
 
-	// run our Early tests
 	q := newParallelTestQueue(testRunnerContext)
-	q.Execute(testCtx, early, parallelism, testOutputConfig, abortFn)
-	tests = append(tests, early...)
+	runBatches := func(batches []testBatch) {
+		for _, batch := range batches {
+			batchTests := copyTests(batch.tests)
+			q.Execute(testCtx, batchTests, batch.parallelism, testOutputConfig, abortFn)
+			tests = append(tests, batchTests...)
+		}
+	}
+
+	// run our Early tests
+	runBatches(plan.early)
 
 	// TODO: will move to the monitor
 	pc.SetEvents([]string{upgradeEvent})
@@ -365,31 +456,14 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	// Run kube, storage, openshift, and must-gather tests. If user specified a count of -1,
 	// we loop indefinitely.
 	for i := 0; (i < 1 || count == -1) && testCtx.Err() == nil; i++ {
-		kubeTestsCopy := copyTests(kubeTests)
-		q.Execute(testCtx, kubeTestsCopy, parallelism, testOutputConfig, abortFn)
-		tests = append(tests, kubeTestsCopy...)
-
-		// I thought about randomizing the order of the kube, storage, and openshift tests, but storage dominates our e2e runs, so it doesn't help much.
-		storageTestsCopy := copyTests(storageTests)
-		q.Execute(testCtx, storageTestsCopy, max(1, parallelism/2), testOutputConfig, abortFn) // storage tests only run at half the parallelism, so we can avoid cloud provider quota problems.
-		tests = append(tests, storageTestsCopy...)
-
-		openshiftTestsCopy := copyTests(openshiftTests)
-		q.Execute(testCtx, openshiftTestsCopy, parallelism, testOutputConfig, abortFn)
-		tests = append(tests, openshiftTestsCopy...)
-
-		// run the must-gather tests after parallel tests to reduce resource contention
-		mustGatherTestsCopy := copyTests(mustGatherTests)
-		q.Execute(testCtx, mustGatherTestsCopy, parallelism, testOutputConfig, abortFn)
-		tests = append(tests, mustGatherTestsCopy...)
+		runBatches(plan.primary)
 	}
 
 	// TODO: will move to the monitor
 	pc.SetEvents([]string{postUpgradeEvent})
 
 	// run Late test suits after everything else
-	q.Execute(testCtx, late, parallelism, testOutputConfig, abortFn)
-	tests = append(tests, late...)
+	runBatches(plan.late)
 
 	// TODO: will move to the monitor
 	if len(o.JUnitDir) > 0 {
@@ -410,6 +484,8 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 
 	// calculate the effective test set we ran, excluding any incompletes
 	tests, _ = splitTests(tests, func(t *testCase) bool { return t.success || t.flake || t.failed || t.skipped })
+	// tests that do not apply to the cluster are reported as skipped with the reason
+	tests = append(tests, inapplicable...)
 
 	end := time.Now()
 	duration := end.Sub(start).Round(time.Second / 10)
@@ -566,6 +642,9 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	}
 
 	if len(o.JUnitDir) > 0 {
+		if err := writeSkippedTestsSummary(newSkippedTestsSummary(suite.Name, inapplicable), o.JUnitDir, timeSuffix); err != nil {
+			fmt.Fprintf(o.ErrOut, "error: Unable to write skipped tests summary: %v\n", err)
+		}
 		finalSuiteResults := generateJUnitTestSuiteResults(junitSuiteName, duration, tests, syntheticTestResults...)
 		if err := writeJUnitReport(finalSuiteResults, "junit_e2e", timeSuffix, o.JUnitDir, o.ErrOut); err != nil {
 			fmt.Fprintf(o.Out, "error: Unable to write e2e JUnit xml results: %v", err)
@@ -594,7 +673,58 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	return ctx.Err()
 }
 
-func (o *GinkgoRunSuiteOptions) filterOutRebaseTests(restConfig *rest.Config, tests []*testCase) ([]*testCase, error) {
+// testPlan splits the tests into the batches they are run in.
+func (o *GinkgoRunSuiteOptions) testPlan(tests []*testCase, count, parallelism int) *testPlan {
+	early, notEarly := splitTests(tests, func(t *testCase) bool {
+		return strings.Contains(t.name, "[Early]")
+	})
+
+	late, primaryTests := splitTests(notEarly, func(t *testCase) bool {
+		return strings.Contains(t.name, "[Late]")
+	})
+
+	kubeTests, openshiftTests := splitTests(primaryTests, func(t *testCase) bool {
+		return strings.Contains(t.name, "[Suite:k8s]")
+	})
+
+	storageTests, kubeTests := splitTests(kubeTests, func(t *testCase) bool {
+		return strings.Contains(t.name, "[sig-storage]")
+	})
+
+	mustGatherTests, openshiftTests := splitTests(openshiftTests, func(t *testCase) bool {
+		return strings.Contains(t.name, "[sig-cli] oc adm must-gather")
+	})
+
+	// If user specifies a count, duplicate the kube and openshift tests that many times.
+	if count != -1 {
+		originalKube := kubeTests
+		originalOpenshift := openshiftTests
+		originalStorage := storageTests
+		originalMustGather := mustGatherTests
+
+		for i := 1; i < count; i++ {
+			kubeTests = append(kubeTests, copyTests(originalKube)...)
+			openshiftTests = append(openshiftTests, copyTests(originalOpenshift)...)
+			storageTests = append(storageTests, copyTests(originalStorage)...)
+			mustGatherTests = append(mustGatherTests, copyTests(originalMustGather)...)
+		}
+	}
+
+	plan := &testPlan{}
+	plan.add(testBatch{name: earlyBatch, parallelism: parallelism, tests: early})
+	plan.add(testBatch{name: "kube", parallelism: parallelism, tests: kubeTests})
+	// I thought about randomizing the order of the kube, storage, and openshift tests, but storage dominates our e2e runs, so it doesn't help much.
+	// storage tests only run at half the parallelism, so we can avoid cloud provider quota problems.
+	plan.add(testBatch{name: "storage", parallelism: max(1, parallelism/2), tests: storageTests})
+	plan.add(testBatch{name: "openshift", parallelism: parallelism, tests: openshiftTests})
+	// run the must-gather tests after parallel tests to reduce resource contention
+	plan.add(testBatch{name: "must-gather", parallelism: parallelism, tests: mustGatherTests})
+	plan.add(testBatch{name: lateBatch, parallelism: parallelism, tests: late})
+	return plan
+}
+
+// rebaseApplicabilityCheck skips tests that are known not to work with the kube version of a rebase in progress.
+func (o *GinkgoRunSuiteOptions) rebaseApplicabilityCheck(restConfig *rest.Config) (TestApplicabilityFunc, error) {
 	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
 	if err != nil {
 		return nil, err
@@ -606,23 +736,19 @@ func (o *GinkgoRunSuiteOptions) filterOutRebaseTests(restConfig *rest.Config, te
 	// TODO: this version along with below exclusions lists needs to be updated
 	// for the rebase in-progress.
 	if !strings.HasPrefix(serverVersion.Minor, "30") {
-		return tests, nil
+		return nil, nil
 	}
 
 	// Below list should only be filled in when we're trying to land k8s rebase.
 	// Don't pile them up!
 	exclusions := []string{}
 
-	matches := make([]*testCase, 0, len(tests))
-outerLoop:
-	for _, test := range tests {
+	return func(name string) string {
 		for _, excl := range exclusions {
-			if strings.Contains(test.name, excl) {
-				fmt.Fprintf(o.Out, "Skipping %q due to rebase in-progress\n", test.name)
-				continue outerLoop
+			if strings.Contains(name, excl) {
+				return fmt.Sprintf("kube %s.%s rebase in progress", serverVersion.Major, serverVersion.Minor)
 			}
 		}
-		matches = append(matches, test)
-	}
-	return matches, nil
+		return ""
+	}, nil
 }
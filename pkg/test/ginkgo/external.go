@@ -3,6 +3,8 @@ package ginkgo
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,12 +12,15 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	imagev1 "github.com/openshift/api/image/v1"
+	"github.com/openshift/origin/pkg/test/ginkgo/externalbinaryapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
 	"github.com/openshift/origin/test/extended/util"
 )
 
@@ -24,13 +29,84 @@ type serializedTest struct {
 	Labels string
 }
 
-// externalTestsForSuite reads tests from external binary, currently only
-// k8s-tests is supported
-func externalTestsForSuite(ctx context.Context) ([]*testCase, error) {
+// externalTestSource records where a test from an external binary came from.
+type externalTestSource struct {
+	// image is the pull spec of the payload image the binary was extracted from.
+	image string
+	// binary is the path of the binary in image.
+	binary string
+	// info is reported by binaries speaking the v1 protocol, it is nil for k8s-tests.
+	info *externalbinaryapi.Info
+}
+
+// junitProperties returns the provenance of the test to record in junit.
+func (s *externalTestSource) junitProperties() *junitapi.TestCaseProperties {
+	if s == nil {
+		return nil
+	}
+	properties := []*junitapi.TestSuiteProperty{
+		{Name: "TestSourceImage", Value: s.image},
+		{Name: "TestSourceBinary", Value: s.binary},
+	}
+	if s.info != nil {
+		properties = append(properties,
+			&junitapi.TestSuiteProperty{Name: "TestSourceName", Value: s.info.Name},
+			&junitapi.TestSuiteProperty{Name: "TestSourceAPIVersion", Value: s.info.APIVersion},
+		)
+		if len(s.info.Component) > 0 {
+			properties = append(properties, &junitapi.TestSuiteProperty{Name: "TestSourceComponent", Value: s.info.Component})
+		}
+		if len(s.info.Version) > 0 {
+			properties = append(properties, &junitapi.TestSuiteProperty{Name: "TestSourceVersion", Value: s.info.Version})
+		}
+	}
+	return &junitapi.TestCaseProperties{Properties: properties}
+}
+
+// externalBinary is a test binary in a payload image.
+type externalBinary struct {
+	// tag is the tag of the image in the release image-references.
+	tag   string
+	image string
+	// binary is the path of the binary in image.
+	binary string
+}
+
+// externalTestsForSuite reads tests from external binaries: k8s-tests from the hyperkube image, and every binary
+// listed in the externalbinaryapi.TestBinariesAnnotation of a tag in the release image-references.  Binaries
+// other than k8s-tests that cannot be listed are reported to out and skipped.
+func externalTestsForSuite(ctx context.Context, out io.Writer) ([]*testCase, error) {
+	imageReferences, err := releaseImageReferences()
+	if err != nil {
+		return nil, err
+	}
+
+	k8sTestsBinary, err := externalBinaryForTag(imageReferences, "hyperkube", "/usr/bin/k8s-tests")
+	if err != nil {
+		return nil, err
+	}
+	tests, err := k8sTestsForSuite(ctx, k8sTestsBinary)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, binary := range annotatedExternalBinaries(imageReferences) {
+		binaryTests, err := externalBinaryTests(ctx, binary)
+		if err != nil {
+			fmt.Fprintf(out, "Skipping tests from %s in %s: %v\n", binary.binary, binary.tag, err)
+			continue
+		}
+		fmt.Fprintf(out, "Got %d tests from %s in %s\n", len(binaryTests), binary.binary, binary.tag)
+		tests = append(tests, binaryTests...)
+	}
+	return tests, nil
+}
+
+// k8sTestsForSuite lists the tests of k8s-tests, which predates the external binary protocol.
+func k8sTestsForSuite(ctx context.Context, binary *externalBinary) ([]*testCase, error) {
 	var tests []*testCase
 
-	// TODO: add support for binaries from other images
-	testBinary, err := extractBinaryFromReleaseImage("hyperkube", "/usr/bin/k8s-tests")
+	testBinary, err := extractExternalBinary(binary)
 	if err != nil {
 		return nil, fmt.Errorf("unable to extract k8s-tests binary: %w", err)
 	}
@@ -40,6 +116,7 @@ func externalTestsForSuite(ctx context.Context) ([]*testCase, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed running '%s list': %w", testBinary, err)
 	}
+	source := &externalTestSource{image: binary.image, binary: binary.binary}
 	buf := bytes.NewBuffer(testList)
 	for {
 		line, err := buf.ReadString('\n')
@@ -59,71 +136,243 @@ func externalTestsForSuite(ctx context.Context) ([]*testCase, error) {
 				name:       test.Name + test.Labels,
 				rawName:    test.Name,
 				binaryName: testBinary,
+				source:     source,
 			})
 		}
 	}
 	return tests, nil
 }
 
-// extractBinaryFromReleaseImage is responsible for resolving the tag from
-// release image and extracting binary, returns path to the binary or error
-func extractBinaryFromReleaseImage(tag, binary string) (string, error) {
+// externalBinaryTests lists the tests of a binary speaking the external binary protocol.
+func externalBinaryTests(ctx context.Context, binary *externalBinary) ([]*testCase, error) {
+	testBinary, err := extractExternalBinary(binary)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &externalbinaryapi.Info{}
+	if err := runExternalBinaryForJSON(ctx, testBinary, "info", info); err != nil {
+		return nil, err
+	}
+	if info.APIVersion != externalbinaryapi.ProtocolVersionV1 {
+		return nil, fmt.Errorf("unsupported protocol version %q", info.APIVersion)
+	}
+
+	specs := []externalbinaryapi.TestSpec{}
+	if err := runExternalBinaryForJSON(ctx, testBinary, "list", &specs); err != nil {
+		return nil, err
+	}
+
+	source := &externalTestSource{image: binary.image, binary: binary.binary, info: info}
+	var tests []*testCase
+	for _, spec := range specs {
+		test, err := newTestCaseFromExternalTestSpec(spec)
+		if err != nil {
+			return nil, err
+		}
+		test.binaryName = testBinary
+		test.source = source
+		tests = append(tests, test)
+	}
+	return tests, nil
+}
+
+// newTestCaseFromExternalTestSpec names the test the way origin tests are named so that suites select external tests
+// the same way they select origin tests.
+func newTestCaseFromExternalTestSpec(spec externalbinaryapi.TestSpec) (*testCase, error) {
+	if len(spec.Name) == 0 {
+		return nil, fmt.Errorf("test without a name")
+	}
+
+	name := spec.Name
+	appendLabel := func(label string) {
+		if !strings.Contains(name, label) {
+			name += " " + label
+		}
+	}
+	for _, label := range spec.Labels {
+		appendLabel(fmt.Sprintf("[%s]", label))
+	}
+	if spec.Isolation.Mode == externalbinaryapi.IsolationModeSerial {
+		appendLabel("[Serial]")
+	}
+	for _, suite := range spec.Suites {
+		appendLabel(fmt.Sprintf("[Suite:%s]", suite))
+	}
+
+	tc := &testCase{
+		name:           name,
+		rawName:        spec.Name,
+		externalLabels: spec.Labels,
+	}
+	if len(spec.Timeout) > 0 {
+		timeout, err := time.ParseDuration(spec.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("test %q has an invalid timeout: %w", spec.Name, err)
+		}
+		tc.testTimeout = timeout
+	}
+	return tc, nil
+}
+
+// runExternalBinaryForJSON runs command of testBinary and decodes its stdout into into.
+func runExternalBinaryForJSON(ctx context.Context, testBinary, command string, into interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+	defer cancel()
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, testBinary, command)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed running '%s %s': %w: %s", testBinary, command, err, stderr.String())
+	}
+	if err := json.Unmarshal(stdout.Bytes(), into); err != nil {
+		return fmt.Errorf("unable to read output of '%s %s': %w", testBinary, command, err)
+	}
+	return nil
+}
+
+// releaseImageReferences reads the image-references of the release image the cluster is updating to.
+func releaseImageReferences() (*imagev1.ImageStream, error) {
 	tmpDir, err := os.MkdirTemp("", "release")
 	if err != nil {
-		return "", fmt.Errorf("cannot create temporary directory for extracted binary: %w", err)
+		return nil, fmt.Errorf("cannot create temporary directory for image-references: %w", err)
 	}
+	defer os.RemoveAll(tmpDir)
 
 	oc := util.NewCLIWithoutNamespace("default")
 	cv, err := oc.AdminConfigClient().ConfigV1().ClusterVersions().Get(context.Background(), "version", metav1.GetOptions{})
 	if err != nil {
-		return "", fmt.Errorf("failed reading ClusterVersion/version: %w", err)
+		return nil, fmt.Errorf("failed reading ClusterVersion/version: %w", err)
 	}
 	releaseImage := cv.Status.Desired.Image
 	if len(releaseImage) == 0 {
-		return "", fmt.Errorf("cannot determine release image from ClusterVersion resource")
+		return nil, fmt.Errorf("cannot determine release image from ClusterVersion resource")
 	}
 
 	if err := runImageExtract(releaseImage, "/release-manifests/image-references", tmpDir); err != nil {
-		return "", fmt.Errorf("failed extracting image-references: %w", err)
+		return nil, fmt.Errorf("failed extracting image-references: %w", err)
 	}
 	jsonFile, err := os.Open(filepath.Join(tmpDir, "image-references"))
 	if err != nil {
-		return "", fmt.Errorf("failed reading image-references: %w", err)
+		return nil, fmt.Errorf("failed reading image-references: %w", err)
 	}
 	defer jsonFile.Close()
 	data, err := ioutil.ReadAll(jsonFile)
 	if err != nil {
-		return "", fmt.Errorf("unable to load release image-references: %w", err)
+		return nil, fmt.Errorf("unable to load release image-references: %w", err)
 	}
 	is := &imagev1.ImageStream{}
 	if err := json.Unmarshal(data, &is); err != nil {
-		return "", fmt.Errorf("unable to load release image-references: %w", err)
+		return nil, fmt.Errorf("unable to load release image-references: %w", err)
 	}
 	if is.Kind != "ImageStream" || is.APIVersion != "image.openshift.io/v1" {
-		return "", fmt.Errorf("unrecognized image-references in release payload")
+		return nil, fmt.Errorf("unrecognized image-references in release payload")
 	}
+	return is, nil
+}
 
-	image := ""
-	for _, t := range is.Spec.Tags {
-		if t.Name == tag {
-			image = t.From.Name
-			break
+// externalBinaryForTag resolves the image of tag in the release image-references.
+func externalBinaryForTag(imageReferences *imagev1.ImageStream, tag, binary string) (*externalBinary, error) {
+	for _, t := range imageReferences.Spec.Tags {
+		if t.Name == tag && t.From != nil {
+			return &externalBinary{tag: tag, image: t.From.Name, binary: binary}, nil
+		}
+	}
+	return nil, fmt.Errorf("%s not found", tag)
+}
+
+// annotatedExternalBinaries returns the binaries listed in the externalbinaryapi.TestBinariesAnnotation of the tags in
+// the release image-references, sorted by tag.
+func annotatedExternalBinaries(imageReferences *imagev1.ImageStream) []*externalBinary {
+	var binaries []*externalBinary
+	for _, t := range imageReferences.Spec.Tags {
+		if t.From == nil {
+			continue
+		}
+		for _, binary := range strings.Split(t.Annotations[externalbinaryapi.TestBinariesAnnotation], ",") {
+			binary = strings.TrimSpace(binary)
+			if len(binary) == 0 {
+				continue
+			}
+			binaries = append(binaries, &externalBinary{tag: t.Name, image: t.From.Name, binary: binary})
 		}
 	}
-	if len(image) == 0 {
-		return "", fmt.Errorf("%s not found", tag)
+	sort.SliceStable(binaries, func(i, j int) bool {
+		return binaries[i].tag < binaries[j].tag
+	})
+	return binaries
+}
+
+// extractExternalBinary extracts the binary from its image and returns the local path to it.  Binaries from images
+// referenced by digest are cached across runs, since the content of the image cannot change.
+func extractExternalBinary(binary *externalBinary) (string, error) {
+	cacheDir := externalBinaryCacheDir(binary)
+	if len(cacheDir) == 0 {
+		tmpDir, err := os.MkdirTemp("", "release")
+		if err != nil {
+			return "", fmt.Errorf("cannot create temporary directory for extracted binary: %w", err)
+		}
+		return extractExternalBinaryTo(binary, tmpDir)
 	}
-	if err := runImageExtract(image, binary, tmpDir); err != nil {
-		return "", fmt.Errorf("failed extracting %q from %q: %w", binary, image, err)
+
+	cachedBinary := filepath.Join(cacheDir, filepath.Base(binary.binary))
+	if _, err := os.Stat(cachedBinary); err == nil {
+		return cachedBinary, nil
 	}
 
-	extractedBinary := filepath.Join(tmpDir, filepath.Base(binary))
+	// extract next to the cache entry and rename so concurrent runs never see a partial binary.
+	if err := os.MkdirAll(filepath.Dir(cacheDir), 0755); err != nil {
+		return "", fmt.Errorf("cannot create external binary cache: %w", err)
+	}
+	tmpDir, err := os.MkdirTemp(filepath.Dir(cacheDir), "extract")
+	if err != nil {
+		return "", fmt.Errorf("cannot create temporary directory for extracted binary: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	if _, err := extractExternalBinaryTo(binary, tmpDir); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmpDir, cacheDir); err != nil && !os.IsExist(err) {
+		if _, statErr := os.Stat(cachedBinary); statErr != nil {
+			return "", fmt.Errorf("failed caching the extracted binary: %w", err)
+		}
+	}
+	return cachedBinary, nil
+}
+
+func extractExternalBinaryTo(binary *externalBinary, dir string) (string, error) {
+	if err := runImageExtract(binary.image, binary.binary, dir); err != nil {
+		return "", fmt.Errorf("failed extracting %q from %q: %w", binary.binary, binary.image, err)
+	}
+
+	extractedBinary := filepath.Join(dir, filepath.Base(binary.binary))
 	if err := os.Chmod(extractedBinary, 0755); err != nil {
 		return "", fmt.Errorf("failed making the extracted binary executable: %w", err)
 	}
 	return extractedBinary, nil
 }
 
+// externalBinaryCacheDir returns the directory the binary is cached in, or empty if it cannot be cached.
+// OPENSHIFT_TESTS_BINARY_CACHE overrides the location of the cache.
+func externalBinaryCacheDir(binary *externalBinary) string {
+	if !strings.Contains(binary.image, "@sha256:") {
+		return ""
+	}
+	cacheRoot := os.Getenv("OPENSHIFT_TESTS_BINARY_CACHE")
+	if len(cacheRoot) == 0 {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return ""
+		}
+		cacheRoot = filepath.Join(userCacheDir, "openshift-tests", "external-binaries")
+	}
+	key := sha256.Sum256([]byte(binary.image + ":" + binary.binary))
+	return filepath.Join(cacheRoot, hex.EncodeToString(key[:]))
+}
+
 // runImageExtract extracts src from specified image to dst
 func runImageExtract(image, src, dst string) error {
 	cmd := exec.Command("oc", "--kubeconfig="+util.KubeConfigPath(), "image", "extract", image, fmt.Sprintf("--path=%s:%s", src, dst), "--confirm")
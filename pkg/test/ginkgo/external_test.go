@@ -0,0 +1,111 @@
+package ginkgo
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	"github.com/openshift/origin/pkg/test/ginkgo/externalbinaryapi"
+)
+
+func Test_newTestCaseFromExternalTestSpec(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        externalbinaryapi.TestSpec
+		wantName    string
+		wantTimeout time.Duration
+		wantErr     bool
+	}{
+		{
+			name:     "name only",
+			spec:     externalbinaryapi.TestSpec{Name: "[sig-foo] does a thing"},
+			wantName: "[sig-foo] does a thing",
+		},
+		{
+			name: "labels, suites and isolation",
+			spec: externalbinaryapi.TestSpec{
+				Name:      "[sig-foo] does a thing",
+				Labels:    []string{"sig-foo", "Slow"},
+				Suites:    []string{"openshift/conformance/serial"},
+				Isolation: externalbinaryapi.Isolation{Mode: externalbinaryapi.IsolationModeSerial},
+			},
+			wantName: "[sig-foo] does a thing [Slow] [Serial] [Suite:openshift/conformance/serial]",
+		},
+		{
+			name:        "timeout",
+			spec:        externalbinaryapi.TestSpec{Name: "does a thing", Timeout: "30m"},
+			wantName:    "does a thing",
+			wantTimeout: 30 * time.Minute,
+		},
+		{
+			name:    "invalid timeout",
+			spec:    externalbinaryapi.TestSpec{Name: "does a thing", Timeout: "soon"},
+			wantErr: true,
+		},
+		{
+			name:    "missing name",
+			spec:    externalbinaryapi.TestSpec{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newTestCaseFromExternalTestSpec(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.name != tt.wantName {
+				t.Errorf("name = %q, want %q", got.name, tt.wantName)
+			}
+			if got.rawName != tt.spec.Name {
+				t.Errorf("rawName = %q, want %q", got.rawName, tt.spec.Name)
+			}
+			if got.testTimeout != tt.wantTimeout {
+				t.Errorf("testTimeout = %v, want %v", got.testTimeout, tt.wantTimeout)
+			}
+		})
+	}
+}
+
+func Test_annotatedExternalBinaries(t *testing.T) {
+	imageReferences := &imagev1.ImageStream{
+		Spec: imagev1.ImageStreamSpec{
+			Tags: []imagev1.TagReference{
+				{
+					Name:        "zz-operator",
+					Annotations: map[string]string{externalbinaryapi.TestBinariesAnnotation: "/usr/bin/zz-tests"},
+					From:        &corev1.ObjectReference{Name: "registry/zz@sha256:1"},
+				},
+				{
+					Name: "hyperkube",
+					From: &corev1.ObjectReference{Name: "registry/hyperkube@sha256:2"},
+				},
+				{
+					Name:        "aa-operator",
+					Annotations: map[string]string{externalbinaryapi.TestBinariesAnnotation: "/usr/bin/aa-tests, /usr/bin/aa-more-tests,"},
+					From:        &corev1.ObjectReference{Name: "registry/aa@sha256:3"},
+				},
+			},
+		},
+	}
+
+	got := annotatedExternalBinaries(imageReferences)
+	want := []externalBinary{
+		{tag: "aa-operator", image: "registry/aa@sha256:3", binary: "/usr/bin/aa-tests"},
+		{tag: "aa-operator", image: "registry/aa@sha256:3", binary: "/usr/bin/aa-more-tests"},
+		{tag: "zz-operator", image: "registry/zz@sha256:1", binary: "/usr/bin/zz-tests"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d binaries, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if *got[i] != want[i] {
+			t.Errorf("binary %d = %#v, want %#v", i, *got[i], want[i])
+		}
+	}
+}
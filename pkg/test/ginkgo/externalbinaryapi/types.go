@@ -0,0 +1,62 @@
+package externalbinaryapi
+
+// The below types describe the protocol openshift-tests uses to list and run tests that are shipped as binaries in
+// payload images.  A test binary must support three commands:
+//
+//	<binary> info            prints Info as JSON to stdout
+//	<binary> list            prints a JSON list of TestSpec to stdout
+//	<binary> run-test NAME   runs a single test and exits with the same codes as openshift-tests run-test:
+//	                         0 passed, 1 failed, 2 timed out, 3 skipped, 4 flaked
+//
+// Anything a binary writes to stderr is ignored by info and list and is captured as test output by run-test.
+
+const (
+	// ProtocolVersionV1 is the only protocol version currently supported.
+	ProtocolVersionV1 = "v1"
+
+	// TestBinariesAnnotation is set on a tag of the release image-references to a comma separated list of absolute
+	// paths of test binaries in the image of that tag.  openshift-tests extracts each binary and merges its tests
+	// with its own.
+	TestBinariesAnnotation = "testbinaries.openshift.io/paths"
+)
+
+// Info identifies a test binary and the version of the protocol it speaks.
+type Info struct {
+	// APIVersion is the protocol version of the binary, for instance v1.
+	APIVersion string `json:"apiVersion"`
+	// Name identifies the binary, usually after the component that owns the tests.
+	Name string `json:"name"`
+	// Component is the jira component that owns the tests in this binary.
+	Component string `json:"component,omitempty"`
+	// Version is the version of the binary itself, like the commit it was built from.
+	Version string `json:"version,omitempty"`
+}
+
+// TestSpec describes a single test of a test binary.
+type TestSpec struct {
+	// Name is passed to run-test to run the test.  It must be unique within the binary.
+	Name string `json:"name"`
+	// Labels are added to the name of the test as [Label], for instance sig-network or Slow.
+	Labels []string `json:"labels,omitempty"`
+	// Suites are the openshift-tests suites the test belongs to, for instance openshift/conformance/parallel.
+	Suites []string `json:"suites,omitempty"`
+	// Timeout overrides the test timeout of the suite.  It is parsed as a Go duration, like 30m.
+	Timeout string `json:"timeout,omitempty"`
+	// Isolation describes how the test may run alongside other tests.
+	Isolation Isolation `json:"isolation,omitempty"`
+}
+
+// IsolationMode is how a test may run alongside other tests.
+type IsolationMode string
+
+const (
+	// IsolationModeParallel tests may run alongside any other test.
+	IsolationModeParallel IsolationMode = ""
+	// IsolationModeSerial tests run by themselves, after the parallel tests.
+	IsolationModeSerial IsolationMode = "Serial"
+)
+
+// Isolation describes how a test may run alongside other tests.
+type Isolation struct {
+	Mode IsolationMode `json:"mode,omitempty"`
+}
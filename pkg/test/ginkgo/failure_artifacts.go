@@ -0,0 +1,378 @@
+package ginkgo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// failureArtifactBytesPerTest is the most that is written for the namespaces of a single failed test.
+	failureArtifactBytesPerTest = 20 * 1024 * 1024
+	// failureArtifactLogLines is the number of lines kept from the end of each container log.
+	failureArtifactLogLines = 2000
+	// failureArtifactTimeout bounds how long capturing the namespaces of a single failed test may take.
+	failureArtifactTimeout = 2 * time.Minute
+	// failureArtifactConcurrentCaptures bounds how many failed tests are captured at once, so that many tests failing
+	// at once do not flood the apiserver.
+	failureArtifactConcurrentCaptures = 3
+)
+
+var (
+	// testNamespaceRegex matches the namespaces created by NewCLI project setup and by the kube framework, see
+	// test/extended/util/client.go and test/extended/util/test_setup.go.
+	testNamespaceRegex = regexp.MustCompile(`(?:Creating project|Creating namespace|Created test namespace) "(e2e-[a-z0-9-]+)"`)
+
+	unsafeFileNameRegex = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+	// excludedFailureArtifactResources are never captured, because they hold credentials, are captured separately
+	// or are the same in every namespace.
+	excludedFailureArtifactResources = sets.New[schema.GroupResource](
+		schema.GroupResource{Resource: "secrets"},
+		schema.GroupResource{Resource: "pods"},
+		schema.GroupResource{Resource: "events"},
+		schema.GroupResource{Group: "events.k8s.io", Resource: "events"},
+		schema.GroupResource{Group: "metrics.k8s.io", Resource: "pods"},
+		schema.GroupResource{Group: "packages.operators.coreos.com", Resource: "packagemanifests"},
+	)
+)
+
+// failureArtifactCollector snapshots the namespaces of failed tests into <junit-dir>/tests/<sanitized-name>/ and then
+// deletes them.  Tests only leave their namespaces behind for it when run with DELETE_NAMESPACE_ON_FAILURE=false.
+type failureArtifactCollector struct {
+	kubeClient    kubernetes.Interface
+	dynamicClient dynamic.Interface
+	// namespacedResources returns the resources to capture in addition to pods and events.
+	namespacedResources func() ([]schema.GroupVersionResource, error)
+
+	junitDir string
+	maxTests int
+	// deleteNamespaces is false when namespaces are never deleted, so that they can be inspected after the run.
+	deleteNamespaces bool
+
+	// captureSlots holds a token for every capture in progress.
+	captureSlots chan struct{}
+
+	// lock guards the number of tests captured and the directories they were captured to.
+	lock     sync.Mutex
+	captured int
+	dirs     sets.Set[string]
+}
+
+func newFailureArtifactCollector(restConfig *rest.Config, junitDir string, maxTests int) (*failureArtifactCollector, error) {
+	restConfig = rest.CopyConfig(restConfig)
+	restConfig.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(5, 10)
+
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	c := &failureArtifactCollector{
+		kubeClient:       kubeClient,
+		dynamicClient:    dynamicClient,
+		junitDir:         junitDir,
+		maxTests:         maxTests,
+		deleteNamespaces: os.Getenv("DELETE_NAMESPACE") != "false",
+		captureSlots:     make(chan struct{}, failureArtifactConcurrentCaptures),
+		dirs:             sets.New[string](),
+	}
+	var once sync.Once
+	var resources []schema.GroupVersionResource
+	var resourcesErr error
+	c.namespacedResources = func() ([]schema.GroupVersionResource, error) {
+		once.Do(func() {
+			resources, resourcesErr = listableNamespacedResources(kubeClient.Discovery())
+		})
+		return resources, resourcesErr
+	}
+	return c, nil
+}
+
+// listableNamespacedResources returns the preferred version of every namespaced resource that can be listed.
+func listableNamespacedResources(client discovery.DiscoveryInterface) ([]schema.GroupVersionResource, error) {
+	// some groups failing discovery should not prevent capturing the others
+	lists, err := client.ServerPreferredNamespacedResources()
+	if err != nil && len(lists) == 0 {
+		return nil, err
+	}
+	var resources []schema.GroupVersionResource
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, resource := range list.APIResources {
+			if strings.Contains(resource.Name, "/") || !sets.New[string](resource.Verbs...).Has("list") {
+				continue
+			}
+			if excludedFailureArtifactResources.Has(schema.GroupResource{Group: gv.Group, Resource: resource.Name}) {
+				continue
+			}
+			resources = append(resources, gv.WithResource(resource.Name))
+		}
+	}
+	return resources, nil
+}
+
+// testNamespaces returns the namespaces a test reported creating in its output.
+func testNamespaces(output []byte) []string {
+	namespaces := sets.New[string]()
+	for _, match := range testNamespaceRegex.FindAllSubmatch(output, -1) {
+		namespaces.Insert(string(match[1]))
+	}
+	return sets.List(namespaces)
+}
+
+// sanitizeTestName returns a directory name for a test.  Test names are truncated, so a hash of the full name keeps
+// them unique.
+func sanitizeTestName(name string) string {
+	sanitized := strings.Trim(unsafeFileNameRegex.ReplaceAllString(name, "_"), "_.")
+	if len(sanitized) > 120 {
+		sanitized = sanitized[:120]
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(name))
+	return fmt.Sprintf("%s-%08x", sanitized, hash.Sum32())
+}
+
+// Collect captures the namespaces of a failed test, then deletes them.  The namespaces of flaked tests are only
+// deleted.  It returns the directory the artifacts were written to, relative to the junit directory.
+func (c *failureArtifactCollector) Collect(ctx context.Context, result *testRunResult, recorder monitorapi.RecorderReader) (string, error) {
+	if result.testState == TestSucceeded || result.testState == TestSkipped {
+		return "", nil
+	}
+	namespaces := testNamespaces(result.testOutputBytes)
+	if len(namespaces) == 0 {
+		return "", nil
+	}
+
+	var dir string
+	var captureErr error
+	if isTestFailed(result.testState) {
+		dir, captureErr = c.capture(ctx, result, namespaces, recorder)
+	}
+
+	if c.deleteNamespaces {
+		for _, namespace := range namespaces {
+			err := c.kubeClient.CoreV1().Namespaces().Delete(ctx, namespace, metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) && captureErr == nil {
+				captureErr = fmt.Errorf("unable to delete namespace %s: %w", namespace, err)
+			}
+		}
+	}
+	return dir, captureErr
+}
+
+func (c *failureArtifactCollector) capture(ctx context.Context, result *testRunResult, namespaces []string, recorder monitorapi.RecorderReader) (string, error) {
+	dir, ok := c.reserveDir(result.name)
+	if !ok {
+		return "", nil
+	}
+
+	select {
+	case c.captureSlots <- struct{}{}:
+		defer func() { <-c.captureSlots }()
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, failureArtifactTimeout)
+	defer cancel()
+
+	w := &artifactWriter{dir: filepath.Join(c.junitDir, dir), remaining: failureArtifactBytesPerTest}
+	var errs []error
+	for _, namespace := range namespaces {
+		if err := c.captureNamespace(ctx, w, namespace); err != nil {
+			errs = append(errs, fmt.Errorf("namespace %s: %w", namespace, err))
+		}
+	}
+	if recorder != nil {
+		if err := writeNamespaceIntervals(w, recorder.Intervals(result.start, result.end), namespaces); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return dir, fmt.Errorf("unable to capture all artifacts: %v", errs)
+	}
+	return dir, nil
+}
+
+// reserveDir returns the directory to capture a failed test to, or false once maxTests tests have been captured.
+func (c *failureArtifactCollector) reserveDir(name string) (string, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.captured >= c.maxTests {
+		return "", false
+	}
+	c.captured++
+
+	// a retried test fails under the same name
+	dir := filepath.Join("tests", sanitizeTestName(name))
+	for i := 2; c.dirs.Has(dir); i++ {
+		dir = filepath.Join("tests", fmt.Sprintf("%s-%d", sanitizeTestName(name), i))
+	}
+	c.dirs.Insert(dir)
+	return dir, true
+}
+
+func (c *failureArtifactCollector) captureNamespace(ctx context.Context, w *artifactWriter, namespace string) error {
+	pods, err := c.kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := w.writeYAML(filepath.Join(namespace, "pods.yaml"), pods); err != nil {
+		return err
+	}
+
+	events, err := c.kubeClient.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	if err := w.writeYAML(filepath.Join(namespace, "events.yaml"), events); err != nil {
+		return err
+	}
+
+	for _, pod := range pods.Items {
+		for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			if status.State.Waiting != nil && status.LastTerminationState.Terminated == nil {
+				// never started, so there are no logs
+				continue
+			}
+			logFile := filepath.Join(namespace, "logs", pod.Name, status.Name+".log")
+			if err := c.captureLog(ctx, w, logFile, &pod, status.Name, false); err != nil {
+				return err
+			}
+			if status.RestartCount > 0 {
+				logFile := filepath.Join(namespace, "logs", pod.Name, status.Name+".previous.log")
+				if err := c.captureLog(ctx, w, logFile, &pod, status.Name, true); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	resources, err := c.namespacedResources()
+	if err != nil {
+		return fmt.Errorf("unable to discover resources: %w", err)
+	}
+	for _, resource := range resources {
+		list, err := c.dynamicClient.Resource(resource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			// resources can be forbidden or served by an unavailable aggregated apiserver
+			continue
+		}
+		if len(list.Items) == 0 {
+			continue
+		}
+		name := resource.Resource
+		if len(resource.Group) > 0 {
+			name += "." + resource.Group
+		}
+		if err := w.writeYAML(filepath.Join(namespace, "resources", name+".yaml"), list); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *failureArtifactCollector) captureLog(ctx context.Context, w *artifactWriter, filename string, pod *corev1.Pod, container string, previous bool) error {
+	tailLines := int64(failureArtifactLogLines)
+	stream, err := c.kubeClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: container,
+		Previous:  previous,
+		TailLines: &tailLines,
+	}).Stream(ctx)
+	if err != nil {
+		// the node may be gone, so record why instead of failing the capture
+		return w.write(filename, []byte(fmt.Sprintf("unable to get logs: %v\n", err)))
+	}
+	defer stream.Close()
+	return w.copy(filename, stream)
+}
+
+func writeNamespaceIntervals(w *artifactWriter, intervals monitorapi.Intervals, namespaces []string) error {
+	wanted := sets.New[string](namespaces...)
+	namespaceIntervals := intervals.Filter(func(interval monitorapi.Interval) bool {
+		return wanted.Has(monitorapi.NamespaceFromLocator(interval.Locator))
+	})
+	if len(namespaceIntervals) == 0 || w.remaining <= 0 {
+		return nil
+	}
+	if err := os.MkdirAll(w.dir, 0755); err != nil {
+		return err
+	}
+	return monitorserialization.EventsToFile(filepath.Join(w.dir, "intervals.json"), namespaceIntervals)
+}
+
+// artifactWriter writes files below dir until remaining bytes have been written, after which files are truncated.
+type artifactWriter struct {
+	dir       string
+	remaining int64
+}
+
+func (w *artifactWriter) writeYAML(filename string, obj interface{}) error {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return w.write(filename, data)
+}
+
+func (w *artifactWriter) write(filename string, data []byte) error {
+	return w.copy(filename, bytes.NewReader(data))
+}
+
+func (w *artifactWriter) copy(filename string, r io.Reader) error {
+	if w.remaining <= 0 {
+		return nil
+	}
+	path := filepath.Join(w.dir, filename)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	n, err := io.Copy(f, io.LimitReader(r, w.remaining))
+	w.remaining -= n
+	if err != nil {
+		return err
+	}
+	if w.remaining <= 0 {
+		fmt.Fprintf(f, "\n... truncated, the limit of %d bytes of artifacts for the test was reached\n", failureArtifactBytesPerTest)
+	}
+	return nil
+}
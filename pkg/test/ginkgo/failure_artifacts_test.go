@@ -0,0 +1,130 @@
+package ginkgo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_testNamespaces(t *testing.T) {
+	output := []byte(`STEP: Building a namespace api object, basename pods
+I0101 00:00:00.000000 1 test_setup.go:80] Created test namespace "e2e-pods-1234"
+I0101 00:00:01.000000 1 client.go:325] Creating project "e2e-test-router-abcde"
+I0101 00:00:01.000000 1 client.go:423] Creating namespace "e2e-test-router-abcde"
+I0101 00:00:02.000000 1 client.go:80] Creating project "openshift-config"
+`)
+	got := testNamespaces(output)
+	want := []string{"e2e-pods-1234", "e2e-test-router-abcde"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func Test_sanitizeTestName(t *testing.T) {
+	name := `[sig-network] Services should "serve" a basic endpoint/from pods [Suite:openshift/conformance/parallel]`
+	got := sanitizeTestName(name)
+	if !strings.HasPrefix(got, "sig-network_Services_should_serve_a_basic_endpoint_from_pods_Suite_openshift_conformance_parallel-") {
+		t.Errorf("unexpected name %s", got)
+	}
+	if sanitizeTestName(name+" ") == got {
+		t.Errorf("different tests must not share a directory")
+	}
+	if long := sanitizeTestName(strings.Repeat("a", 500)); len(long) != 129 {
+		t.Errorf("expected long names to be truncated, got %d characters", len(long))
+	}
+}
+
+func Test_failureArtifactCollector(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "e2e-test-router-abcde"}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "e2e-test-router-abcde", Name: "router"},
+			Status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{
+					{Name: "setup", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}},
+				},
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "router", RestartCount: 1, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+					{Name: "pending", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{}}},
+				},
+			},
+		},
+		&corev1.Event{ObjectMeta: metav1.ObjectMeta{Namespace: "e2e-test-router-abcde", Name: "router.1"}, Reason: "BackOff"},
+	)
+	junitDir := t.TempDir()
+	c := &failureArtifactCollector{
+		kubeClient:          kubeClient,
+		namespacedResources: func() ([]schema.GroupVersionResource, error) { return nil, nil },
+		junitDir:            junitDir,
+		maxTests:            1,
+		deleteNamespaces:    true,
+		captureSlots:        make(chan struct{}, failureArtifactConcurrentCaptures),
+		dirs:                sets.New[string](),
+	}
+	result := &testRunResult{
+		name:            "[sig-network] router should work",
+		testState:       TestFailed,
+		testOutputBytes: []byte(`Creating project "e2e-test-router-abcde"`),
+	}
+
+	dir, err := c.Collect(context.Background(), result, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join("tests", sanitizeTestName(result.name)); dir != want {
+		t.Errorf("got dir %s, want %s", dir, want)
+	}
+	for _, file := range []string{
+		"pods.yaml",
+		"events.yaml",
+		"logs/router/setup.log",
+		"logs/router/router.log",
+		"logs/router/router.previous.log",
+	} {
+		if _, err := os.Stat(filepath.Join(junitDir, dir, "e2e-test-router-abcde", file)); err != nil {
+			t.Errorf("expected %s to be captured: %v", file, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(junitDir, dir, "e2e-test-router-abcde", "logs/router/pending.log")); err == nil {
+		t.Errorf("containers that never started have no logs")
+	}
+	if _, err := kubeClient.CoreV1().Namespaces().Get(context.Background(), "e2e-test-router-abcde", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the namespace to be deleted, got %v", err)
+	}
+
+	// only maxTests tests are captured, the namespaces of the others are still deleted
+	dir, err = c.Collect(context.Background(), result, nil)
+	if err != nil || len(dir) > 0 {
+		t.Errorf("expected no capture past the limit, got %q, %v", dir, err)
+	}
+}
+
+func Test_artifactWriter(t *testing.T) {
+	w := &artifactWriter{dir: t.TempDir(), remaining: 10}
+	if err := w.write("first", []byte("0123456789abcdef")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.write("second", []byte("not written")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(w.dir, "first"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "0123456789\n... truncated") {
+		t.Errorf("unexpected content %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(w.dir, "second")); err == nil {
+		t.Errorf("expected nothing to be written past the limit")
+	}
+}
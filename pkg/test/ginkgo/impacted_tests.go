@@ -0,0 +1,237 @@
+package ginkgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+// ImpactRules decide which changes are too broad to map to individual tests.  Paths are relative to the
+// repository root and match themselves and everything below them.
+type ImpactRules struct {
+	// RunAll paths select every test when changed, for instance shared helpers that nearly every test imports.
+	RunAll []string `json:"runAll,omitempty"`
+	// Ignore paths never select tests, for instance documentation.
+	Ignore []string `json:"ignore,omitempty"`
+}
+
+// DefaultImpactRules are used when no rules file is passed.
+func DefaultImpactRules() *ImpactRules {
+	return &ImpactRules{
+		RunAll: []string{
+			"go.mod",
+			"go.sum",
+			"cmd/openshift-tests",
+			"pkg/test/ginkgo",
+			"test/extended/util",
+			"vendor/github.com/onsi/ginkgo",
+			"vendor/k8s.io/kubernetes/test/e2e/framework",
+		},
+		Ignore: []string{
+			"docs",
+		},
+	}
+}
+
+// ImpactRulesFromFile reads ImpactRules from a YAML file.  Unknown fields are rejected.
+func ImpactRulesFromFile(filename string) (*ImpactRules, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	rules := &ImpactRules{}
+	if err := yaml.UnmarshalStrict(data, rules); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return rules, nil
+}
+
+func (r *ImpactRules) runAll(changedPath string) (string, bool) {
+	return matchPathPrefix(r.RunAll, changedPath)
+}
+
+func (r *ImpactRules) ignore(changedPath string) (string, bool) {
+	return matchPathPrefix(r.Ignore, changedPath)
+}
+
+func matchPathPrefix(prefixes []string, changedPath string) (string, bool) {
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSuffix(path.Clean(prefix), "/")
+		if changedPath == prefix || strings.HasPrefix(changedPath, prefix+"/") {
+			return prefix, true
+		}
+	}
+	return "", false
+}
+
+// importGraph is the import graph of the packages tests are built from, keyed by import path.
+type importGraph struct {
+	// dirsByPackage holds the directory of each package relative to the repository root, vendored packages
+	// included.
+	dirsByPackage map[string]string
+	packagesByDir map[string]string
+	// importers are the reverse edges of the graph.
+	importers map[string]sets.Set[string]
+}
+
+func newImportGraph(dirsByPackage map[string]string, importsByPackage map[string][]string) *importGraph {
+	g := &importGraph{
+		dirsByPackage: dirsByPackage,
+		packagesByDir: map[string]string{},
+		importers:     map[string]sets.Set[string]{},
+	}
+	for pkg, dir := range dirsByPackage {
+		g.packagesByDir[dir] = pkg
+	}
+	for pkg, imports := range importsByPackage {
+		for _, imported := range imports {
+			if _, ok := g.importers[imported]; !ok {
+				g.importers[imported] = sets.New[string]()
+			}
+			g.importers[imported].Insert(pkg)
+		}
+	}
+	return g
+}
+
+// loadImportGraph lists the packages matching patterns and all of their dependencies that live below repoRoot.
+func loadImportGraph(repoRoot string, patterns []string) (*importGraph, error) {
+	absRoot, err := filepath.Abs(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command("go", append([]string{"list", "-deps", "-json=ImportPath,Dir,Imports,Standard"}, patterns...)...)
+	cmd.Dir = absRoot
+	cmd.Stderr = &bytes.Buffer{}
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list packages %v: %w\n%s", patterns, err, cmd.Stderr)
+	}
+
+	dirsByPackage := map[string]string{}
+	importsByPackage := map[string][]string{}
+	decoder := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var pkg struct {
+			ImportPath string
+			Dir        string
+			Imports    []string
+			Standard   bool
+		}
+		if err := decoder.Decode(&pkg); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to decode go list output: %w", err)
+		}
+		if pkg.Standard {
+			continue
+		}
+		dir, err := filepath.Rel(absRoot, pkg.Dir)
+		if err != nil || dir == ".." || strings.HasPrefix(dir, "../") {
+			// modules outside of the repository cannot be changed by it
+			continue
+		}
+		dirsByPackage[pkg.ImportPath] = filepath.ToSlash(dir)
+		importsByPackage[pkg.ImportPath] = pkg.Imports
+	}
+	return newImportGraph(dirsByPackage, importsByPackage), nil
+}
+
+// relativePath returns a changed file, directory or import path as a slash separated path relative to the
+// repository root.
+func (g *importGraph) relativePath(change string) string {
+	if dir, ok := g.dirsByPackage[change]; ok {
+		return dir
+	}
+	return strings.TrimPrefix(path.Clean(filepath.ToSlash(change)), "./")
+}
+
+// packageForPath returns the package of a changed file or package directory.
+func (g *importGraph) packageForPath(changedPath string) (string, bool) {
+	if pkg, ok := g.packagesByDir[changedPath]; ok {
+		return pkg, true
+	}
+	pkg, ok := g.packagesByDir[path.Dir(changedPath)]
+	return pkg, ok
+}
+
+// packageForLocation returns the package of a ginkgo code location.  Locations are absolute paths on the machine
+// the binary was built on, so the longest suffix of the directory that is a package directory wins.
+func (g *importGraph) packageForLocation(fileName string) (string, bool) {
+	dir := path.Dir(filepath.ToSlash(fileName))
+	for {
+		if pkg, ok := g.packagesByDir[dir]; ok {
+			return pkg, true
+		}
+		i := strings.Index(dir, "/")
+		if i < 0 {
+			return "", false
+		}
+		dir = dir[i+1:]
+	}
+}
+
+// affected returns the changed packages and every package that imports one of them, directly or not.
+func (g *importGraph) affected(changed sets.Set[string]) sets.Set[string] {
+	affected := sets.New[string]()
+	queue := sets.List(changed)
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+		if affected.Has(pkg) {
+			continue
+		}
+		affected.Insert(pkg)
+		queue = append(queue, sets.List(g.importers[pkg])...)
+	}
+	return affected
+}
+
+// impactedTests returns the tests with a code location in a package affected by the changes.  The reason each
+// change was used or ignored is written to out.
+func impactedTests(tests []*testCase, graph *importGraph, rules *ImpactRules, changes []string, out io.Writer) []*testCase {
+	changedPackages := sets.New[string]()
+	for _, change := range changes {
+		changedPath := graph.relativePath(change)
+		if prefix, ok := rules.ignore(changedPath); ok {
+			fmt.Fprintf(out, "ignoring %s: matches ignore rule %s\n", changedPath, prefix)
+			continue
+		}
+		if prefix, ok := rules.runAll(changedPath); ok {
+			fmt.Fprintf(out, "selecting all tests: %s matches runAll rule %s\n", changedPath, prefix)
+			return tests
+		}
+		pkg, ok := graph.packageForPath(changedPath)
+		if !ok {
+			fmt.Fprintf(out, "ignoring %s: not part of a package tests are built from\n", changedPath)
+			continue
+		}
+		changedPackages.Insert(pkg)
+	}
+	if changedPackages.Len() == 0 {
+		return nil
+	}
+
+	affected := graph.affected(changedPackages)
+	fmt.Fprintf(out, "%d changed packages affect %d packages\n", changedPackages.Len(), affected.Len())
+
+	var impacted []*testCase
+	for _, test := range tests {
+		for _, location := range test.locations {
+			if pkg, ok := graph.packageForLocation(location.FileName); ok && affected.Has(pkg) {
+				impacted = append(impacted, test)
+				break
+			}
+		}
+	}
+	return impacted
+}
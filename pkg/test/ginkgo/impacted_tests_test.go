@@ -0,0 +1,92 @@
+package ginkgo
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+func Test_impactedTests(t *testing.T) {
+	graph := newImportGraph(
+		map[string]string{
+			"github.com/openshift/origin/test/extended":              "test/extended",
+			"github.com/openshift/origin/test/extended/networking":   "test/extended/networking",
+			"github.com/openshift/origin/test/extended/router":       "test/extended/router",
+			"github.com/openshift/origin/test/extended/router/shard": "test/extended/router/shard",
+			"github.com/openshift/origin/test/extended/util":         "test/extended/util",
+			"k8s.io/kubernetes/test/e2e/network":                     "vendor/k8s.io/kubernetes/test/e2e/network",
+		},
+		map[string][]string{
+			"github.com/openshift/origin/test/extended": {
+				"github.com/openshift/origin/test/extended/networking",
+				"github.com/openshift/origin/test/extended/router",
+				"k8s.io/kubernetes/test/e2e/network",
+			},
+			"github.com/openshift/origin/test/extended/networking": {"github.com/openshift/origin/test/extended/util"},
+			"github.com/openshift/origin/test/extended/router": {
+				"github.com/openshift/origin/test/extended/router/shard",
+				"github.com/openshift/origin/test/extended/util",
+			},
+		},
+	)
+	testAt := func(name string, files ...string) *testCase {
+		tc := &testCase{name: name}
+		for _, file := range files {
+			tc.locations = append(tc.locations, types.CodeLocation{FileName: file})
+		}
+		return tc
+	}
+	tests := []*testCase{
+		testAt("networking", "/go/src/github.com/openshift/origin/test/extended/networking/egress.go"),
+		testAt("router", "/go/src/github.com/openshift/origin/test/extended/router/router.go"),
+		testAt("kube network", "/go/src/github.com/openshift/origin/vendor/k8s.io/kubernetes/test/e2e/network/service.go"),
+		testAt("no locations"),
+	}
+
+	for _, tt := range []struct {
+		name    string
+		changes []string
+		want    []string
+	}{
+		{
+			name:    "changed file",
+			changes: []string{"test/extended/networking/egress.go"},
+			want:    []string{"networking"},
+		},
+		{
+			name:    "changed import",
+			changes: []string{"./test/extended/router/shard/shard.go"},
+			want:    []string{"router"},
+		},
+		{
+			name:    "changed import path",
+			changes: []string{"k8s.io/kubernetes/test/e2e/network"},
+			want:    []string{"kube network"},
+		},
+		{
+			name:    "run all rule",
+			changes: []string{"test/extended/networking/egress.go", "test/extended/util/client.go"},
+			want:    []string{"networking", "router", "kube network", "no locations"},
+		},
+		{
+			name:    "ignored and unknown paths",
+			changes: []string{"docs/README.md", "images/tests/Dockerfile"},
+		},
+		{
+			name:    "run all rule does not match a sibling with the same prefix",
+			changes: []string{"test/extended/utility/foo.go"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, test := range impactedTests(tests, graph, DefaultImpactRules(), tt.changes, io.Discard) {
+				got = append(got, test.name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
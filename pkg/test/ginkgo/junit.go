@@ -37,9 +37,10 @@ func generateJUnitTestSuiteResults(
 			s.NumTests++
 			s.NumSkipped++
 			s.TestCases = append(s.TestCases, &junitapi.JUnitTestCase{
-				Name:      test.name,
-				SystemOut: string(test.testOutputBytes),
-				Duration:  test.duration.Seconds(),
+				Name:       test.name,
+				SystemOut:  string(test.testOutputBytes),
+				Duration:   test.duration.Seconds(),
+				Properties: test.source.junitProperties(),
 				SkipMessage: &junitapi.SkipMessage{
 					Message: lastLinesUntil(string(test.testOutputBytes), 100, "skip ["),
 				},
@@ -48,20 +49,22 @@ func generateJUnitTestSuiteResults(
 			s.NumTests++
 			s.NumFailed++
 			s.TestCases = append(s.TestCases, &junitapi.JUnitTestCase{
-				Name:      test.name,
-				SystemOut: string(test.testOutputBytes),
-				Duration:  test.duration.Seconds(),
+				Name:       test.name,
+				SystemOut:  string(test.testOutputBytes),
+				Duration:   test.duration.Seconds(),
+				Properties: test.source.junitProperties(),
 				FailureOutput: &junitapi.FailureOutput{
-					Output: lastLinesUntil(string(test.testOutputBytes), 100, "fail ["),
+					Output: failureOutputWithArtifacts(test),
 				},
 			})
 		case test.flake:
 			s.NumTests++
 			s.NumFailed++
 			s.TestCases = append(s.TestCases, &junitapi.JUnitTestCase{
-				Name:      test.name,
-				SystemOut: string(test.testOutputBytes),
-				Duration:  test.duration.Seconds(),
+				Name:       test.name,
+				SystemOut:  string(test.testOutputBytes),
+				Duration:   test.duration.Seconds(),
+				Properties: test.source.junitProperties(),
 				FailureOutput: &junitapi.FailureOutput{
 					Output: lastLinesUntil(string(test.testOutputBytes), 100, "flake:"),
 				},
@@ -70,14 +73,16 @@ func generateJUnitTestSuiteResults(
 			// also add the successful junit result:
 			s.NumTests++
 			s.TestCases = append(s.TestCases, &junitapi.JUnitTestCase{
-				Name:     test.name,
-				Duration: test.duration.Seconds(),
+				Name:       test.name,
+				Duration:   test.duration.Seconds(),
+				Properties: test.source.junitProperties(),
 			})
 		case test.success:
 			s.NumTests++
 			s.TestCases = append(s.TestCases, &junitapi.JUnitTestCase{
-				Name:     test.name,
-				Duration: test.duration.Seconds(),
+				Name:       test.name,
+				Duration:   test.duration.Seconds(),
+				Properties: test.source.junitProperties(),
 			})
 		}
 	}
@@ -94,6 +99,15 @@ func generateJUnitTestSuiteResults(
 	return s
 }
 
+// failureOutputWithArtifacts links the artifacts captured from the namespaces of a failed test.
+func failureOutputWithArtifacts(test *testCase) string {
+	output := lastLinesUntil(string(test.testOutputBytes), 100, "fail [")
+	if len(test.failureArtifacts) == 0 {
+		return output
+	}
+	return fmt.Sprintf("%s\n\nArtifacts of the test namespaces: %s", output, filepath.ToSlash(test.failureArtifacts))
+}
+
 func writeJUnitReport(s *junitapi.JUnitTestSuite, filePrefix, fileSuffix, dir string, errOut io.Writer) error {
 	out, err := xml.MarshalIndent(s, "", "    ")
 	if err != nil {
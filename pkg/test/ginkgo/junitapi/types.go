@@ -77,6 +77,14 @@ type JUnitTestCase struct {
 
 	// SystemErr is output written to stderr during the execution of this test case
 	SystemErr string `xml:"system-err,omitempty"`
+
+	// Properties holds other properties of the test case, like the binary that provided it
+	Properties *TestCaseProperties `xml:"properties,omitempty"`
+}
+
+// TestCaseProperties holds the properties of a test case
+type TestCaseProperties struct {
+	Properties []*TestSuiteProperty `xml:"property"`
 }
 
 // SkipMessage holds a message explaining why a test was skipped
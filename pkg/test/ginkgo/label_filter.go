@@ -0,0 +1,293 @@
+package ginkgo
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+var testNameLabelRegex = regexp.MustCompile(`\[([^\[\]]+)\]`)
+
+// LabelsFromTestName returns the bracketed tags of a test name, for instance sig-network, Slow and
+// Suite:openshift/conformance/parallel for "[sig-network] does a thing [Slow] [Suite:openshift/conformance/parallel]".
+func LabelsFromTestName(name string) sets.Set[string] {
+	labels := sets.New[string]()
+	for _, match := range testNameLabelRegex.FindAllStringSubmatch(name, -1) {
+		labels.Insert(match[1])
+	}
+	return labels
+}
+
+// LabelFilter is a boolean expression over test labels, like
+//
+//	sig-network && !Slow && (Feature:EgressIP || apigroup:route.openshift.io)
+//
+// Labels are matched exactly.  Labels containing whitespace or any of &|!()" must be double quoted.
+type LabelFilter struct {
+	expression string
+	root       labelExpression
+}
+
+// ParseLabelFilter parses a label filter expression.
+func ParseLabelFilter(expression string) (*LabelFilter, error) {
+	tokens, err := tokenizeLabelFilter(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label filter %q: %w", expression, err)
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("invalid label filter %q: empty expression", expression)
+	}
+	p := &labelFilterParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %q", p.tokens[p.pos].value)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid label filter %q: %w", expression, err)
+	}
+	return &LabelFilter{expression: expression, root: root}, nil
+}
+
+// Matches returns true if the labels satisfy the filter.
+func (f *LabelFilter) Matches(labels sets.Set[string]) bool {
+	return f.root.matches(labels)
+}
+
+// Explain returns the filter with the value of every label, for instance "sig-network(true) && !Slow(true)".
+func (f *LabelFilter) Explain(labels sets.Set[string]) string {
+	return f.root.explain(labels)
+}
+
+func (f *LabelFilter) String() string {
+	return f.expression
+}
+
+type labelExpression interface {
+	matches(labels sets.Set[string]) bool
+	explain(labels sets.Set[string]) string
+}
+
+type labelIs string
+
+func (e labelIs) matches(labels sets.Set[string]) bool {
+	return labels.Has(string(e))
+}
+
+func (e labelIs) explain(labels sets.Set[string]) string {
+	label := string(e)
+	if strings.ContainsAny(label, labelFilterSpecialCharacters) {
+		label = fmt.Sprintf("%q", label)
+	}
+	return fmt.Sprintf("%s(%v)", label, e.matches(labels))
+}
+
+type labelNot struct {
+	expression labelExpression
+}
+
+func (e labelNot) matches(labels sets.Set[string]) bool {
+	return !e.expression.matches(labels)
+}
+
+func (e labelNot) explain(labels sets.Set[string]) string {
+	return "!" + explainOperand(e.expression, labels)
+}
+
+type labelAnd []labelExpression
+
+func (e labelAnd) matches(labels sets.Set[string]) bool {
+	for _, curr := range e {
+		if !curr.matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e labelAnd) explain(labels sets.Set[string]) string {
+	parts := []string{}
+	for _, curr := range e {
+		parts = append(parts, explainOperand(curr, labels))
+	}
+	return strings.Join(parts, " && ")
+}
+
+type labelOr []labelExpression
+
+func (e labelOr) matches(labels sets.Set[string]) bool {
+	for _, curr := range e {
+		if curr.matches(labels) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e labelOr) explain(labels sets.Set[string]) string {
+	parts := []string{}
+	for _, curr := range e {
+		parts = append(parts, explainOperand(curr, labels))
+	}
+	return strings.Join(parts, " || ")
+}
+
+// explainOperand parenthesizes and and or expressions so the explanation keeps the meaning of the filter.
+func explainOperand(e labelExpression, labels sets.Set[string]) string {
+	switch e.(type) {
+	case labelAnd, labelOr:
+		return "(" + e.explain(labels) + ")"
+	default:
+		return e.explain(labels)
+	}
+}
+
+const labelFilterSpecialCharacters = " \t\r\n&|!()\""
+
+type labelFilterTokenType int
+
+const (
+	labelToken labelFilterTokenType = iota
+	andToken
+	orToken
+	notToken
+	openToken
+	closeToken
+)
+
+type labelFilterToken struct {
+	tokenType labelFilterTokenType
+	value     string
+}
+
+func tokenizeLabelFilter(expression string) ([]labelFilterToken, error) {
+	var tokens []labelFilterToken
+	for i := 0; i < len(expression); {
+		switch c := expression[i]; {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case strings.HasPrefix(expression[i:], "&&"):
+			tokens = append(tokens, labelFilterToken{tokenType: andToken, value: "&&"})
+			i += 2
+		case strings.HasPrefix(expression[i:], "||"):
+			tokens = append(tokens, labelFilterToken{tokenType: orToken, value: "||"})
+			i += 2
+		case c == '!':
+			tokens = append(tokens, labelFilterToken{tokenType: notToken, value: "!"})
+			i++
+		case c == '(':
+			tokens = append(tokens, labelFilterToken{tokenType: openToken, value: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, labelFilterToken{tokenType: closeToken, value: ")"})
+			i++
+		case c == '&' || c == '|':
+			return nil, fmt.Errorf("unexpected %q at %d, use && or ||", c, i)
+		case c == '"':
+			end := strings.IndexByte(expression[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quote at %d", i)
+			}
+			label := expression[i+1 : i+1+end]
+			if len(label) == 0 {
+				return nil, fmt.Errorf("empty label at %d", i)
+			}
+			tokens = append(tokens, labelFilterToken{tokenType: labelToken, value: label})
+			i += end + 2
+		default:
+			end := strings.IndexAny(expression[i:], labelFilterSpecialCharacters)
+			if end < 0 {
+				end = len(expression) - i
+			}
+			tokens = append(tokens, labelFilterToken{tokenType: labelToken, value: expression[i : i+end]})
+			i += end
+		}
+	}
+	return tokens, nil
+}
+
+// labelFilterParser is a recursive descent parser for
+//
+//	or    = and { "||" and }
+//	and   = unary { "&&" unary }
+//	unary = "!" unary | "(" or ")" | label
+type labelFilterParser struct {
+	tokens []labelFilterToken
+	pos    int
+}
+
+func (p *labelFilterParser) next(tokenType labelFilterTokenType) bool {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].tokenType == tokenType {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *labelFilterParser) parseOr() (labelExpression, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	or := labelOr{first}
+	for p.next(orToken) {
+		curr, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, curr)
+	}
+	if len(or) == 1 {
+		return first, nil
+	}
+	return or, nil
+}
+
+func (p *labelFilterParser) parseAnd() (labelExpression, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	and := labelAnd{first}
+	for p.next(andToken) {
+		curr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, curr)
+	}
+	if len(and) == 1 {
+		return first, nil
+	}
+	return and, nil
+}
+
+func (p *labelFilterParser) parseUnary() (labelExpression, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	token := p.tokens[p.pos]
+	p.pos++
+	switch token.tokenType {
+	case notToken:
+		expression, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return labelNot{expression: expression}, nil
+	case openToken:
+		expression, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.next(closeToken) {
+			return nil, fmt.Errorf("missing )")
+		}
+		return expression, nil
+	case labelToken:
+		return labelIs(token.value), nil
+	default:
+		return nil, fmt.Errorf("unexpected %q", token.value)
+	}
+}
@@ -0,0 +1,105 @@
+package ginkgo
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestLabelsFromTestName(t *testing.T) {
+	got := LabelsFromTestName("[sig-network][Feature:EgressIP] egress works [apigroup:route.openshift.io] [Suite:openshift/conformance/parallel]")
+	want := sets.New[string]("sig-network", "Feature:EgressIP", "apigroup:route.openshift.io", "Suite:openshift/conformance/parallel")
+	if !got.Equal(want) {
+		t.Errorf("got %v, want %v", sets.List(got), sets.List(want))
+	}
+}
+
+func TestLabelFilter(t *testing.T) {
+	tests := []struct {
+		name        string
+		expression  string
+		testName    string
+		want        bool
+		wantExplain string
+	}{
+		{
+			name:        "single label",
+			expression:  "sig-network",
+			testName:    "[sig-network] does a thing",
+			want:        true,
+			wantExplain: "sig-network(true)",
+		},
+		{
+			name:        "negation",
+			expression:  "sig-network && !Slow",
+			testName:    "[sig-network] does a thing [Slow]",
+			want:        false,
+			wantExplain: "sig-network(true) && !Slow(true)",
+		},
+		{
+			name:        "grouping",
+			expression:  "sig-network && !Slow && (Feature:EgressIP || apigroup:route.openshift.io)",
+			testName:    "[sig-network] does a thing [apigroup:route.openshift.io]",
+			want:        true,
+			wantExplain: "sig-network(true) && !Slow(false) && (Feature:EgressIP(false) || apigroup:route.openshift.io(true))",
+		},
+		{
+			name:        "and binds tighter than or",
+			expression:  "sig-network || sig-storage && Slow",
+			testName:    "[sig-storage] does a thing",
+			want:        false,
+			wantExplain: "sig-network(false) || (sig-storage(true) && Slow(false))",
+		},
+		{
+			name:        "quoted label",
+			expression:  `"Skipped:Disconnected" || "Feature:A B"`,
+			testName:    "does a thing [Feature:A B]",
+			want:        true,
+			wantExplain: `Skipped:Disconnected(false) || "Feature:A B"(true)`,
+		},
+		{
+			name:        "double negation",
+			expression:  "!!Serial",
+			testName:    "does a thing [Serial]",
+			want:        true,
+			wantExplain: "!!Serial(true)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParseLabelFilter(tt.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := f.Matches(LabelsFromTestName(tt.testName)); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+			if got := f.Explain(LabelsFromTestName(tt.testName)); got != tt.wantExplain {
+				t.Errorf("Explain() = %q, want %q", got, tt.wantExplain)
+			}
+		})
+	}
+}
+
+func TestParseLabelFilterErrors(t *testing.T) {
+	for _, expression := range []string{
+		"",
+		"   ",
+		"sig-network &",
+		"sig-network | Slow",
+		"sig-network &&",
+		"(sig-network",
+		"sig-network)",
+		"sig-network Slow",
+		`"unterminated`,
+		`""`,
+		"!",
+		"&& Slow",
+	} {
+		t.Run(expression, func(t *testing.T) {
+			if _, err := ParseLabelFilter(expression); err == nil {
+				t.Errorf("expected an error for %q", expression)
+			}
+		})
+	}
+}
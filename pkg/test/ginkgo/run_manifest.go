@@ -0,0 +1,152 @@
+package ginkgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	earlyBatch = "early"
+	lateBatch  = "late"
+)
+
+// RunManifest records how a suite was run: the seed the tests were shuffled with, how they were selected and the
+// batches they were run in.  Passing it to --replay-manifest runs the same tests in the same order and batches.
+type RunManifest struct {
+	// Version is the version of openshift-tests that wrote the manifest.
+	Version string `json:"version"`
+	Suite   string `json:"suite"`
+	// Seed is the ginkgo random seed the tests were shuffled with.
+	Seed int64 `json:"seed"`
+	// CommandLine holds the arguments of the run, including the flags that selected tests.
+	CommandLine []string `json:"commandLine"`
+	LabelFilter string   `json:"labelFilter,omitempty"`
+	// SkippedByCheck is the number of tests each applicability check skipped.
+	SkippedByCheck map[string]int `json:"skippedByCheck,omitempty"`
+	// Count is the number of times tests were run.  When -1, the batches other than early and late were repeated
+	// until the run was interrupted.
+	Count    int      `json:"count"`
+	Monitors []string `json:"monitors"`
+	// Batches are run one after the other, in order.
+	Batches []RunManifestBatch `json:"batches"`
+}
+
+// RunManifestBatch is a set of tests that is run in parallel, with serial tests run after the others.
+type RunManifestBatch struct {
+	Name        string `json:"name"`
+	Parallelism int    `json:"parallelism"`
+	// Tests are queued in this order.
+	Tests []string `json:"tests"`
+}
+
+// RunManifestFromFile reads a manifest written by a previous run.
+func RunManifestFromFile(filename string) (*RunManifest, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &RunManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	if len(manifest.Suite) == 0 || len(manifest.Batches) == 0 {
+		return nil, fmt.Errorf("%s: not a run manifest, it has no suite or batches", filename)
+	}
+	return manifest, nil
+}
+
+func writeRunManifest(manifest *RunManifest, junitDir, timeSuffix string) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(junitDir, fmt.Sprintf("run-manifest%s.json", timeSuffix)), data, 0644)
+}
+
+type testBatch struct {
+	name        string
+	parallelism int
+	tests       []*testCase
+}
+
+// testPlan holds the batches of a run.  The early batches are run first and the late batches last, the others are
+// repeated when running tests forever.
+type testPlan struct {
+	early   []testBatch
+	primary []testBatch
+	late    []testBatch
+}
+
+func (p *testPlan) batches() []testBatch {
+	return append(append(append([]testBatch{}, p.early...), p.primary...), p.late...)
+}
+
+func (p *testPlan) add(batch testBatch) {
+	switch batch.name {
+	case earlyBatch:
+		p.early = append(p.early, batch)
+	case lateBatch:
+		p.late = append(p.late, batch)
+	default:
+		p.primary = append(p.primary, batch)
+	}
+}
+
+func (p *testPlan) manifestBatches() []RunManifestBatch {
+	var batches []RunManifestBatch
+	for _, batch := range p.batches() {
+		batches = append(batches, RunManifestBatch{
+			Name:        batch.name,
+			Parallelism: batch.parallelism,
+			Tests:       testNames(batch.tests),
+		})
+	}
+	return batches
+}
+
+// replayTestPlan returns the batches of the manifest with the tests of the same name.  Tests that are listed more
+// than once, because of --count, are copied.
+func replayTestPlan(manifest *RunManifest, tests []*testCase) (*testPlan, error) {
+	testsByName := map[string]*testCase{}
+	for _, test := range tests {
+		testsByName[test.name] = test
+	}
+
+	plan := &testPlan{}
+	var missing []string
+	for _, batch := range manifest.Batches {
+		if batch.Parallelism < 1 {
+			return nil, fmt.Errorf("batch %q must have a parallelism of at least 1", batch.Name)
+		}
+		replayed := testBatch{name: batch.Name, parallelism: batch.Parallelism}
+		for _, name := range batch.Tests {
+			test, ok := testsByName[name]
+			if !ok {
+				missing = append(missing, name)
+				continue
+			}
+			copied := *test
+			replayed.tests = append(replayed.tests, &copied)
+		}
+		plan.add(replayed)
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("%d tests of the manifest do not exist in this binary:\n%s", len(missing), strings.Join(missing, "\n"))
+	}
+	return plan, nil
+}
+
+// replayCount returns the count the manifest was run with.  Tests repeated by a count above 1 are already listed
+// more than once in the batches, but a count of -1 has to repeat the primary batches until the run is interrupted.
+// A --count that differs from the manifest's is rejected rather than silently ignored.
+func replayCount(manifest *RunManifest, count int) (int, error) {
+	if count != 0 && count != manifest.Count {
+		return 0, fmt.Errorf("--count=%d does not match the count of %d the manifest was run with", count, manifest.Count)
+	}
+	return manifest.Count, nil
+}
@@ -0,0 +1,110 @@
+package ginkgo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_replayTestPlan(t *testing.T) {
+	var tests []*testCase
+	for _, name := range []string{
+		"[sig-arch] early [Early]",
+		"[sig-node] kube [Suite:k8s]",
+		"[sig-storage] storage [Suite:k8s]",
+		"[sig-network] openshift",
+		"[sig-network] openshift [Serial]",
+		"[sig-arch] late [Late]",
+	} {
+		tests = append(tests, &testCase{name: name})
+	}
+
+	plan := (&GinkgoRunSuiteOptions{}).testPlan(tests, 2, 4)
+	manifest := &RunManifest{Suite: "openshift/conformance", Seed: 42, Batches: plan.manifestBatches()}
+
+	var names []string
+	var parallelism []int
+	for _, batch := range manifest.Batches {
+		names = append(names, batch.Name)
+		parallelism = append(parallelism, batch.Parallelism)
+	}
+	if want := []string{"early", "kube", "storage", "openshift", "must-gather", "late"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got batches %v, want %v", names, want)
+	}
+	if want := []int{4, 4, 2, 4, 4, 4}; !reflect.DeepEqual(parallelism, want) {
+		t.Errorf("got parallelism %v, want %v", parallelism, want)
+	}
+	if want := []string{"[sig-network] openshift", "[sig-network] openshift [Serial]", "[sig-network] openshift", "[sig-network] openshift [Serial]"}; !reflect.DeepEqual(manifest.Batches[3].Tests, want) {
+		t.Errorf("expected --count to repeat the openshift tests, got %v", manifest.Batches[3].Tests)
+	}
+
+	replayed, err := replayTestPlan(manifest, tests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(replayed.manifestBatches(), manifest.Batches) {
+		t.Errorf("replayed batches differ:\n%#v\n%#v", replayed.manifestBatches(), manifest.Batches)
+	}
+	if len(replayed.early) != 1 || len(replayed.late) != 1 || len(replayed.primary) != 4 {
+		t.Errorf("unexpected phases: %d early, %d primary, %d late", len(replayed.early), len(replayed.primary), len(replayed.late))
+	}
+	if openshift := replayed.primary[2].tests; openshift[0] == openshift[2] {
+		t.Errorf("repeated tests must be copies")
+	}
+
+	_, err = replayTestPlan(manifest, tests[1:])
+	if err == nil || !strings.Contains(err.Error(), "[sig-arch] early [Early]") {
+		t.Errorf("expected an error naming the missing test, got %v", err)
+	}
+}
+
+func Test_replayCount(t *testing.T) {
+	for _, tc := range []struct {
+		manifestCount, count, want int
+		wantErr                    bool
+	}{
+		{manifestCount: 1, count: 0, want: 1},
+		{manifestCount: -1, count: 0, want: -1},
+		{manifestCount: 3, count: 3, want: 3},
+		{manifestCount: -1, count: 1, wantErr: true},
+	} {
+		got, err := replayCount(&RunManifest{Count: tc.manifestCount}, tc.count)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("count %d of manifest with count %d: unexpected error %v", tc.count, tc.manifestCount, err)
+		}
+		if got != tc.want {
+			t.Errorf("count %d of manifest with count %d: got %d, want %d", tc.count, tc.manifestCount, got, tc.want)
+		}
+	}
+}
+
+func TestRunManifestFromFile(t *testing.T) {
+	dir := t.TempDir()
+	manifest := &RunManifest{
+		Version:  "v4.16.0",
+		Suite:    "openshift/conformance",
+		Seed:     42,
+		Monitors: []string{"e2e-test-analyzer"},
+		Batches:  []RunManifestBatch{{Name: "early", Parallelism: 1, Tests: []string{"a"}}},
+	}
+	if err := writeRunManifest(manifest, dir, "_20240101-000000"); err != nil {
+		t.Fatal(err)
+	}
+	read, err := RunManifestFromFile(filepath.Join(dir, "run-manifest_20240101-000000.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, manifest) {
+		t.Errorf("got %#v, want %#v", read, manifest)
+	}
+
+	junit := filepath.Join(dir, "junit.json")
+	if err := os.WriteFile(junit, []byte(`{"name": "suite"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RunManifestFromFile(junit); err == nil {
+		t.Errorf("expected an error for a file that is not a manifest")
+	}
+}
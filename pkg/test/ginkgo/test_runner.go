@@ -56,6 +56,13 @@ func (r *testSuiteRunnerImpl) RunOneTest(ctx context.Context, test *testCase) {
 	defer recordTestResultInLogWithoutOverlap(testRunResult, r.testOutput.testOutputLock, r.testOutput.out, r.testOutput.includeSuccessfulOutput)
 
 	testRunResult.testRunResult = r.commandContext.RunTestInNewProcess(ctx, test)
+	if r.testOutput.failureArtifacts != nil {
+		dir, err := r.testOutput.failureArtifacts.Collect(ctx, testRunResult.testRunResult, r.testOutput.monitorRecorder)
+		if err != nil {
+			testRunResult.testOutputBytes = append(testRunResult.testOutputBytes, []byte(fmt.Sprintf("\nerror: %v\n", err))...)
+		}
+		testRunResult.failureArtifacts = dir
+	}
 	mutateTestCaseWithResults(test, testRunResult)
 }
 
@@ -71,6 +78,7 @@ func mutateTestCaseWithResults(test *testCase, testRunResult *testRunResultHandl
 	test.duration = duration
 
 	test.testOutputBytes = testRunResult.testOutputBytes
+	test.failureArtifacts = testRunResult.failureArtifacts
 
 	switch testRunResult.testState {
 	case TestFlaked:
@@ -125,6 +133,8 @@ type testOutputConfig struct {
 	testOutputLock  *sync.Mutex
 	out             io.Writer
 	monitorRecorder monitorapi.Recorder
+	// failureArtifacts captures the namespaces of failed tests when set.
+	failureArtifacts *failureArtifactCollector
 
 	includeSuccessfulOutput bool
 }
@@ -139,6 +149,9 @@ type testRunResult struct {
 	end             time.Time
 	testState       TestState
 	testOutputBytes []byte
+	// failureArtifacts is the directory the namespaces of the failed test were captured to, relative to the junit
+	// directory.
+	failureArtifacts string
 }
 
 func (r testRunResult) duration() time.Duration {
@@ -173,11 +186,12 @@ func isTestFailed(testState TestState) bool {
 }
 
 // testOutputLock prevents parallel tests from interleaving their output.
-func newTestOutputConfig(testOutputLock *sync.Mutex, out io.Writer, monitorRecorder monitorapi.Recorder, includeSuccessfulOutput bool) testOutputConfig {
+func newTestOutputConfig(testOutputLock *sync.Mutex, out io.Writer, monitorRecorder monitorapi.Recorder, failureArtifacts *failureArtifactCollector, includeSuccessfulOutput bool) testOutputConfig {
 	return testOutputConfig{
 		testOutputLock:          testOutputLock,
 		out:                     out,
 		monitorRecorder:         monitorRecorder,
+		failureArtifacts:        failureArtifacts,
 		includeSuccessfulOutput: includeSuccessfulOutput,
 	}
 }
@@ -249,6 +263,9 @@ func recordTestResultInLog(testRunResult *testRunResultHandle, out io.Writer, in
 	case TestFailed, TestFailedTimeout:
 		out.Write(testRunResult.testOutputBytes)
 		fmt.Fprintln(out)
+		if len(testRunResult.failureArtifacts) > 0 {
+			fmt.Fprintf(out, "artifacts of the test namespaces: %s\n", testRunResult.failureArtifacts)
+		}
 		fmt.Fprintf(out, "failed: (%s) %s %q\n\n", testRunResult.duration(), testRunResult.end.UTC().Format("2006-01-02T15:04:05"), testRunResult.name)
 	default:
 		out.Write(testRunResult.testOutputBytes)
@@ -1,6 +1,8 @@
 package ginkgo
 
 import (
+	"fmt"
+	"io"
 	"regexp"
 	"time"
 
@@ -8,6 +10,7 @@ import (
 	"github.com/onsi/ginkgo/v2/types"
 
 	"k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	origingenerated "github.com/openshift/origin/test/extended/util/annotate/generated"
 	k8sgenerated "k8s.io/kubernetes/openshift-hack/e2e/annotate/generated"
@@ -77,6 +80,10 @@ type testCase struct {
 	binaryName string
 	spec       types.TestSpec
 	locations  []types.CodeLocation
+	// source records where a test from an external binary came from, it is nil for tests built into openshift-tests
+	source *externalTestSource
+	// externalLabels are the labels reported by an external binary, in addition to the labels in the name
+	externalLabels []string
 
 	// identifies which tests can be run in parallel (ginkgo runs suites linearly)
 	testExclusion string
@@ -95,21 +102,37 @@ type testCase struct {
 	success  bool
 	timedOut bool
 
+	// failureArtifacts is the directory the namespaces of the failed test were captured to, relative to the junit
+	// directory.
+	failureArtifacts string
+
+	// skipReason is set when the test was skipped because it does not apply to the cluster
+	skipReason *testSkipReason
+
 	previous *testCase
 }
 
 func (t *testCase) Retry() *testCase {
 	copied := &testCase{
-		name:          t.name,
-		spec:          t.spec,
-		locations:     t.locations,
-		testExclusion: t.testExclusion,
+		name:           t.name,
+		rawName:        t.rawName,
+		binaryName:     t.binaryName,
+		source:         t.source,
+		externalLabels: t.externalLabels,
+		spec:           t.spec,
+		locations:      t.locations,
+		testExclusion:  t.testExclusion,
 
 		previous: t,
 	}
 	return copied
 }
 
+// labels returns the bracketed tags of the test name and any labels reported by an external binary.
+func (t *testCase) labels() sets.Set[string] {
+	return LabelsFromTestName(t.name).Insert(t.externalLabels...)
+}
+
 type ClusterStabilityDuringTest string
 
 var (
@@ -127,6 +150,9 @@ type TestSuite struct {
 	Description string
 
 	Matches TestMatchFunc
+	// MatchesLabels, when set, is used instead of Matches to select the tests of the suite.  It is given the labels
+	// of the test, including those reported by an external binary that are not part of the test name.
+	MatchesLabels TestLabelMatchFunc
 
 	// The number of times to execute each test in this suite.
 	Count int
@@ -138,14 +164,38 @@ type TestSuite struct {
 	ClusterStabilityDuringTest ClusterStabilityDuringTest
 
 	TestTimeout time.Duration
+
+	// LabelFilter, when set, must also match the labels of a test for the test to be part of the suite.
+	LabelFilter *LabelFilter
+
+	// ExactMonitorTests, when set, are the only monitor tests enabled for this suite unless --monitor is passed.
+	ExactMonitorTests []string
+	// DisableMonitorTests are monitor tests disabled for this suite in addition to --disable-monitor.
+	DisableMonitorTests []string
+
+	applicabilityChecks []testApplicabilityCheck
 }
 
 type TestMatchFunc func(name string) bool
 
+// TestLabelMatchFunc matches a test by its name and labels.
+type TestLabelMatchFunc func(name string, labels sets.Set[string]) bool
+
+// matches returns true if the test belongs to the suite, before the LabelFilter is applied.
+func (s *TestSuite) matches(test *testCase) bool {
+	if s.MatchesLabels != nil {
+		return s.MatchesLabels(test.name, test.labels())
+	}
+	return s.Matches(test.name)
+}
+
 func (s *TestSuite) Filter(tests []*testCase) []*testCase {
 	matches := make([]*testCase, 0, len(tests))
 	for _, test := range tests {
-		if !s.Matches(test.name) {
+		if !s.matches(test) {
+			continue
+		}
+		if s.LabelFilter != nil && !s.LabelFilter.Matches(test.labels()) {
 			continue
 		}
 		matches = append(matches, test)
@@ -153,10 +203,35 @@ func (s *TestSuite) Filter(tests []*testCase) []*testCase {
 	return matches
 }
 
+// ExplainFilter writes whether each test of the suite is included by the LabelFilter and why.  Tests that the suite
+// does not match are not listed.
+func (s *TestSuite) ExplainFilter(tests []*testCase, out io.Writer) {
+	if s.LabelFilter == nil {
+		return
+	}
+	for _, test := range sortedTests(tests) {
+		if !s.matches(test) {
+			continue
+		}
+		labels := test.labels()
+		verdict := "excluded"
+		if s.LabelFilter.Matches(labels) {
+			verdict = "included"
+		}
+		fmt.Fprintf(out, "%s by --label-filter: %s: %q\n", verdict, s.LabelFilter.Explain(labels), test.name)
+	}
+}
+
 func (s *TestSuite) AddRequiredMatchFunc(matchFn TestMatchFunc) {
 	if matchFn == nil {
 		return
 	}
+	if s.MatchesLabels != nil {
+		originalLabelMatchFn := s.MatchesLabels
+		s.MatchesLabels = func(name string, labels sets.Set[string]) bool {
+			return originalLabelMatchFn(name, labels) && matchFn(name)
+		}
+	}
 	if s.Matches == nil {
 		s.Matches = matchFn
 		return
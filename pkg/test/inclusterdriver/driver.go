@@ -0,0 +1,461 @@
+package inclusterdriver
+
+import (
+	"archive/tar"
+	"bufio"
+	"context"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	// DefaultImage is the tests image mirrored into the cluster, the same image the in-cluster disruption monitors use.
+	DefaultImage = "image-registry.openshift-image-registry.svc:5000/openshift/tests:latest"
+
+	// InputDir holds the kubeconfig and Options.InputFiles in the driver pod.
+	InputDir = "/var/run/openshift-tests/input"
+	// ArtifactDir is copied out of the driver pod when the run ends, see Options.ArtifactDirs.
+	ArtifactDir = "/var/run/openshift-tests/artifacts"
+
+	testContainer      = "test"
+	artifactsContainer = "artifacts"
+	inputConfigMap     = "test-driver-input"
+	kubeconfigKey      = "kubeconfig"
+
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+)
+
+var (
+	//go:embed manifests/namespace.yaml
+	namespaceYaml []byte
+	//go:embed manifests/serviceaccount.yaml
+	serviceAccountYaml []byte
+	//go:embed manifests/crb-cluster-admin.yaml
+	rbacClusterAdminYaml []byte
+	//go:embed manifests/pod.yaml
+	podYaml []byte
+)
+
+// Options describe an openshift-tests invocation to run in the cluster.
+type Options struct {
+	// Image contains the openshift-tests binary, DefaultImage when empty.
+	Image string
+	// Args follow "openshift-tests run" in the driver pod.
+	Args []string
+	// Env is added to the environment of the driver pod.
+	Env []corev1.EnvVar
+	// InputFiles are written to InputDir in the driver pod, keyed by file name.
+	InputFiles map[string][]byte
+	// ArtifactDirs maps directories below ArtifactDir in the pod to local directories they are copied to when
+	// the run ends.
+	ArtifactDirs map[string]string
+	// Timeout bounds the lifetime of the Job, so that it is cleaned up even if the local process goes away.
+	Timeout time.Duration
+
+	Out    io.Writer
+	ErrOut io.Writer
+}
+
+// Run creates the driver Job, streams the output of the test container to Out until it exits, copies the artifacts
+// out and cleans up.  It returns the exit code of the test container.
+func Run(ctx context.Context, config *rest.Config, o Options) (int, error) {
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return 0, err
+	}
+	configClient, err := configclient.NewForConfig(config)
+	if err != nil {
+		return 0, err
+	}
+	infra, err := configClient.ConfigV1().Infrastructures().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return 0, err
+	}
+
+	namespace, err := createNamespace(ctx, kubeClient)
+	if err != nil {
+		return 0, err
+	}
+	fmt.Fprintf(o.ErrOut, "Running the test driver in namespace %s\n", namespace)
+	defer func() {
+		// the run context may already be cancelled, clean up anyway
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if err := deleteTestBed(cleanupCtx, kubeClient, namespace); err != nil {
+			fmt.Fprintf(o.ErrOut, "Unable to clean up the test driver: %v\n", err)
+		}
+	}()
+
+	if err := createServiceAccount(ctx, kubeClient, namespace); err != nil {
+		return 0, err
+	}
+	if err := createRBACClusterAdmin(ctx, kubeClient, namespace); err != nil {
+		return 0, err
+	}
+	if err := createInputConfigMap(ctx, kubeClient, namespace, infra.Status.APIServerInternalURL, o.InputFiles); err != nil {
+		return 0, err
+	}
+	if err := createJob(ctx, kubeClient, namespace, o); err != nil {
+		return 0, err
+	}
+
+	pod, err := waitForTestContainerStart(ctx, kubeClient, namespace)
+	if err != nil {
+		return 0, err
+	}
+	fmt.Fprintf(o.ErrOut, "Test driver pod %s is running on node %s\n", pod.Name, pod.Spec.NodeName)
+
+	exitCode, pod, err := followTestContainer(ctx, kubeClient, pod, o.Out, o.ErrOut)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(o.ArtifactDirs) > 0 {
+		if status := containerStatus(pod, artifactsContainer); status == nil || status.State.Running == nil {
+			return 0, fmt.Errorf("the artifacts of the test driver were lost, the artifacts container is not running on node %s", pod.Spec.NodeName)
+		}
+		if err := copyArtifacts(ctx, kubeClient, config, pod, o.ArtifactDirs); err != nil {
+			return 0, fmt.Errorf("unable to copy artifacts out of the test driver: %w", err)
+		}
+	}
+	return exitCode, nil
+}
+
+func createNamespace(ctx context.Context, clientset kubernetes.Interface) (string, error) {
+	namespaceObj := resourceread.ReadNamespaceV1OrDie(namespaceYaml)
+
+	actualNamespace, err := clientset.CoreV1().Namespaces().Create(ctx, namespaceObj, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("error creating namespace: %v", err)
+	}
+	return actualNamespace.Name, nil
+}
+
+func createServiceAccount(ctx context.Context, clientset kubernetes.Interface, namespace string) error {
+	serviceAccountObj := resourceread.ReadServiceAccountV1OrDie(serviceAccountYaml)
+	serviceAccountObj.Namespace = namespace
+
+	_, err := clientset.CoreV1().ServiceAccounts(namespace).Create(ctx, serviceAccountObj, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("error creating service account: %v", err)
+	}
+	return nil
+}
+
+// createRBACClusterAdmin binds the driver to cluster-admin, like the kubeconfig openshift-tests usually runs with.  The
+// binding is named after the namespace so that concurrent runs do not share it.
+func createRBACClusterAdmin(ctx context.Context, clientset kubernetes.Interface, namespace string) error {
+	rbacClusterAdminObj := resourceread.ReadClusterRoleBindingV1OrDie(rbacClusterAdminYaml)
+	rbacClusterAdminObj.Name = namespace
+	rbacClusterAdminObj.Subjects[0].Namespace = namespace
+
+	_, err := clientset.RbacV1().ClusterRoleBindings().Create(ctx, rbacClusterAdminObj, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("error creating cluster-admin CRB: %v", err)
+	}
+	return nil
+}
+
+// createInputConfigMap holds the input files and a kubeconfig for the service account of the driver.  The kubeconfig
+// refers to the mounted service account token so that it never contains a credential.
+func createInputConfigMap(ctx context.Context, clientset kubernetes.Interface, namespace, apiServerInternalURL string, inputFiles map[string][]byte) error {
+	kubeconfig, err := serviceAccountKubeconfig(apiServerInternalURL)
+	if err != nil {
+		return err
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: inputConfigMap, Namespace: namespace},
+		Data:       map[string]string{kubeconfigKey: string(kubeconfig)},
+	}
+	for name, data := range inputFiles {
+		if name == kubeconfigKey {
+			return fmt.Errorf("input file name %q is reserved", name)
+		}
+		configMap.Data[name] = string(data)
+	}
+
+	if _, err := clientset.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("error creating input configmap: %v", err)
+	}
+	return nil
+}
+
+func serviceAccountKubeconfig(apiServerInternalURL string) ([]byte, error) {
+	server := apiServerInternalURL
+	if len(server) == 0 {
+		server = "https://kubernetes.default.svc"
+	}
+	return clientcmd.Write(clientcmdapi.Config{
+		Clusters: map[string]*clientcmdapi.Cluster{
+			"cluster": {
+				Server:               server,
+				CertificateAuthority: filepath.Join(serviceAccountDir, "ca.crt"),
+			},
+		},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			"test-driver": {TokenFile: filepath.Join(serviceAccountDir, "token")},
+		},
+		Contexts: map[string]*clientcmdapi.Context{
+			"test-driver": {Cluster: "cluster", AuthInfo: "test-driver", Namespace: "default"},
+		},
+		CurrentContext: "test-driver",
+	})
+}
+
+func createJob(ctx context.Context, clientset kubernetes.Interface, namespace string, o Options) error {
+	jobObj := newJob(namespace, o)
+	if _, err := clientset.BatchV1().Jobs(namespace).Create(ctx, jobObj, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("error creating job: %v", err)
+	}
+	return nil
+}
+
+func newJob(namespace string, o Options) *batchv1.Job {
+	podObj := resourceread.ReadPodV1OrDie(podYaml)
+
+	image := o.Image
+	if len(image) == 0 {
+		image = DefaultImage
+	}
+	timeout := o.Timeout
+	if timeout == 0 {
+		timeout = 24 * time.Hour
+	}
+	for i := range podObj.Spec.Containers {
+		container := &podObj.Spec.Containers[i]
+		container.Image = image
+		if container.Name == testContainer {
+			container.Args = o.Args
+			container.Env = append(container.Env, o.Env...)
+		}
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: podObj.Name, Namespace: namespace},
+		Spec: batchv1.JobSpec{
+			// a failed run is reported, not retried
+			BackoffLimit:          pointer.Int32(0),
+			ActiveDeadlineSeconds: pointer.Int64(int64(timeout.Seconds())),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podObj.Labels},
+				Spec:       podObj.Spec,
+			},
+		},
+	}
+}
+
+func waitForTestContainerStart(ctx context.Context, clientset kubernetes.Interface, namespace string) (*corev1.Pod, error) {
+	podObj := resourceread.ReadPodV1OrDie(podYaml)
+	selector := metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: podObj.Labels})
+
+	var pod *corev1.Pod
+	err := wait.PollUntilContextTimeout(ctx, 5*time.Second, 30*time.Minute, true, func(ctx context.Context) (bool, error) {
+		pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil || len(pods.Items) == 0 {
+			return false, nil
+		}
+		pod = &pods.Items[0]
+		if pod.Status.Phase == corev1.PodFailed {
+			return false, fmt.Errorf("test driver pod %s failed: %s %s", pod.Name, pod.Status.Reason, pod.Status.Message)
+		}
+		status := containerStatus(pod, testContainer)
+		return status != nil && (status.State.Running != nil || status.State.Terminated != nil), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("test driver did not start: %w", err)
+	}
+	return pod, nil
+}
+
+func containerStatus(pod *corev1.Pod, name string) *corev1.ContainerStatus {
+	for i := range pod.Status.ContainerStatuses {
+		if pod.Status.ContainerStatuses[i].Name == name {
+			return &pod.Status.ContainerStatuses[i]
+		}
+	}
+	return nil
+}
+
+// followTestContainer streams the log of the test container until it exits and returns its exit code and the pod as
+// of then.  Log streams break when the apiserver or the node of the pod is disrupted, so the stream is resumed from the
+// timestamp of the last line until the container has exited.  A pod terminated by a drain of its node is followed
+// until the test container has written what it has, so that the partial artifacts can still be copied out.
+func followTestContainer(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod, out, errOut io.Writer) (int, *corev1.Pod, error) {
+	var lastLine time.Time
+	reportedTermination := false
+	for {
+		var err error
+		lastLine, err = streamLogs(ctx, clientset, pod, lastLine, out)
+		if err != nil && ctx.Err() == nil {
+			fmt.Fprintf(errOut, "Interrupted reading the test driver log, resuming: %v\n", err)
+		}
+
+		current, err := clientset.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		switch {
+		case ctx.Err() != nil:
+			return 0, nil, ctx.Err()
+		case apierrors.IsNotFound(err):
+			return 0, nil, fmt.Errorf("test driver pod %s was deleted before the tests finished, node %s may have been lost", pod.Name, pod.Spec.NodeName)
+		case err == nil:
+			if current.DeletionTimestamp != nil && !reportedTermination {
+				fmt.Fprintf(errOut, "Test driver pod %s is being terminated, node %s may be draining, the results will be partial\n", pod.Name, pod.Spec.NodeName)
+				reportedTermination = true
+			}
+			if status := containerStatus(current, testContainer); status != nil && status.State.Terminated != nil {
+				// one more pass picks up anything logged after the previous stream broke
+				if lastLine, err = streamLogs(ctx, clientset, pod, lastLine, out); err != nil {
+					fmt.Fprintf(errOut, "Unable to read the end of the test driver log: %v\n", err)
+				}
+				return int(status.State.Terminated.ExitCode), current, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, nil, ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// streamLogs writes the log lines of the test container after since to out and returns the timestamp of the last
+// line written.
+func streamLogs(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod, since time.Time, out io.Writer) (time.Time, error) {
+	logOptions := &corev1.PodLogOptions{Container: testContainer, Follow: true, Timestamps: true}
+	if !since.IsZero() {
+		// SinceTime has second granularity, lines already written are skipped below
+		logOptions.SinceTime = &metav1.Time{Time: since}
+	}
+	stream, err := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, logOptions).Stream(ctx)
+	if err != nil {
+		return since, err
+	}
+	defer stream.Close()
+
+	reader := bufio.NewReader(stream)
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			timestamp, text, ok := strings.Cut(line, " ")
+			lineTime, parseErr := time.Parse(time.RFC3339Nano, timestamp)
+			switch {
+			case !ok || parseErr != nil:
+				fmt.Fprint(out, line)
+			case lineTime.After(since):
+				fmt.Fprint(out, text)
+				since = lineTime
+			}
+		}
+		if err == io.EOF {
+			return since, nil
+		}
+		if err != nil {
+			return since, err
+		}
+	}
+}
+
+// copyArtifacts streams ArtifactDir out of the artifacts container as a tar archive and extracts the directories
+// in artifactDirs.
+func copyArtifacts(ctx context.Context, clientset kubernetes.Interface, config *rest.Config, pod *corev1.Pod, artifactDirs map[string]string) error {
+	req := clientset.CoreV1().RESTClient().Post().Resource("pods").Namespace(pod.Namespace).Name(pod.Name).SubResource("exec").VersionedParams(&corev1.PodExecOptions{
+		Container: artifactsContainer,
+		Command:   []string{"tar", "cf", "-", "-C", ArtifactDir, "."},
+		Stdout:    true,
+		Stderr:    true,
+	}, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+
+	reader, writer := io.Pipe()
+	stderr := &strings.Builder{}
+	go func() {
+		err := executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: writer, Stderr: stderr})
+		if err != nil {
+			err = fmt.Errorf("%w: %s", err, stderr.String())
+		}
+		writer.CloseWithError(err)
+	}()
+	defer reader.Close()
+
+	return extractArtifacts(tar.NewReader(reader), artifactDirs)
+}
+
+func extractArtifacts(archive *tar.Reader, artifactDirs map[string]string) error {
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := filepath.Clean(strings.TrimPrefix(header.Name, "./"))
+		dir, rest, _ := strings.Cut(filepath.ToSlash(name), "/")
+		localDir, ok := artifactDirs[dir]
+		if !ok {
+			continue
+		}
+		target := filepath.Join(localDir, filepath.FromSlash(rest))
+		if target != filepath.Clean(localDir) && !strings.HasPrefix(target, filepath.Clean(localDir)+string(filepath.Separator)) {
+			return fmt.Errorf("artifact %q is outside of %s", header.Name, dir)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, archive)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func deleteTestBed(ctx context.Context, clientset kubernetes.Interface, namespace string) error {
+	err := clientset.CoreV1().Namespaces().Delete(ctx, namespace, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("error removing namespace %s: %v", namespace, err)
+	}
+	err = clientset.RbacV1().ClusterRoleBindings().Delete(ctx, namespace, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("error removing cluster-admin CRB: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package inclusterdriver
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_newJob(t *testing.T) {
+	job := newJob("test-driver-abcde", Options{
+		Image:   "registry/tests:latest",
+		Args:    []string{"--junit-dir=/var/run/openshift-tests/artifacts/junit", "openshift/conformance"},
+		Timeout: time.Hour,
+	})
+	if job.Namespace != "test-driver-abcde" || *job.Spec.BackoffLimit != 0 || *job.Spec.ActiveDeadlineSeconds != 3600 {
+		t.Errorf("unexpected job: %#v", job.ObjectMeta)
+	}
+	// a drained driver needs time to write and copy out its partial results
+	if gracePeriod := job.Spec.Template.Spec.TerminationGracePeriodSeconds; gracePeriod == nil || *gracePeriod < 600 {
+		t.Errorf("unexpected termination grace period %v", gracePeriod)
+	}
+	containers := job.Spec.Template.Spec.Containers
+	if len(containers) != 2 {
+		t.Fatalf("expected the test and artifacts containers, got %d", len(containers))
+	}
+	for _, container := range containers {
+		if container.Image != "registry/tests:latest" {
+			t.Errorf("container %s has image %s", container.Name, container.Image)
+		}
+	}
+	if want := []string{"openshift-tests", "run"}; !reflect.DeepEqual(containers[0].Command, want) {
+		t.Errorf("command = %v, want %v", containers[0].Command, want)
+	}
+	if containers[0].Args[1] != "openshift/conformance" {
+		t.Errorf("unexpected args %v", containers[0].Args)
+	}
+}
+
+func Test_extractArtifacts(t *testing.T) {
+	buf := &bytes.Buffer{}
+	archive := tar.NewWriter(buf)
+	for _, file := range []struct {
+		name, contents string
+	}{
+		{name: "./junit/junit_e2e.xml", contents: "<testsuite/>"},
+		{name: "./junit/monitor/e2e-events.json", contents: "{}"},
+		{name: "./other/ignored.txt", contents: "ignored"},
+		{name: "./junit/../../escape.txt", contents: "escape"},
+	} {
+		if err := archive.WriteHeader(&tar.Header{Name: file.name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(file.contents))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := archive.Write([]byte(file.contents)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir := t.TempDir()
+	junitDir := filepath.Join(tmpDir, "junit")
+	if err := extractArtifacts(tar.NewReader(buf), map[string]string{"junit": junitDir}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "escape.txt")); !os.IsNotExist(err) {
+		t.Errorf("artifact outside of the directory was extracted: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "other")); !os.IsNotExist(err) {
+		t.Errorf("artifact in an unmapped directory was extracted: %v", err)
+	}
+	for name, want := range map[string]string{
+		"junit_e2e.xml":           "<testsuite/>",
+		"monitor/e2e-events.json": "{}",
+	} {
+		got, err := os.ReadFile(filepath.Join(junitDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
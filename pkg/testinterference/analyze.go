@@ -0,0 +1,268 @@
+package testinterference
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Options tune which pairs of tests are reported.
+type Options struct {
+	// MinCoFailures is the number of times a test must have failed while the suspect was running.
+	MinCoFailures int
+	// MaxPValue is the largest probability of the failures coinciding with the suspect by chance that is reported.
+	MaxPValue float64
+	// SerialThreshold is the number of tests a suspect must interfere with before it is suggested to be [Serial].
+	SerialThreshold int
+}
+
+func NewOptions() Options {
+	return Options{
+		MinCoFailures:   2,
+		MaxPValue:       0.01,
+		SerialThreshold: 3,
+	}
+}
+
+// Validate rejects options that cannot rank pairs.  The lift of a pair divides by the failures of the test, so a
+// MinCoFailures below 1 would rank tests that never failed.
+func (o Options) Validate() error {
+	if o.MinCoFailures < 1 {
+		return fmt.Errorf("the minimum number of co-failures must be at least 1, got %d", o.MinCoFailures)
+	}
+	return nil
+}
+
+// SuspectPair is a test that fails more often while the suspect runs concurrently than it does otherwise.
+type SuspectPair struct {
+	Test    string `json:"test"`
+	Suspect string `json:"suspect"`
+
+	// FailuresWithSuspect and PassesWithSuspect count the executions of the test that overlapped the suspect.
+	FailuresWithSuspect int `json:"failuresWithSuspect"`
+	PassesWithSuspect   int `json:"passesWithSuspect"`
+	// FailuresWithoutSuspect and PassesWithoutSuspect count the executions of the test that did not.
+	FailuresWithoutSuspect int `json:"failuresWithoutSuspect"`
+	PassesWithoutSuspect   int `json:"passesWithoutSuspect"`
+
+	// Lift is how much more often the suspect was running during failures than during any execution of the test.
+	Lift float64 `json:"lift"`
+	// PValue is the probability of at least as many failures overlapping the suspect if failures were unrelated to
+	// it, from a one-sided Fisher's exact test.  It is not corrected for the number of pairs tested, so it is best
+	// used to rank pairs.
+	PValue float64 `json:"pValue"`
+}
+
+// Suggestion is an annotation that would stop a test from running alongside the tests it interferes with.
+type Suggestion struct {
+	Test       string `json:"test"`
+	Annotation string `json:"annotation"`
+	Reason     string `json:"reason"`
+}
+
+type Report struct {
+	JobRuns     int           `json:"jobRuns"`
+	Pairs       []SuspectPair `json:"pairs"`
+	Suggestions []Suggestion  `json:"suggestions,omitempty"`
+}
+
+// pairCounts holds the executions of a test that overlapped a suspect.
+type pairCounts struct {
+	failures int
+	passes   int
+}
+
+type testCounts struct {
+	failures int
+	passes   int
+	// overlaps is keyed by suspect.
+	overlaps map[string]*pairCounts
+}
+
+// Analyze finds, for every test that failed, the tests that were running concurrently with its failures more often
+// than with its passes.  Pairs are ranked by how unlikely that is to be chance.
+func Analyze(runs []JobRun, o Options) *Report {
+	counts := map[string]*testCounts{}
+	failedTests := sets.New[string]()
+	for _, run := range runs {
+		for _, execution := range run.Executions {
+			if execution.failed() {
+				failedTests.Insert(execution.Name)
+			}
+		}
+	}
+
+	for _, run := range runs {
+		// executions are sorted by start, so only the ones that started before an execution ended can overlap it
+		executions := run.Executions
+		for _, execution := range executions {
+			if !failedTests.Has(execution.Name) || !(execution.failed() || execution.passed()) {
+				continue
+			}
+			c, ok := counts[execution.Name]
+			if !ok {
+				c = &testCounts{overlaps: map[string]*pairCounts{}}
+				counts[execution.Name] = c
+			}
+			if execution.failed() {
+				c.failures++
+			} else {
+				c.passes++
+			}
+
+			concurrent := sets.New[string]()
+			for _, other := range executions {
+				if !other.From.Before(execution.To) {
+					break
+				}
+				if other.Name != execution.Name && execution.overlaps(other) {
+					concurrent.Insert(other.Name)
+				}
+			}
+			for suspect := range concurrent {
+				p, ok := c.overlaps[suspect]
+				if !ok {
+					p = &pairCounts{}
+					c.overlaps[suspect] = p
+				}
+				if execution.failed() {
+					p.failures++
+				} else {
+					p.passes++
+				}
+			}
+		}
+	}
+
+	report := &Report{JobRuns: len(runs)}
+	for test, c := range counts {
+		total := c.failures + c.passes
+		for suspect, p := range c.overlaps {
+			if p.failures < o.MinCoFailures {
+				continue
+			}
+			overlapping := p.failures + p.passes
+			// the share of failures the suspect overlapped, against the share of all executions it overlapped
+			lift := (float64(p.failures) / float64(c.failures)) / (float64(overlapping) / float64(total))
+			if lift <= 1 {
+				continue
+			}
+			pValue := hypergeometricTail(total, overlapping, c.failures, p.failures)
+			if pValue > o.MaxPValue {
+				continue
+			}
+			report.Pairs = append(report.Pairs, SuspectPair{
+				Test:                   test,
+				Suspect:                suspect,
+				FailuresWithSuspect:    p.failures,
+				PassesWithSuspect:      p.passes,
+				FailuresWithoutSuspect: c.failures - p.failures,
+				PassesWithoutSuspect:   c.passes - p.passes,
+				Lift:                   lift,
+				PValue:                 pValue,
+			})
+		}
+	}
+	sort.Slice(report.Pairs, func(i, j int) bool {
+		a, b := report.Pairs[i], report.Pairs[j]
+		switch {
+		case a.PValue != b.PValue:
+			return a.PValue < b.PValue
+		case a.Lift != b.Lift:
+			return a.Lift > b.Lift
+		case a.Test != b.Test:
+			return a.Test < b.Test
+		}
+		return a.Suspect < b.Suspect
+	})
+	report.Suggestions = suggest(report.Pairs, o.SerialThreshold)
+	return report
+}
+
+// suggest proposes [Serial] for suspects that interfere with many tests, because they likely disrupt the cluster,
+// and for tests that are only sensitive to a few others, so that they run in isolation.
+func suggest(pairs []SuspectPair, serialThreshold int) []Suggestion {
+	victimsBySuspect := map[string]sets.Set[string]{}
+	for _, pair := range pairs {
+		if _, ok := victimsBySuspect[pair.Suspect]; !ok {
+			victimsBySuspect[pair.Suspect] = sets.New[string]()
+		}
+		victimsBySuspect[pair.Suspect].Insert(pair.Test)
+	}
+
+	var suggestions []Suggestion
+	suggested := sets.New[string]()
+	for _, suspect := range sets.List(sets.KeySet(victimsBySuspect)) {
+		victims := victimsBySuspect[suspect]
+		if victims.Len() < serialThreshold || strings.Contains(suspect, "[Serial]") {
+			continue
+		}
+		suggested.Insert(suspect)
+		suggestions = append(suggestions, Suggestion{
+			Test:       suspect,
+			Annotation: "[Serial]",
+			Reason:     fmt.Sprintf("%d tests fail more often while it runs", victims.Len()),
+		})
+	}
+	for _, pair := range pairs {
+		if suggested.Has(pair.Suspect) || suggested.Has(pair.Test) || strings.Contains(pair.Test, "[Serial]") {
+			continue
+		}
+		suggested.Insert(pair.Test)
+		suggestions = append(suggestions, Suggestion{
+			Test:       pair.Test,
+			Annotation: "[Serial]",
+			Reason:     fmt.Sprintf("isolate it from %q, it fails more often while that test runs", pair.Suspect),
+		})
+	}
+	return suggestions
+}
+
+// hypergeometricTail returns the probability that at least successes of draws items picked at random from total items
+// are among the marked ones.
+func hypergeometricTail(total, marked, draws, successes int) float64 {
+	p := 0.0
+	for k := successes; k <= marked && k <= draws; k++ {
+		if draws-k > total-marked {
+			continue
+		}
+		p += math.Exp(logChoose(marked, k) + logChoose(total-marked, draws-k) - logChoose(total, draws))
+	}
+	return math.Min(p, 1)
+}
+
+func logChoose(n, k int) float64 {
+	a, _ := math.Lgamma(float64(n + 1))
+	b, _ := math.Lgamma(float64(k + 1))
+	c, _ := math.Lgamma(float64(n - k + 1))
+	return a - b - c
+}
+
+// Print writes the ranked pairs and the suggestions as tables.
+func (r *Report) Print(out io.Writer) error {
+	fmt.Fprintf(out, "Analyzed %d job runs, found %d suspect pairs\n\n", r.JobRuns, len(r.Pairs))
+	if len(r.Pairs) > 0 {
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "RANK\tP-VALUE\tLIFT\tFAILED WITH\tPASSED WITH\tFAILED WITHOUT\tPASSED WITHOUT\tTEST\tSUSPECT")
+		for i, pair := range r.Pairs {
+			fmt.Fprintf(w, "%d\t%.2g\t%.1f\t%d\t%d\t%d\t%d\t%s\t%s\n", i+1, pair.PValue, pair.Lift,
+				pair.FailuresWithSuspect, pair.PassesWithSuspect, pair.FailuresWithoutSuspect, pair.PassesWithoutSuspect,
+				pair.Test, pair.Suspect)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	if len(r.Suggestions) > 0 {
+		fmt.Fprintf(out, "\nSuggested annotations:\n\n")
+		for _, suggestion := range r.Suggestions {
+			fmt.Fprintf(out, "%s %q: %s\n", suggestion.Annotation, suggestion.Test, suggestion.Reason)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package testinterference
+
+import (
+	"math"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+)
+
+func testIntervals(start time.Time, name, status string, from, to time.Duration) monitorapi.Intervals {
+	locator := monitorapi.NewLocator().E2ETest(name)
+	return monitorapi.Intervals{
+		monitorapi.NewInterval(monitorapi.SourceE2ETest, monitorapi.Info).Locator(locator).
+			Message(monitorapi.NewMessage().HumanMessage("started").Reason(monitorapi.E2ETestStarted)).
+			Build(start.Add(from), start.Add(from)),
+		monitorapi.NewInterval(monitorapi.SourceE2ETest, monitorapi.Info).Locator(locator).
+			Message(monitorapi.NewMessage().HumanMessage("finished").Reason(monitorapi.E2ETestFinished).
+				WithAnnotation(monitorapi.AnnotationStatus, status)).
+			Build(start.Add(to), start.Add(to)),
+	}
+}
+
+// jobRuns returns runs where victim fails whenever disruptor runs alongside it, and bystander always runs alongside it.
+func jobRuns() []JobRun {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var runs []JobRun
+	for i := 0; i < 10; i++ {
+		var intervals monitorapi.Intervals
+		intervals = append(intervals, testIntervals(start, "bystander", "Passed", 0, 10*time.Minute)...)
+		if i < 5 {
+			intervals = append(intervals, testIntervals(start, "victim", "Failed", time.Minute, 3*time.Minute)...)
+			intervals = append(intervals, testIntervals(start, "disruptor", "Passed", 2*time.Minute, 4*time.Minute)...)
+		} else {
+			intervals = append(intervals, testIntervals(start, "victim", "Passed", time.Minute, 3*time.Minute)...)
+			intervals = append(intervals, testIntervals(start, "disruptor", "Passed", 5*time.Minute, 6*time.Minute)...)
+		}
+		runs = append(runs, JobRun{Executions: ExecutionsFromIntervals(intervals)})
+	}
+	return runs
+}
+
+func TestAnalyze(t *testing.T) {
+	report := Analyze(jobRuns(), NewOptions())
+	if len(report.Pairs) != 1 {
+		t.Fatalf("expected only the disruptor to be suspected, got %#v", report.Pairs)
+	}
+	pair := report.Pairs[0]
+	if pair.Test != "victim" || pair.Suspect != "disruptor" {
+		t.Errorf("unexpected pair %#v", pair)
+	}
+	if pair.FailuresWithSuspect != 5 || pair.PassesWithSuspect != 0 || pair.FailuresWithoutSuspect != 0 || pair.PassesWithoutSuspect != 5 {
+		t.Errorf("unexpected counts %#v", pair)
+	}
+	if pair.Lift != 2 {
+		t.Errorf("got lift %v, want 2", pair.Lift)
+	}
+	// all five failures overlapping the five executions of the disruptor has one chance in C(10, 5)
+	if want := 1.0 / 252; math.Abs(pair.PValue-want) > 1e-9 {
+		t.Errorf("got p-value %v, want %v", pair.PValue, want)
+	}
+	if want := []Suggestion{{Test: "victim", Annotation: "[Serial]", Reason: `isolate it from "disruptor", it fails more often while that test runs`}}; !reflect.DeepEqual(report.Suggestions, want) {
+		t.Errorf("got suggestions %#v", report.Suggestions)
+	}
+
+	o := NewOptions()
+	if err := o.Validate(); err != nil {
+		t.Errorf("default options must be valid: %v", err)
+	}
+	o.MinCoFailures = 0
+	if err := o.Validate(); err == nil {
+		t.Errorf("expected a minimum of 0 co-failures to be rejected")
+	}
+	o.MinCoFailures = 6
+	if report := Analyze(jobRuns(), o); len(report.Pairs) != 0 {
+		t.Errorf("expected no pairs with fewer co-failures than required, got %#v", report.Pairs)
+	}
+}
+
+func Test_suggest(t *testing.T) {
+	pairs := []SuspectPair{
+		{Test: "a", Suspect: "noisy"},
+		{Test: "b", Suspect: "noisy"},
+		{Test: "c", Suspect: "noisy"},
+		{Test: "c", Suspect: "other"},
+		{Test: "d", Suspect: "other"},
+	}
+	var got []string
+	for _, suggestion := range suggest(pairs, 3) {
+		got = append(got, suggestion.Test)
+	}
+	if want := []string{"noisy", "c", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got suggestions for %v, want %v", got, want)
+	}
+}
+
+func TestLoadJobRuns(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	intervals := append(testIntervals(start, "a", "Failed", 0, time.Minute), testIntervals(start, "b", "Passed", 0, time.Minute)...)
+	for _, file := range []string{"e2e-events_20240101-000000.json", "events_used_for_junits_20240101-000000.json"} {
+		if err := monitorserialization.EventsToFile(filepath.Join(dir, file), intervals); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runs, err := LoadJobRuns([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("expected one run, got %d", len(runs))
+	}
+	want := []TestExecution{
+		{Name: "a", Status: "Failed", From: start, To: start.Add(time.Minute)},
+		{Name: "b", Status: "Passed", From: start, To: start.Add(time.Minute)},
+	}
+	got := runs[0].Executions
+	if len(got) != len(want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+	for i := range want {
+		if got[i].Name != want[i].Name || got[i].Status != want[i].Status || !got[i].From.Equal(want[i].From) || !got[i].To.Equal(want[i].To) {
+			t.Errorf("got %#v, want %#v", got[i], want[i])
+		}
+	}
+}
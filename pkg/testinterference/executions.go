@@ -0,0 +1,109 @@
+package testinterference
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+)
+
+// TestExecution is a single run of a test.
+type TestExecution struct {
+	Name string
+	// Status is the status annotation of the E2ETestFinished interval: Passed, Failed, Flaked, Skipped or Unknown.
+	Status string
+	From   time.Time
+	To     time.Time
+}
+
+func (e TestExecution) failed() bool {
+	return e.Status == "Failed" || e.Status == "Flaked"
+}
+
+func (e TestExecution) passed() bool {
+	return e.Status == "Passed"
+}
+
+func (e TestExecution) overlaps(other TestExecution) bool {
+	return e.From.Before(other.To) && other.From.Before(e.To)
+}
+
+// JobRun holds the test executions of a single openshift-tests run.
+type JobRun struct {
+	// Name is the intervals file the run was read from.
+	Name       string
+	Executions []TestExecution
+}
+
+// ExecutionsFromIntervals pairs the E2ETestStarted and E2ETestFinished intervals of each test.  Tests that did not
+// finish are left out.
+func ExecutionsFromIntervals(intervals monitorapi.Intervals) []TestExecution {
+	var executions []TestExecution
+	lastStart := map[string]time.Time{}
+	for _, interval := range intervals {
+		if interval.Source != monitorapi.SourceE2ETest {
+			continue
+		}
+		name, ok := monitorapi.E2ETestFromLocator(interval.Locator)
+		if !ok {
+			continue
+		}
+		switch interval.Message.Reason {
+		case monitorapi.E2ETestStarted:
+			lastStart[name] = interval.From
+		case monitorapi.E2ETestFinished:
+			start, ok := lastStart[name]
+			if !ok {
+				continue
+			}
+			delete(lastStart, name)
+			executions = append(executions, TestExecution{
+				Name:   name,
+				Status: interval.Message.Annotations[monitorapi.AnnotationStatus],
+				From:   start,
+				To:     interval.From,
+			})
+		}
+	}
+	sort.SliceStable(executions, func(i, j int) bool { return executions[i].From.Before(executions[j].From) })
+	return executions
+}
+
+// isIntervalsFile matches the e2e-events_<timestamp>.json files openshift-tests writes to its junit directory.
+func isIntervalsFile(path string) bool {
+	name := filepath.Base(path)
+	return strings.HasPrefix(name, "e2e-events") && strings.HasSuffix(name, ".json")
+}
+
+// LoadJobRuns reads every intervals file below the directories.  Each file is a job run.
+func LoadJobRuns(dirs []string) ([]JobRun, error) {
+	var runs []JobRun
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !isIntervalsFile(path) {
+				return nil
+			}
+			intervals, err := monitorserialization.EventsFromFile(path)
+			if err != nil {
+				return err
+			}
+			executions := ExecutionsFromIntervals(intervals)
+			if len(executions) == 0 {
+				return nil
+			}
+			runs = append(runs, JobRun{Name: path, Executions: executions})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return runs, nil
+}
@@ -0,0 +1,210 @@
+package testsuites
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/openshift/origin/pkg/test/ginkgo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+// TestSuiteFile is the serialized form of test suites defined outside of this binary.
+type TestSuiteFile struct {
+	Suites []TestSuiteDefinition `json:"suites"`
+}
+
+// TestSuiteDefinition mirrors the fields of ginkgo.TestSuite, with selectors in place of the Matches function.
+type TestSuiteDefinition struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// Include selects the tests of the suite.  A test is included if it matches any of the selectors.
+	Include []TestSelector `json:"include"`
+	// Exclude removes tests matching any of the selectors from the suite.
+	Exclude []TestSelector `json:"exclude,omitempty"`
+
+	Parallelism          int             `json:"parallelism,omitempty"`
+	TestTimeout          metav1.Duration `json:"testTimeout,omitempty"`
+	MaximumAllowedFlakes int             `json:"maximumAllowedFlakes,omitempty"`
+	Count                int             `json:"count,omitempty"`
+	// ClusterStability is Stable or Disruptive, empty is treated as Stable.
+	ClusterStability ginkgo.ClusterStabilityDuringTest `json:"clusterStability,omitempty"`
+
+	// ExactMonitorTests are the only monitor tests to enable.  Empty enables the defaults.
+	ExactMonitorTests []string `json:"exactMonitorTests,omitempty"`
+	// DisableMonitorTests are monitor tests to disable.
+	DisableMonitorTests []string `json:"disableMonitorTests,omitempty"`
+}
+
+// TestSelector matches a test if every field that is set matches.
+type TestSelector struct {
+	// Suite is the name of a suite built into this binary.
+	Suite string `json:"suite,omitempty"`
+	// Labels is a label filter expression, like the value of --label-filter.
+	Labels string `json:"labels,omitempty"`
+	// NameRegex is a regular expression matched against the test name.
+	NameRegex string `json:"nameRegex,omitempty"`
+}
+
+// TestSuitesFromFile reads a TestSuiteFile from disk.  Selectors may refer to builtInSuites by name.
+func TestSuitesFromFile(filename string, builtInSuites []*ginkgo.TestSuite) ([]*ginkgo.TestSuite, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	suites, err := TestSuitesFromYAML(data, builtInSuites)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return suites, nil
+}
+
+// TestSuitesFromYAML parses a TestSuiteFile. Unknown fields are rejected so that typos do not silently change
+// which tests run.
+func TestSuitesFromYAML(data []byte, builtInSuites []*ginkgo.TestSuite) ([]*ginkgo.TestSuite, error) {
+	file := &TestSuiteFile{}
+	if err := yaml.UnmarshalStrict(data, file); err != nil {
+		return nil, err
+	}
+
+	builtInSuitesByName := map[string]*ginkgo.TestSuite{}
+	for _, suite := range builtInSuites {
+		builtInSuitesByName[suite.Name] = suite
+	}
+
+	names := sets.New[string]()
+	var suites []*ginkgo.TestSuite
+	for _, definition := range file.Suites {
+		if len(definition.Name) == 0 {
+			return nil, fmt.Errorf("suite without a name")
+		}
+		if names.Has(definition.Name) {
+			return nil, fmt.Errorf("suite %q is defined more than once", definition.Name)
+		}
+		if _, ok := builtInSuitesByName[definition.Name]; ok {
+			return nil, fmt.Errorf("suite %q is already built in", definition.Name)
+		}
+		names.Insert(definition.Name)
+
+		suite, err := definition.toTestSuite(builtInSuitesByName)
+		if err != nil {
+			return nil, fmt.Errorf("suite %q: %w", definition.Name, err)
+		}
+		suites = append(suites, suite)
+	}
+	return suites, nil
+}
+
+func (d TestSuiteDefinition) toTestSuite(builtInSuitesByName map[string]*ginkgo.TestSuite) (*ginkgo.TestSuite, error) {
+	if len(d.Include) == 0 {
+		return nil, fmt.Errorf("include must select at least one test")
+	}
+	switch d.ClusterStability {
+	case "", ginkgo.Stable, ginkgo.Disruptive:
+	default:
+		return nil, fmt.Errorf("unknown clusterStability %q, expected Stable or Disruptive", d.ClusterStability)
+	}
+	if d.Parallelism < 0 || d.MaximumAllowedFlakes < 0 || d.TestTimeout.Duration < 0 {
+		return nil, fmt.Errorf("parallelism, maximumAllowedFlakes and testTimeout must not be negative")
+	}
+
+	var include, exclude []ginkgo.TestLabelMatchFunc
+	for i, selector := range d.Include {
+		matchFn, err := selector.toMatchFunc(builtInSuitesByName)
+		if err != nil {
+			return nil, fmt.Errorf("include[%d]: %w", i, err)
+		}
+		include = append(include, matchFn)
+	}
+	for i, selector := range d.Exclude {
+		matchFn, err := selector.toMatchFunc(builtInSuitesByName)
+		if err != nil {
+			return nil, fmt.Errorf("exclude[%d]: %w", i, err)
+		}
+		exclude = append(exclude, matchFn)
+	}
+
+	// Selectors match the labels of a test, which include those reported by external binaries, so the suite
+	// selects tests with MatchesLabels.  Matches only has the name and uses the labels found in it.
+	matches := func(name string, labels sets.Set[string]) bool {
+		if isDisabled(name) {
+			return false
+		}
+		for _, matchFn := range exclude {
+			if matchFn(name, labels) {
+				return false
+			}
+		}
+		for _, matchFn := range include {
+			if matchFn(name, labels) {
+				return true
+			}
+		}
+		return false
+	}
+	return &ginkgo.TestSuite{
+		Name:        d.Name,
+		Description: d.Description,
+		Matches: func(name string) bool {
+			return matches(name, ginkgo.LabelsFromTestName(name))
+		},
+		MatchesLabels:              matches,
+		Count:                      d.Count,
+		Parallelism:                d.Parallelism,
+		MaximumAllowedFlakes:       d.MaximumAllowedFlakes,
+		ClusterStabilityDuringTest: d.ClusterStability,
+		TestTimeout:                d.TestTimeout.Duration,
+		ExactMonitorTests:          d.ExactMonitorTests,
+		DisableMonitorTests:        d.DisableMonitorTests,
+	}, nil
+}
+
+func (s TestSelector) toMatchFunc(builtInSuitesByName map[string]*ginkgo.TestSuite) (ginkgo.TestLabelMatchFunc, error) {
+	var matchFns []ginkgo.TestLabelMatchFunc
+	if len(s.Suite) > 0 {
+		suite, ok := builtInSuitesByName[s.Suite]
+		if !ok {
+			return nil, fmt.Errorf("unknown suite %q", s.Suite)
+		}
+		if suite.MatchesLabels != nil {
+			matchFns = append(matchFns, suite.MatchesLabels)
+		} else {
+			matchFns = append(matchFns, func(name string, _ sets.Set[string]) bool {
+				return suite.Matches(name)
+			})
+		}
+	}
+	if len(s.Labels) > 0 {
+		labelFilter, err := ginkgo.ParseLabelFilter(s.Labels)
+		if err != nil {
+			return nil, err
+		}
+		matchFns = append(matchFns, func(_ string, labels sets.Set[string]) bool {
+			return labelFilter.Matches(labels)
+		})
+	}
+	if len(s.NameRegex) > 0 {
+		re, err := regexp.Compile(s.NameRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid nameRegex: %w", err)
+		}
+		matchFns = append(matchFns, func(name string, _ sets.Set[string]) bool {
+			return re.MatchString(name)
+		})
+	}
+	if len(matchFns) == 0 {
+		return nil, fmt.Errorf("selector must set at least one of suite, labels or nameRegex")
+	}
+
+	return func(name string, labels sets.Set[string]) bool {
+		for _, matchFn := range matchFns {
+			if !matchFn(name, labels) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
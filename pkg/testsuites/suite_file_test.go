@@ -0,0 +1,101 @@
+package testsuites
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/test/ginkgo"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestTestSuitesFromYAML(t *testing.T) {
+	builtIn := []*ginkgo.TestSuite{
+		{
+			Name: "openshift/conformance/parallel",
+			Matches: func(name string) bool {
+				return strings.Contains(name, "parallel only")
+			},
+		},
+	}
+
+	suites, err := TestSuitesFromYAML([]byte(`
+suites:
+- name: example/network
+  description: networking tests
+  include:
+  - suite: openshift/conformance/parallel
+    labels: sig-network
+  - nameRegex: "^custom "
+  exclude:
+  - labels: Slow
+  parallelism: 3
+  testTimeout: 10m
+  disableMonitorTests:
+  - pod-network-avalibility
+`), builtIn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suites) != 1 {
+		t.Fatalf("expected one suite, got %d", len(suites))
+	}
+	suite := suites[0]
+	if suite.Name != "example/network" || suite.Description != "networking tests" {
+		t.Errorf("unexpected name or description: %q %q", suite.Name, suite.Description)
+	}
+	if suite.Parallelism != 3 || suite.TestTimeout != 10*time.Minute {
+		t.Errorf("unexpected parallelism or timeout: %d %v", suite.Parallelism, suite.TestTimeout)
+	}
+	if len(suite.DisableMonitorTests) != 1 || suite.DisableMonitorTests[0] != "pod-network-avalibility" {
+		t.Errorf("unexpected disabled monitor tests: %v", suite.DisableMonitorTests)
+	}
+
+	for name, want := range map[string]bool{
+		"[sig-network] parallel only":            true,
+		"[sig-network] not in the built-in":      false,
+		"custom test":                            true,
+		"custom test [Slow]":                     false,
+		"custom test [Disabled:Broken]":          false,
+		"[sig-storage] custom test not anchored": false,
+	} {
+		if got := suite.Matches(name); got != want {
+			t.Errorf("Matches(%q) = %v, want %v", name, got, want)
+		}
+	}
+
+	// labels reported by an external binary are not part of the test name.
+	for name, labels := range map[string][]string{
+		"external parallel only": {"sig-network"},
+		"custom external test":   {"Slow"},
+	} {
+		want := name == "external parallel only"
+		if got := suite.MatchesLabels(name, sets.New(labels...)); got != want {
+			t.Errorf("MatchesLabels(%q, %v) = %v, want %v", name, labels, got, want)
+		}
+	}
+}
+
+func TestTestSuitesFromYAMLErrors(t *testing.T) {
+	builtIn := []*ginkgo.TestSuite{{Name: "openshift/conformance/parallel", Matches: func(string) bool { return true }}}
+	tests := map[string]string{
+		"unknown field":        "suites:\n- name: a\n  includes:\n  - labels: Slow\n",
+		"missing name":         "suites:\n- include:\n  - labels: Slow\n",
+		"duplicate name":       "suites:\n- name: a\n  include:\n  - labels: Slow\n- name: a\n  include:\n  - labels: Slow\n",
+		"built in name":        "suites:\n- name: openshift/conformance/parallel\n  include:\n  - labels: Slow\n",
+		"missing include":      "suites:\n- name: a\n",
+		"empty selector":       "suites:\n- name: a\n  include:\n  - {}\n",
+		"unknown suite":        "suites:\n- name: a\n  include:\n  - suite: missing\n",
+		"invalid labels":       "suites:\n- name: a\n  include:\n  - labels: 'Slow &&'\n",
+		"invalid regex":        "suites:\n- name: a\n  include:\n  - nameRegex: '('\n",
+		"invalid stability":    "suites:\n- name: a\n  clusterStability: Sometimes\n  include:\n  - labels: Slow\n",
+		"negative parallelism": "suites:\n- name: a\n  parallelism: -1\n  include:\n  - labels: Slow\n",
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := TestSuitesFromYAML([]byte(data), builtIn); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
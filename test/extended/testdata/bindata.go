@@ -53299,10 +53299,18 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
         return eventInterval.source === "NodeState"
     }
 
+    function isAPIServerBrownout(eventInterval) {
+        return eventInterval.source === "AuditLog";
+    }
+
     function isCloudMetrics(eventInterval) {
         return eventInterval.source === "CloudMetrics";
     }
 
+    function isEtcdMetrics(eventInterval) {
+        return eventInterval.source === "EtcdMetrics";
+    }
+
     function isAlert(eventInterval) {
         return eventInterval.source === "Alert"
     }
@@ -53370,10 +53378,21 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
         return [` + "`" + `node/${nodeVal} ${etcdMemberVal} term/${term}` + "`" + `, ` + "`" + ` ${reason}` + "`" + `, color ]
     }
 
+    function apiserverBrownoutValue(item) {
+        if (item.message.reason === "APIServerErrorBurst") {
+            return [buildLocatorDisplayString(item.locator), "", "APIServerErrorBurst"];
+        }
+        return [buildLocatorDisplayString(item.locator), "", "APIServerSlowRequests"];
+    }
+
     function cloudMetricsValue(item) {
         return [buildLocatorDisplayString(item.locator), "", "CloudMetric"];
     }
 
+    function etcdMetricsValue(item) {
+        return [buildLocatorDisplayString(item.locator), ` + "`" + ` reason/${item.message.reason}` + "`" + `, item.message.reason];
+    }
+
     function alertSeverity(item) {
         // the other types can be pending, so check pending first
         if (item.message.annotations["alertstate"] === "pending") {
@@ -53616,9 +53635,15 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
         timelineGroups.push({group: "apiserver-shutdown", data: []})
         createTimelineData(apiserverShutdownValue, timelineGroups[timelineGroups.length - 1].data, eventIntervals, isGracefulShutdownActivity, regex)
 
+        timelineGroups.push({group: "apiserver-brownouts", data: []})
+        createTimelineData(apiserverBrownoutValue, timelineGroups[timelineGroups.length - 1].data, eventIntervals, isAPIServerBrownout, regex)
+
         timelineGroups.push({ group: "etcd-leaders", data: [] })
         createTimelineData(etcdLeadershipLogsValue, timelineGroups[timelineGroups.length - 1].data, eventIntervals, isEtcdLeadershipAndNotEmpty, regex)
 
+        timelineGroups.push({group: "etcd-metrics", data: []})
+        createTimelineData(etcdMetricsValue, timelineGroups[timelineGroups.length - 1].data, eventIntervals, isEtcdMetrics, regex)
+
         timelineGroups.push({group: "cloud-metrics", data: []})
         createTimelineData(cloudMetricsValue, timelineGroups[timelineGroups.length - 1].data, eventIntervals, isCloudMetrics, regex)
 
@@ -53685,9 +53710,11 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
                 'Passed', 'Skipped', 'Flaked', 'Failed',  // tests
                 'PodCreated', 'PodScheduled', 'PodTerminating','ContainerWait', 'ContainerStart', 'ContainerNotReady', 'ContainerReady', 'ContainerReadinessFailed', 'ContainerReadinessErrored',  'StartupProbeFailed', // pods
                 'CIClusterDisruption', 'Disruption', // disruption
+                'APIServerSlowRequests', 'APIServerErrorBurst', // apiserver brownouts
                 'Degraded', 'Upgradeable', 'False', 'Unknown',
                 'PodLogInfo', 'PodLogWarning', 'PodLogError',
-                'EtcdOther', 'EtcdLeaderFound', 'EtcdLeaderLost', 'EtcdLeaderElected', 'EtcdLeaderMissing'])
+                'EtcdOther', 'EtcdLeaderFound', 'EtcdLeaderLost', 'EtcdLeaderElected', 'EtcdLeaderMissing',
+                'EtcdSlowWALFsync', 'EtcdSlowBackendCommit', 'EtcdSlowPeerRoundTrip', 'EtcdProposalsFailed', 'EtcdDatabaseFragmented', 'EtcdDatabaseNearQuota'])
             .range([
                 '#6E6E6E', '#0000ff', '#d0312d', '#ffa500', // pathological and interesting events
                 '#fada5e','#fada5e','#ffa500', '#d0312d',  // alerts
@@ -53696,9 +53723,11 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
                 '#3cb043', '#ceba76', '#ffa500', '#d0312d', // tests
                 '#96cbff', '#1e7bd9', '#ffa500', '#ca8dfd', '#9300ff', '#fada5e','#3cb043', '#d0312d', '#d0312d', '#c90076', // pods
                 '#96cbff', '#d0312d', // disruption
+                '#fada5e', '#ffa500', // apiserver brownouts
                 '#b65049', '#32b8b6', '#ffffff', '#bbbbbb',
                 '#96cbff', '#fada5e', '#d0312d',
-                '#d3d3de', '#03fc62', '#fc0303', '#fada5e', '#8c5efa']); // EtcdLeadership
+                '#d3d3de', '#03fc62', '#fc0303', '#fada5e', '#8c5efa', // EtcdLeadership
+                '#ffa500', '#fada5e', '#ca8dfd', '#d0312d', '#96cbff', '#c90076']); // EtcdMetrics
         myChart.
         data(timelineGroups).
         useUtc(true).
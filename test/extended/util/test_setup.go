@@ -57,6 +57,8 @@ func InitTest(dryRun bool) error {
 	InitDefaultEnvironmentVariables()
 
 	TestContext.DeleteNamespace = os.Getenv("DELETE_NAMESPACE") != "false"
+	// the run command deletes the namespaces of failed tests after capturing them
+	TestContext.DeleteNamespaceOnFailure = os.Getenv("DELETE_NAMESPACE_ON_FAILURE") != "false"
 	TestContext.VerifyServiceAccount = true
 	testfiles.AddFileSource(e2etestingmanifests.GetE2ETestingManifestsFS())
 	testfiles.AddFileSource(testfixtures.GetTestFixturesFS())
@@ -88,7 +90,12 @@ func InitTest(dryRun bool) error {
 		// 2. all k8s tests (based on testfile location), which don't have specific wording in their name (see skipTestNamespaceCustomization)
 		isKubeNamespace := upgradeFilter.MatchString(baseName) || // 1.
 			(isGoModulePath(ginkgo.CurrentSpecReport().FileName(), "k8s.io/kubernetes", "test/e2e") && !skipTestNamespaceCustomization()) // 2.
-		return e2e.CreateTestingNS(ctx, baseName, c, labels, isKubeNamespace)
+		ns, err := e2e.CreateTestingNS(ctx, baseName, c, labels, isKubeNamespace)
+		if err == nil {
+			// namespace names are generated by the server, so report them for the run command
+			framework.Logf("Created test namespace %q", ns.Name)
+		}
+		return ns, err
 	}
 
 	klog.V(2).Infof("Extended test version %s", version.Get().String())